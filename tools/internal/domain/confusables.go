@@ -0,0 +1,211 @@
+package domain
+
+//go:generate go run update_confusables.go
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Skeleton returns the confusable skeleton of the label, as defined
+// in Unicode Technical Standard #39 section 4.
+//
+// Two labels with equal skeletons look the same (or nearly the same)
+// to a human, even if they are different labels according to
+// IDNA2008. For example, "apple" and "аpple" (with a Cyrillic "а")
+// have the same skeleton.
+//
+// Skeletons are only useful for comparison with other skeletons. They
+// are not valid domain labels in general.
+func (l Label) Skeleton() string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(l.label) {
+		if proto, ok := confusablePrototypes[r]; ok {
+			b.WriteString(proto)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return norm.NFD.String(b.String())
+}
+
+// Skeleton returns the confusable skeleton of the domain name. See
+// Label.Skeleton for details.
+func (d Name) Skeleton() string {
+	var b strings.Builder
+	for i := len(d.labels) - 1; i >= 0; i-- {
+		b.WriteString(d.labels[i].Skeleton())
+		if i != 0 {
+			b.WriteByte('.')
+		}
+	}
+	return b.String()
+}
+
+// ConfusableRunes returns the codepoints of d that look like
+// different codepoints of other, in the order they appear in d. d
+// and other should have equal skeletons.
+//
+// Codepoints are matched up by the part of the skeleton they produce,
+// so a codepoint can match a sequence of several codepoints (e.g. "m"
+// and "rn").
+func (d Name) ConfusableRunes(other Name) []rune {
+	type span struct{ start, end int }
+	spans := func(s string) (skel string, ret []span) {
+		var b strings.Builder
+		for _, r := range s {
+			start := b.Len()
+			var proto strings.Builder
+			for _, dr := range norm.NFD.String(string(r)) {
+				if p, ok := confusablePrototypes[dr]; ok {
+					proto.WriteString(p)
+				} else {
+					proto.WriteRune(dr)
+				}
+			}
+			b.WriteString(norm.NFD.String(proto.String()))
+			ret = append(ret, span{start, b.Len()})
+		}
+		return b.String(), ret
+	}
+
+	a, b := d.String(), other.String()
+	skelA, spansA := spans(a)
+	skelB, spansB := spans(b)
+
+	var ret []rune
+	if skelA != skelB {
+		// Combining marks got reordered across codepoints, or the
+		// names aren't confusable at all. Fall back to reporting the
+		// codepoints that other doesn't use.
+		for _, r := range a {
+			if !strings.ContainsRune(b, r) {
+				ret = append(ret, r)
+			}
+		}
+		return ret
+	}
+
+	runesB := map[span]rune{}
+	i := 0
+	for _, r := range b {
+		runesB[spansB[i]] = r
+		i++
+	}
+	i = 0
+	for _, r := range a {
+		if rb, ok := runesB[spansA[i]]; !ok || rb != r {
+			ret = append(ret, r)
+		}
+		i++
+	}
+	return ret
+}
+
+// Scripts returns the names of the Unicode scripts used by the
+// label, in order of first appearance. Codepoints in the Common and
+// Inherited scripts (digits, hyphens, combining marks) are not
+// counted.
+func (l Label) Scripts() []string {
+	var ret []string
+	for _, r := range l.label {
+		if s := scriptOf(r); s != "" && !slices.Contains(ret, s) {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
+
+// MixedScriptRunes reports whether the label mixes scripts in a way
+// that Unicode Technical Standard #39 section 5.2 considers unsafe,
+// and if so returns the codepoints that do not belong to the label's
+// dominant script.
+//
+// The PSL applies the "Highly Restrictive" level: a label must use a
+// single script, or one of the combinations that are common in East
+// Asian languages (Latin + Han + Hiragana + Katakana, Latin + Han +
+// Bopomofo, or Latin + Han + Hangul).
+func (l Label) MixedScriptRunes() (offending []rune, mixed bool) {
+	scripts := l.Scripts()
+	if len(scripts) <= 1 {
+		return nil, false
+	}
+	for _, allowed := range allowedScriptMixes {
+		if allSubset(scripts, allowed) {
+			return nil, false
+		}
+	}
+
+	// The offending codepoints are the ones that are not in the
+	// label's dominant script. If there's a tie, the script that
+	// appears first wins.
+	counts := map[string]int{}
+	for _, r := range l.label {
+		counts[scriptOf(r)]++
+	}
+	primary := scripts[0]
+	for _, s := range scripts[1:] {
+		if counts[s] > counts[primary] {
+			primary = s
+		}
+	}
+	for _, r := range l.label {
+		if s := scriptOf(r); s != "" && s != primary {
+			offending = append(offending, r)
+		}
+	}
+	return offending, true
+}
+
+// allowedScriptMixes are the combinations of scripts that the UTS #39
+// "Highly Restrictive" level allows within a single label.
+var allowedScriptMixes = [][]string{
+	{"Latin", "Han", "Hiragana", "Katakana"},
+	{"Latin", "Han", "Bopomofo"},
+	{"Latin", "Han", "Hangul"},
+}
+
+// allSubset reports whether all elements of a are in b.
+func allSubset(a, b []string) bool {
+	for _, s := range a {
+		if !slices.Contains(b, s) {
+			return false
+		}
+	}
+	return true
+}
+
+// scriptNames are the keys of unicode.Scripts, sorted so that script
+// lookups are deterministic.
+var scriptNames = func() []string {
+	var ret []string
+	for name := range unicode.Scripts {
+		ret = append(ret, name)
+	}
+	slices.Sort(ret)
+	return ret
+}()
+
+// scriptOf returns the Unicode script of r, or "" if r is in the
+// Common or Inherited script (or has no script at all).
+func scriptOf(r rune) string {
+	switch {
+	case r >= 'a' && r <= 'z':
+		// Fast path for the vast majority of PSL codepoints.
+		return "Latin"
+	case r < 0x80:
+		return ""
+	}
+	for _, name := range scriptNames {
+		if unicode.Is(unicode.Scripts[name], r) {
+			if name == "Common" || name == "Inherited" {
+				return ""
+			}
+			return name
+		}
+	}
+	return ""
+}
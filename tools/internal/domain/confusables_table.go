@@ -0,0 +1,2271 @@
+// Code generated by update_confusables.go. DO NOT EDIT.
+
+package domain
+
+// confusablePrototypes maps codepoints to the prototype string they
+// are visually confusable with, for the purpose of computing
+// skeletons. It holds the mappings of confusables.txt for Unicode
+// 15.0.0 whose source codepoint is left unchanged by IDNA mapping and
+// NFD, with prototypes in NFD.
+var confusablePrototypes = map[rune]string{
+	'0':          "O",
+	'1':          "l",
+	'm':          "rn",
+	'\u00a2':     "c\u0338",
+	'\u00a5':     "Y\u0335",
+	'\u00d7':     "x",
+	'\u00e6':     "ae",
+	'\u00f0':     "\u2202\u0335",
+	'\u00f8':     "o\u0338",
+	'\u0111':     "d\u0335",
+	'\u0127':     "h\u0335",
+	'\u0131':     "i",
+	'\u0142':     "l\u0338",
+	'\u0153':     "oe",
+	'\u0167':     "t\u0335",
+	'\u0180':     "b\u0335",
+	'\u0183':     "b\u0304",
+	'\u018c':     "d\u0304",
+	'\u018d':     "g",
+	'\u0192':     "f\u0326",
+	'\u0199':     "k\u0314",
+	'\u019a':     "l\u0335",
+	'\u019e':     "n\u0329",
+	'\u01a5':     "p\u0314",
+	'\u01ad':     "t\u0314",
+	'\u01b4':     "y\u0314",
+	'\u01b6':     "z\u0335",
+	'\u01bb':     "2\u0335",
+	'\u01bd':     "s",
+	'\u01bf':     "\u00fe",
+	'\u01c0':     "l",
+	'\u01c1':     "ll",
+	'\u01c3':     "!",
+	'\u01e5':     "g\u0335",
+	'\u0223':     "8",
+	'\u0225':     "z\u0326",
+	'\u023c':     "c\u0338",
+	'\u0247':     "e\u0338",
+	'\u0249':     "j\u0335",
+	'\u024d':     "r\u0335",
+	'\u024f':     "y\u0335",
+	'\u0251':     "a",
+	'\u0253':     "b\u0314",
+	'\u0256':     "d\u0328",
+	'\u0257':     "d\u0314",
+	'\u0259':     "\u01dd",
+	'\u025a':     "\u01dd\u02de",
+	'\u025b':     "\ua793",
+	'\u0260':     "g\u0314",
+	'\u0261':     "g",
+	'\u0263':     "y",
+	'\u0266':     "h\u0314",
+	'\u0268':     "i\u0335",
+	'\u0269':     "i",
+	'\u026a':     "i",
+	'\u026b':     "l\u0334",
+	'\u026d':     "l\u0328",
+	'\u026e':     "l\u021d",
+	'\u026f':     "w",
+	'\u0271':     "rn\u0326",
+	'\u0273':     "n\u0328",
+	'\u0275':     "o\u0335",
+	'\u0276':     "o\u1d07",
+	'\u027c':     "r\u0329",
+	'\u027d':     "r\u0328",
+	'\u0282':     "s\u0328",
+	'\u028b':     "u",
+	'\u028f':     "y",
+	'\u0290':     "z\u0328",
+	'\u0292':     "\u021d",
+	'\u0294':     "?",
+	'\u02a0':     "q\u0314",
+	'\u02a3':     "dz",
+	'\u02a4':     "d\u021d",
+	'\u02a5':     "d\u0291",
+	'\u02a6':     "ts",
+	'\u02a7':     "t\u0283",
+	'\u02a8':     "t\u0255",
+	'\u02a9':     "f\u014b",
+	'\u02aa':     "ls",
+	'\u02ab':     "lz",
+	'\u02b9':     "'",
+	'\u02ba':     "''",
+	'\u02bb':     "'",
+	'\u02bc':     "'",
+	'\u02bd':     "'",
+	'\u02be':     "'",
+	'\u02bf':     "\u0559",
+	'\u02c2':     "<",
+	'\u02c3':     ">",
+	'\u02c4':     "^",
+	'\u02c6':     "^",
+	'\u02c8':     "'",
+	'\u02ca':     "'",
+	'\u02cb':     "'",
+	'\u02d0':     ":",
+	'\u02d3':     "\u0559",
+	'\u02d7':     "-",
+	'\u02ee':     "''",
+	'\u02f4':     "'",
+	'\u02f6':     "''",
+	'\u02f8':     ":",
+	'\u02fb':     "\u02ea",
+	'\u0305':     "\u0304",
+	'\u030c':     "\u0306",
+	'\u030d':     "\u0670",
+	'\u0310':     "\u0306\u0307",
+	'\u0311':     "\u0302",
+	'\u0315':     "\u0313",
+	'\u0317':     "\u0650",
+	'\u0320':     "\u0331",
+	'\u0321':     "\u0326",
+	'\u0322':     "\u0328",
+	'\u0327':     "\u0326",
+	'\u0336':     "\u0335",
+	'\u0337':     "\u0338",
+	'\u0339':     "\u0326",
+	'\u0342':     "\u0303",
+	'\u0347':     "\u0333",
+	'\u0357':     "\u0350",
+	'\u0358':     "\u0307",
+	'\u0366':     "\u030a",
+	'\u036e':     "\u0306",
+	'\u0375':     "\u02cf",
+	'\u0377':     "\u1d0e",
+	'\u037b':     "\u0254",
+	'\u037d':     "\ua73f",
+	'\u03b1':     "a",
+	'\u03b2':     "\u00df",
+	'\u03b3':     "y",
+	'\u03b4':     "\u1e9f",
+	'\u03b5':     "\ua793",
+	'\u03b7':     "n\u0329",
+	'\u03b8':     "O\u0335",
+	'\u03b9':     "i",
+	'\u03ba':     "\u0138",
+	'\u03bd':     "v",
+	'\u03bf':     "o",
+	'\u03c1':     "p",
+	'\u03c3':     "o",
+	'\u03c4':     "\u1d1b",
+	'\u03c5':     "u",
+	'\u03c6':     "\u0278",
+	'\u03db':     "\u03c2",
+	'\u03e9':     "\u01a8",
+	'\u03f3':     "j",
+	'\u03f8':     "\u00fe",
+	'\u0430':     "a",
+	'\u0431':     "6",
+	'\u0432':     "\u0299",
+	'\u0433':     "r",
+	'\u0435':     "e",
+	'\u0437':     "\u025c",
+	'\u0438':     "\u1d0e",
+	'\u043a':     "\u0138",
+	'\u043c':     "\u028d",
+	'\u043d':     "\u029c",
+	'\u043e':     "o",
+	'\u043f':     "\u03c0",
+	'\u0440':     "p",
+	'\u0441':     "c",
+	'\u0442':     "\u1d1b",
+	'\u0443':     "y",
+	'\u0444':     "\u0278",
+	'\u0445':     "x",
+	'\u044a':     "\u02c9b",
+	'\u044b':     "\u0185i",
+	'\u044c':     "\u0185",
+	'\u044f':     "\u1d19",
+	'\u0454':     "\ua793",
+	'\u0455':     "s",
+	'\u0456':     "i",
+	'\u0458':     "j",
+	'\u045b':     "h\u0335",
+	'\u0461':     "w",
+	'\u0463':     "b\u0335",
+	'\u0471':     "\u03c8",
+	'\u0473':     "o\u0335",
+	'\u0475':     "v",
+	'\u047d':     "w\u0486\u0487",
+	'\u048b':     "\u0438\u0326\u0306",
+	'\u048d':     "b\u0335",
+	'\u0491':     "r'",
+	'\u0493':     "r\u0335",
+	'\u0497':     "\u0436\u0329",
+	'\u0499':     "\u025c\u0326",
+	'\u049b':     "\u0138\u0329",
+	'\u049f':     "\u0138\u0335",
+	'\u04a3':     "\u029c\u0329",
+	'\u04ab':     "c\u0326",
+	'\u04ad':     "\u1d1b\u0329",
+	'\u04af':     "y",
+	'\u04b1':     "y\u0335",
+	'\u04bb':     "h",
+	'\u04bd':     "e",
+	'\u04bf':     "e\u0328",
+	'\u04c6':     "\u043b\u0326",
+	'\u04c8':     "\u029c\u0326",
+	'\u04ca':     "\u029c\u0326",
+	'\u04cc':     "\u04b7",
+	'\u04ce':     "\u028d\u0326",
+	'\u04cf':     "i",
+	'\u04d5':     "ae",
+	'\u04d9':     "\u01dd",
+	'\u04e1':     "\u021d",
+	'\u04e9':     "o\u0335",
+	'\u0501':     "d",
+	'\u050d':     "\u0262",
+	'\u0511':     "\ua793",
+	'\u051b':     "q",
+	'\u051d':     "w",
+	'\u055a':     "'",
+	'\u055d':     "'",
+	'\u0561':     "w",
+	'\u0563':     "q",
+	'\u0566':     "q",
+	'\u056e':     "\u1e9f",
+	'\u0570':     "h",
+	'\u0575':     "\u0237",
+	'\u0578':     "n",
+	'\u057a':     "\u0270",
+	'\u057c':     "n",
+	'\u057d':     "u",
+	'\u0581':     "g",
+	'\u0584':     "f",
+	'\u0585':     "o",
+	'\u0589':     ":",
+	'\u059c':     "\u0301",
+	'\u059d':     "\u0301",
+	'\u05a4':     "\u059a",
+	'\u05a8':     "\u0599",
+	'\u05ad':     "\u0596",
+	'\u05ae':     "\u0598",
+	'\u05af':     "\u030a",
+	'\u05b4':     "\u0323",
+	'\u05b9':     "\u0307",
+	'\u05ba':     "\u0307",
+	'\u05c0':     "l",
+	'\u05c1':     "\u0307",
+	'\u05c2':     "\u0307",
+	'\u05c3':     ":",
+	'\u05c4':     "\u0307",
+	'\u05c5':     "\u0323",
+	'\u05d5':     "l",
+	'\u05d8':     "v",
+	'\u05d9':     "'",
+	'\u05df':     "l",
+	'\u05e1':     "o",
+	'\u05f0':     "ll",
+	'\u05f1':     "l'",
+	'\u05f2':     "''",
+	'\u05f3':     "'",
+	'\u05f4':     "''",
+	'\u0609':     "\u00ba/\u2080\u2080",
+	'\u060a':     "\u00ba/\u2080\u2080\u2080",
+	'\u060d':     ",",
+	'\u060f':     "\u0639",
+	'\u0618':     "\u0301",
+	'\u0619':     "\u0313",
+	'\u061a':     "\u0650",
+	'\u0627':     "l",
+	'\u062b':     "\u0649\u06db",
+	'\u0634':     "\u0633\u06db",
+	'\u063d':     "\u0649\u0302",
+	'\u063f':     "\u0649\u06db",
+	'\u0647':     "o",
+	'\u064a':     "\u0649",
+	'\u064b':     "\u030b",
+	'\u064e':     "\u0301",
+	'\u064f':     "\u0313",
+	'\u0652':     "\u030a",
+	'\u0653':     "\u0303",
+	'\u0656':     "\u0329",
+	'\u0657':     "\u0312",
+	'\u0658':     "\u0306",
+	'\u0659':     "\u0304",
+	'\u065a':     "\u0306",
+	'\u065b':     "\u0302",
+	'\u065c':     "\u0323",
+	'\u065d':     "\u0314",
+	'\u065f':     "\u0655",
+	'\u0660':     ".",
+	'\u0661':     "l",
+	'\u0665':     "o",
+	'\u0667':     "V",
+	'\u0668':     "\u0245",
+	'\u066a':     "\u00ba/\u2080",
+	'\u066b':     ",",
+	'\u066c':     "\u060c",
+	'\u066d':     "*",
+	'\u066e':     "\u0649",
+	'\u066f':     "\u06a1",
+	'\u0672':     "l\u0674",
+	'\u0673':     "l\u0655",
+	'\u0679':     "\u0649\u0615",
+	'\u067e':     "\u0649\u06db",
+	'\u0681':     "\u062d\u0654",
+	'\u0685':     "\u062d\u06db",
+	'\u0688':     "\u062f\u0615",
+	'\u068b':     "\u068a\u0615",
+	'\u068e':     "\u062f\u06db",
+	'\u0691':     "\u0631\u0615",
+	'\u0692':     "\u0631\u0306",
+	'\u0698':     "\u0631\u06db",
+	'\u069e':     "\u0635\u06db",
+	'\u069f':     "\u0637\u06db",
+	'\u06a4':     "\u06a1\u06db",
+	'\u06a7':     "\u0641",
+	'\u06a8':     "\u06a1\u06db",
+	'\u06a9':     "\u0643",
+	'\u06aa':     "\u0643",
+	'\u06ad':     "\u0643\u06db",
+	'\u06b4':     "\u06af\u06db",
+	'\u06b5':     "\u0644\u0306",
+	'\u06b7':     "\u0644\u06db",
+	'\u06ba':     "\u0649",
+	'\u06bb':     "\u0649\u0615",
+	'\u06bd':     "\u0649\u06db",
+	'\u06be':     "o",
+	'\u06c1':     "o",
+	'\u06c3':     "\u0629",
+	'\u06c6':     "\u0648\u0306",
+	'\u06c7':     "\u0648\u0313",
+	'\u06c8':     "\u0648\u0670",
+	'\u06c9':     "\u0648\u0302",
+	'\u06cb':     "\u0648\u06db",
+	'\u06cc':     "\u0649",
+	'\u06ce':     "\u0649\u0306",
+	'\u06d0':     "\u067b",
+	'\u06d1':     "\u0649\u06db",
+	'\u06d2':     "\u0649",
+	'\u06d4':     "-",
+	'\u06d5':     "o",
+	'\u06df':     "\u030a",
+	'\u06e8':     "\u0306\u0307",
+	'\u06ec':     "\u0307",
+	'\u06ee':     "\u062f\u0302",
+	'\u06ef':     "\u0631\u0302",
+	'\u06f0':     ".",
+	'\u06f1':     "l",
+	'\u06f2':     "\u0662",
+	'\u06f3':     "\u0663",
+	'\u06f4':     "\u0664",
+	'\u06f5':     "o",
+	'\u06f6':     "\u0666",
+	'\u06f7':     "V",
+	'\u06f8':     "\u0245",
+	'\u06f9':     "\u0669",
+	'\u06fd':     "\u0621\u0348",
+	'\u06fe':     "\u0645\u0348",
+	'\u06ff':     "o\u0302",
+	'\u0701':     ".",
+	'\u0702':     ".",
+	'\u0703':     ":",
+	'\u0704':     ":",
+	'\u0740':     "\u0307",
+	'\u0741':     "\u0307",
+	'\u0742':     "\u073c",
+	'\u0747':     "\u0301",
+	'\u0751':     "\u0628\u06db",
+	'\u0756':     "\u0649\u0306",
+	'\u0762':     "\u06ac",
+	'\u0763':     "\u0643\u06db",
+	'\u0767':     "\u0754",
+	'\u0768':     "\u0646\u0615",
+	'\u0769':     "\u0646\u0306",
+	'\u076c':     "\u0631\u0654",
+	'\u0771':     "\u0697\u0615",
+	'\u0772':     "\u062d\u0654",
+	'\u077e':     "\u0633\u0302",
+	'\u07c0':     "O",
+	'\u07ca':     "l",
+	'\u07eb':     "\u0304",
+	'\u07ed':     "\u0307",
+	'\u07ee':     "\u0302",
+	'\u07f3':     "\u0308",
+	'\u07f4':     "'",
+	'\u07f5':     "'",
+	'\u07fa':     "_",
+	'\u08a1':     "\u0628\u0654",
+	'\u08a4':     "\u06a2\u06db",
+	'\u08a7':     "\u0645\u06db",
+	'\u08a8':     "\u0649\u0654",
+	'\u08a9':     "\u0754",
+	'\u08ae':     "\u062f\u0324\u0323",
+	'\u08af':     "\u0635\u0324\u0323",
+	'\u08b0':     "\u06af",
+	'\u08b1':     "\u0648",
+	'\u08b2':     "\u0632\u0302",
+	'\u08b6':     "\u0628\u06e2",
+	'\u08b7':     "\u0649\u06db\u06e2",
+	'\u08b9':     "\u0631\u0306\u0307",
+	'\u08ba':     "\u0649\u0306\u0307",
+	'\u08bb':     "\u06a1",
+	'\u08bc':     "\u06a1",
+	'\u08bd':     "\u0649",
+	'\u08e5':     "\u064c",
+	'\u08e8':     "\u064c",
+	'\u08ea':     "\u0307",
+	'\u08eb':     "\u0308",
+	'\u08ed':     "\u0323",
+	'\u08ee':     "\u0324",
+	'\u08f0':     "\u030b",
+	'\u08f1':     "\u064c",
+	'\u08f2':     "\u064d",
+	'\u08f3':     "\u0313",
+	'\u08f8':     "\u0350",
+	'\u08f9':     "\u0354",
+	'\u08fa':     "\u0355",
+	'\u08ff':     "\u0350",
+	'\u0900':     "\u0352",
+	'\u0901':     "\u0306\u0307",
+	'\u0902':     "\u0307",
+	'\u0903':     ":",
+	'\u0904':     "\u0905\u0946",
+	'\u0906':     "\u0905\u093e",
+	'\u0908':     "\u0930\u094d\u0907",
+	'\u090d':     "\u090f\u0945",
+	'\u090e':     "\u090f\u0946",
+	'\u0910':     "\u090f\u0947",
+	'\u0911':     "\u0905\u0949",
+	'\u0912':     "\u0905\u093e\u0946",
+	'\u0913':     "\u0905\u093e\u0947",
+	'\u0914':     "\u0905\u093e\u0948",
+	'\u093c':     "\u0323",
+	'\u0952':     "\u0331",
+	'\u0953':     "\u0300",
+	'\u0954':     "\u0301",
+	'\u0965':     "\u0964\u0964",
+	'\u0966':     "o",
+	'\u0967':     "\u0669",
+	'\u097d':     "?",
+	'\u0981':     "\u0306\u0307",
+	'\u0986':     "\u0985\u09be",
+	'\u09bc':     "\u0323",
+	'\u09e0':     "\u098b\u09c3",
+	'\u09e1':     "\u098b\u09c3",
+	'\u09e6':     "O",
+	'\u09ea':     "8",
+	'\u09ed':     "9",
+	'\u0a02':     "\u0307",
+	'\u0a03':     "\u0983",
+	'\u0a06':     "\u0a05\u0a3e",
+	'\u0a07':     "\u0a72\u0a3f",
+	'\u0a08':     "\u0a72\u0a40",
+	'\u0a09':     "\u0a73\u0a41",
+	'\u0a0a':     "\u0a73\u0a42",
+	'\u0a0f':     "\u0a72\u0a47",
+	'\u0a10':     "\u0a05\u0a48",
+	'\u0a14':     "\u0a05\u0a4c",
+	'\u0a3c':     "\u0323",
+	'\u0a4b':     "\u0946",
+	'\u0a4d':     "\u094d",
+	'\u0a66':     "o",
+	'\u0a67':     "9",
+	'\u0a6a':     "8",
+	'\u0a81':     "\u0306\u0307",
+	'\u0a82':     "\u0307",
+	'\u0a83':     ":",
+	'\u0a86':     "\u0a85\u0abe",
+	'\u0a8d':     "\u0a85\u0ac5",
+	'\u0a8f':     "\u0a85\u0ac7",
+	'\u0a90':     "\u0a85\u0ac8",
+	'\u0a91':     "\u0a85\u0abe\u0ac5",
+	'\u0a93':     "\u0a85\u0abe\u0ac7",
+	'\u0a94':     "\u0a85\u0abe\u0ac8",
+	'\u0abc':     "\u0323",
+	'\u0abd':     "\u093d",
+	'\u0ac1':     "\u0941",
+	'\u0ac2':     "\u0942",
+	'\u0acd':     "\u094d",
+	'\u0ae6':     "o",
+	'\u0ae8':     "\u0968",
+	'\u0ae9':     "\u0969",
+	'\u0aea':     "\u096a",
+	'\u0aee':     "\u096e",
+	'\u0af0':     "\u0970",
+	'\u0b01':     "\u0306\u0307",
+	'\u0b03':     "8",
+	'\u0b06':     "\u0b05\u0b3e",
+	'\u0b20':     "O",
+	'\u0b3c':     "\u0323",
+	'\u0b66':     "O",
+	'\u0b68':     "9",
+	'\u0b82':     "\u030a",
+	'\u0b8a':     "\u0b89\u0bb3",
+	'\u0b9c':     "\u0b90",
+	'\u0bb0':     "\u0b88",
+	'\u0bbe':     "\u0b88",
+	'\u0bc8':     "\u0ba9",
+	'\u0bcd':     "\u0307",
+	'\u0bd7':     "\u0bb3",
+	'\u0be6':     "o",
+	'\u0be7':     "\u0b95",
+	'\u0be8':     "\u0b89",
+	'\u0bea':     "\u0b9a",
+	'\u0beb':     "\u0b88\u0bc1",
+	'\u0bec':     "\u0b9a\u0bc1",
+	'\u0bed':     "\u0b8e",
+	'\u0bee':     "\u0b85",
+	'\u0bf0':     "\u0baf",
+	'\u0bf2':     "\u0b9a\u0bc2",
+	'\u0bf4':     "\u0bae\u0bc0",
+	'\u0bf5':     "\u0bf3",
+	'\u0bf7':     "\u0b8e\u0bb5",
+	'\u0bf8':     "\u0bb7",
+	'\u0bfa':     "\u0ba8\u0bc0",
+	'\u0c00':     "\u0306\u0307",
+	'\u0c02':     "o",
+	'\u0c03':     "\u0983",
+	'\u0c13':     "\u0c12\u0c55",
+	'\u0c14':     "\u0c12\u0c4c",
+	'\u0c20':     "\u0c30\u05bc",
+	'\u0c22':     "\u0c21\u0323",
+	'\u0c25':     "\u0c27\u05bc",
+	'\u0c2d':     "\u0c2c\u0323",
+	'\u0c2e':     "\u0c35\u0c41",
+	'\u0c37':     "\u0c35\u0323",
+	'\u0c39':     "\u0c35\u0c3e",
+	'\u0c42':     "\u0c41\u0c3e",
+	'\u0c44':     "\u0c43\u0c3e",
+	'\u0c60':     "\u0c0b\u0c3e",
+	'\u0c61':     "\u0c0c\u0c3e",
+	'\u0c66':     "o",
+	'\u0c81':     "\u0306\u0307",
+	'\u0c82':     "o",
+	'\u0c83':     "\u0983",
+	'\u0c85':     "\u0c05",
+	'\u0c86':     "\u0c06",
+	'\u0c87':     "\u0c07",
+	'\u0c92':     "\u0c12",
+	'\u0c93':     "\u0c12\u0c55",
+	'\u0c94':     "\u0c12\u0c4c",
+	'\u0c9c':     "\u0c1c",
+	'\u0c9e':     "\u0c1e",
+	'\u0ca3':     "\u0c23",
+	'\u0caf':     "\u0c2f",
+	'\u0cb1':     "\u0c31",
+	'\u0cb2':     "\u0c32",
+	'\u0ce1':     "\u0c8c\u0cbe",
+	'\u0ce6':     "o",
+	'\u0ce7':     "\u0c67",
+	'\u0ce8':     "\u0c68",
+	'\u0cef':     "\u0c6f",
+	'\u0d01':     "\u0306\u0307",
+	'\u0d02':     "o",
+	'\u0d03':     "\u0983",
+	'\u0d08':     "\u0d07\u0d57",
+	'\u0d09':     "\u0b89",
+	'\u0d0a':     "\u0b89\u0d57",
+	'\u0d0c':     "\u0d28\u0d41",
+	'\u0d10':     "\u0d0e\u0d46",
+	'\u0d13':     "\u0d12\u0d3e",
+	'\u0d14':     "\u0d12\u0d57",
+	'\u0d19':     "\u0d28\u0d41",
+	'\u0d1c':     "\u0b90",
+	'\u0d20':     "o",
+	'\u0d23':     "\u0ba3",
+	'\u0d31':     "\u0d30",
+	'\u0d34':     "\u0bb4",
+	'\u0d36':     "\u0bb6",
+	'\u0d3a':     "\u0b9f\u0bbf",
+	'\u0d3f':     "\u0bbf",
+	'\u0d40':     "\u0bbf",
+	'\u0d42':     "\u0d41",
+	'\u0d43':     "\u0d41",
+	'\u0d48':     "\u0d46\u0d46",
+	'\u0d4e':     "\u0971",
+	'\u0d5a':     "\u0d28\u0d4d\u0d2e",
+	'\u0d5f':     "o\u0d30o",
+	'\u0d61':     "\u0d1e",
+	'\u0d66':     "o",
+	'\u0d6a':     "\u0d30\u0d4d",
+	'\u0d6b':     "\u0d26\u0d4d\u0d30",
+	'\u0d6c':     "\u0d28\u0d4d\u0d28",
+	'\u0d6d':     "9",
+	'\u0d6e':     "\u0d35\u0d4d\u0d30",
+	'\u0d6f':     "\u0d28\u0d4d",
+	'\u0d76':     "\u0d39\u0d4d\u0d2e",
+	'\u0d79':     "\u0d28\u0d41",
+	'\u0d7b':     "\u0d28\u0d4d",
+	'\u0d7c':     "\u0d30\u0d4d",
+	'\u0d82':     "o",
+	'\u0d83':     "\u0983",
+	'\u0de9':     "\u0de8\u0dcf",
+	'\u0dea':     "\u0da2",
+	'\u0deb':     "\u0daf",
+	'\u0def':     "\u0de8\u0dd3",
+	'\u0e03':     "\u0e02",
+	'\u0e0b':     "\u0e0a",
+	'\u0e0f':     "\u0e0e",
+	'\u0e14':     "\u0e04",
+	'\u0e15':     "\u0e04",
+	'\u0e17':     "\u0e11",
+	'\u0e21':     "\u0e06",
+	'\u0e26':     "\u0e20",
+	'\u0e41':     "\u0e40\u0e40",
+	'\u0e45':     "\u0e32",
+	'\u0e4d':     "\u030a",
+	'\u0e50':     "o",
+	'\u0e88':     "\u0e08",
+	'\u0e8d':     "\u0e22",
+	'\u0e9a':     "\u0e1a",
+	'\u0e9b':     "\u0e1b",
+	'\u0e9d':     "\u0e1d",
+	'\u0e9e':     "\u0e1e",
+	'\u0e9f':     "\u0e1f",
+	'\u0eb8':     "\u0e38",
+	'\u0eb9':     "\u0e39",
+	'\u0ec8':     "\u0e48",
+	'\u0ec9':     "\u0e49",
+	'\u0eca':     "\u0e4a",
+	'\u0ecb':     "\u0e4b",
+	'\u0ecd':     "\u030a",
+	'\u0ed0':     "o",
+	'\u0f00':     "\u0f68\u0f7c\u0f7e",
+	'\u0f02':     "\u0f60\u0f74\u0f82\u0f7f",
+	'\u0f03':     "\u0f60\u0f74\u0f82\u0f14",
+	'\u0f0e':     "\u0f0d\u0f0d",
+	'\u0f1b':     "\u0f1a\u0f1a",
+	'\u0f1e':     "\u0f1d\u0f1d",
+	'\u0f1f':     "\u0f1a\u0f1d",
+	'\u0f37':     "\u0325",
+	'\u0f6a':     "\u0f62",
+	'\u0fce':     "\u0f1d\u0f1a",
+	'\u0fd5':     "\u5350",
+	'\u0fd6':     "\u534d",
+	'\u1000':     "\u1002\u102c",
+	'\u1010':     "o\u102c",
+	'\u101d':     "o",
+	'\u101f':     "\u1015\u102c",
+	'\u1029':     "\u101e\u103c",
+	'\u102a':     "\u101e\u103c\u1031\u102c\u103a",
+	'\u1036':     "\u030a",
+	'\u1038':     "\u0983",
+	'\u1040':     "o",
+	'\u104b':     "\u104a\u104a",
+	'\u1065':     "\u1041",
+	'\u1066':     "\u1015\u103e",
+	'\u106f':     "\u1015\u102c\u103e",
+	'\u1070':     "\u1003\u103e",
+	'\u107e':     "\u107d\u103e",
+	'\u1081':     "\u1002\u103e",
+	'\u109e':     "\u1083\u030a",
+	'\u10e7':     "y",
+	'\u10f3':     "\u021d",
+	'\u10ff':     "o",
+	'\u1101':     "\u1100\u1100",
+	'\u1104':     "\u1103\u1103",
+	'\u1108':     "\u1107\u1107",
+	'\u110a':     "\u1109\u1109",
+	'\u110d':     "\u110c\u110c",
+	'\u1113':     "\u1102\u1100",
+	'\u1114':     "\u1102\u1102",
+	'\u1115':     "\u1102\u1103",
+	'\u1116':     "\u1102\u1107",
+	'\u1117':     "\u1103\u1100",
+	'\u1118':     "\u1105\u1102",
+	'\u1119':     "\u1105\u1105",
+	'\u111a':     "\u1105\u1112",
+	'\u111b':     "\u1105\u110b",
+	'\u111c':     "\u1106\u1107",
+	'\u111d':     "\u1106\u110b",
+	'\u111e':     "\u1107\u1100",
+	'\u111f':     "\u1107\u1102",
+	'\u1120':     "\u1107\u1103",
+	'\u1121':     "\u1107\u1109",
+	'\u1122':     "\u1107\u1109\u1100",
+	'\u1123':     "\u1107\u1109\u1103",
+	'\u1124':     "\u1107\u1109\u1107",
+	'\u1125':     "\u1107\u1109\u1109",
+	'\u1126':     "\u1107\u1109\u110c",
+	'\u1127':     "\u1107\u110c",
+	'\u1128':     "\u1107\u110e",
+	'\u1129':     "\u1107\u1110",
+	'\u112a':     "\u1107\u1111",
+	'\u112b':     "\u1107\u110b",
+	'\u112c':     "\u1107\u1107\u110b",
+	'\u112d':     "\u1109\u1100",
+	'\u112e':     "\u1109\u1102",
+	'\u112f':     "\u1109\u1103",
+	'\u1130':     "\u1109\u1105",
+	'\u1131':     "\u1109\u1106",
+	'\u1132':     "\u1109\u1107",
+	'\u1133':     "\u1109\u1107\u1100",
+	'\u1134':     "\u1109\u1109\u1109",
+	'\u1135':     "\u1109\u110b",
+	'\u1136':     "\u1109\u110c",
+	'\u1137':     "\u1109\u110e",
+	'\u1138':     "\u1109\u110f",
+	'\u1139':     "\u1109\u1110",
+	'\u113a':     "\u1109\u1111",
+	'\u113b':     "\u1105\u1112",
+	'\u113d':     "\u113c\u113c",
+	'\u113f':     "\u113e\u113e",
+	'\u1141':     "\u110b\u1100",
+	'\u1142':     "\u110b\u1103",
+	'\u1143':     "\u110b\u1106",
+	'\u1144':     "\u110b\u1107",
+	'\u1145':     "\u110b\u1109",
+	'\u1146':     "\u110b\u1140",
+	'\u1147':     "\u110b\u110b",
+	'\u1148':     "\u110b\u110c",
+	'\u1149':     "\u110b\u110e",
+	'\u114a':     "\u110b\u1110",
+	'\u114b':     "\u110b\u1111",
+	'\u114d':     "\u110c\u110b",
+	'\u114f':     "\u114e\u114e",
+	'\u1151':     "\u1150\u1150",
+	'\u1152':     "\u110e\u110f",
+	'\u1153':     "\u110e\u1112",
+	'\u1156':     "\u1111\u1107",
+	'\u1157':     "\u1111\u110b",
+	'\u1158':     "\u1112\u1112",
+	'\u115a':     "\u1100\u1103",
+	'\u115b':     "\u1102\u1109",
+	'\u115c':     "\u1102\u110c",
+	'\u115d':     "\u1102\u1112",
+	'\u115e':     "\u1103\u1105",
+	'\u1162':     "\u1161\u4e28",
+	'\u1164':     "\u1163\u4e28",
+	'\u1166':     "\u1165\u4e28",
+	'\u1168':     "\u1167\u4e28",
+	'\u116a':     "\u1169\u1161",
+	'\u116b':     "\u1169\u1161\u4e28",
+	'\u116c':     "\u1169\u4e28",
+	'\u116f':     "\u116e\u1165",
+	'\u1170':     "\u116e\u1165\u4e28",
+	'\u1171':     "\u116e\u4e28",
+	'\u1173':     "\u30fc",
+	'\u1174':     "\u30fc\u4e28",
+	'\u1175':     "\u4e28",
+	'\u1176':     "\u1161\u1169",
+	'\u1177':     "\u1161\u116e",
+	'\u1178':     "\u1163\u1169",
+	'\u1179':     "\u1163\u116d",
+	'\u117a':     "\u1165\u1169",
+	'\u117b':     "\u1165\u116e",
+	'\u117c':     "\u1165\u30fc",
+	'\u117d':     "\u1167\u1169",
+	'\u117e':     "\u1167\u116e",
+	'\u117f':     "\u1169\u1165",
+	'\u1180':     "\u1169\u1165\u4e28",
+	'\u1181':     "\u1169\u1167\u4e28",
+	'\u1182':     "\u1169\u1169",
+	'\u1183':     "\u1169\u116e",
+	'\u1184':     "\u116d\u1163",
+	'\u1185':     "\u116d\u1163\u4e28",
+	'\u1186':     "\u116d\u1163",
+	'\u1187':     "\u116d\u1169",
+	'\u1188':     "\u116d\u4e28",
+	'\u1189':     "\u116e\u1161",
+	'\u118a':     "\u116e\u1161\u4e28",
+	'\u118b':     "\u116e\u1165\u30fc",
+	'\u118c':     "\u116e\u1167\u4e28",
+	'\u118d':     "\u116e\u116e",
+	'\u118e':     "\u1172\u1161",
+	'\u118f':     "\u1172\u1165",
+	'\u1190':     "\u1172\u1165\u4e28",
+	'\u1191':     "\u1172\u1167",
+	'\u1192':     "\u1172\u1167\u4e28",
+	'\u1193':     "\u1172\u116e",
+	'\u1194':     "\u1172\u4e28",
+	'\u1195':     "\u30fc\u116e",
+	'\u1196':     "\u30fc\u30fc",
+	'\u1197':     "\u30fc\u4e28\u116e",
+	'\u1198':     "\u4e28\u1161",
+	'\u1199':     "\u4e28\u1163",
+	'\u119a':     "\u4e28\u1169",
+	'\u119b':     "\u4e28\u116e",
+	'\u119c':     "\u4e28\u30fc",
+	'\u119d':     "\u4e28\u119e",
+	'\u119f':     "\u119e\u1165",
+	'\u11a0':     "\u119e\u116e",
+	'\u11a1':     "\u119e\u4e28",
+	'\u11a2':     "\u119e\u119e",
+	'\u11a3':     "\u1161\u30fc",
+	'\u11a4':     "\u1163\u116e",
+	'\u11a5':     "\u1167\u1163",
+	'\u11a6':     "\u1169\u1163",
+	'\u11a7':     "\u1169\u1163\u4e28",
+	'\u11a8':     "\u1100",
+	'\u11a9':     "\u1100\u1100",
+	'\u11aa':     "\u1100\u1109",
+	'\u11ab':     "\u1102",
+	'\u11ac':     "\u1102\u110c",
+	'\u11ad':     "\u1102\u1112",
+	'\u11ae':     "\u1103",
+	'\u11af':     "\u1105",
+	'\u11b0':     "\u1105\u1100",
+	'\u11b1':     "\u1105\u1106",
+	'\u11b2':     "\u1105\u1107",
+	'\u11b3':     "\u1105\u1109",
+	'\u11b4':     "\u1105\u1110",
+	'\u11b5':     "\u1105\u1111",
+	'\u11b6':     "\u1105\u1112",
+	'\u11b7':     "\u1106",
+	'\u11b8':     "\u1107",
+	'\u11b9':     "\u1107\u1109",
+	'\u11ba':     "\u1109",
+	'\u11bb':     "\u1109\u1109",
+	'\u11bc':     "\u110b",
+	'\u11bd':     "\u110c",
+	'\u11be':     "\u110e",
+	'\u11bf':     "\u110f",
+	'\u11c0':     "\u1110",
+	'\u11c1':     "\u1111",
+	'\u11c2':     "\u1112",
+	'\u11c3':     "\u1100\u1105",
+	'\u11c4':     "\u1100\u1109\u1100",
+	'\u11c5':     "\u1102\u1100",
+	'\u11c6':     "\u1102\u1103",
+	'\u11c7':     "\u1102\u1109",
+	'\u11c8':     "\u1102\u1140",
+	'\u11c9':     "\u1102\u1110",
+	'\u11ca':     "\u1103\u1100",
+	'\u11cb':     "\u1103\u1105",
+	'\u11cc':     "\u1105\u1100\u1109",
+	'\u11cd':     "\u1105\u1102",
+	'\u11ce':     "\u1105\u1103",
+	'\u11cf':     "\u1105\u1103\u1112",
+	'\u11d0':     "\u1105\u1105",
+	'\u11d1':     "\u1105\u1106\u1100",
+	'\u11d2':     "\u1105\u1106\u1109",
+	'\u11d3':     "\u1105\u1107\u1109",
+	'\u11d4':     "\u1105\u1107\u1112",
+	'\u11d5':     "\u1105\u1107\u110b",
+	'\u11d6':     "\u1105\u1109\u1109",
+	'\u11d7':     "\u1105\u1140",
+	'\u11d8':     "\u1105\u110f",
+	'\u11d9':     "\u1105\u1159",
+	'\u11da':     "\u1106\u1100",
+	'\u11db':     "\u1106\u1105",
+	'\u11dc':     "\u1106\u1107",
+	'\u11dd':     "\u1106\u1109",
+	'\u11de':     "\u1106\u1109\u1109",
+	'\u11df':     "\u1106\u1140",
+	'\u11e0':     "\u1106\u110e",
+	'\u11e1':     "\u1106\u1112",
+	'\u11e2':     "\u1106\u110b",
+	'\u11e3':     "\u1107\u1105",
+	'\u11e4':     "\u1107\u1111",
+	'\u11e5':     "\u1107\u1112",
+	'\u11e6':     "\u1107\u110b",
+	'\u11e7':     "\u1109\u1100",
+	'\u11e8':     "\u1109\u1103",
+	'\u11e9':     "\u1109\u1105",
+	'\u11ea':     "\u1109\u1107",
+	'\u11eb':     "\u1140",
+	'\u11ec':     "\u110b\u1100",
+	'\u11ed':     "\u110b\u1100\u1100",
+	'\u11ee':     "\u110b\u110b",
+	'\u11ef':     "\u110b\u110f",
+	'\u11f0':     "\u114c",
+	'\u11f1':     "\u110b\u1109",
+	'\u11f2':     "\u110b\u1140",
+	'\u11f3':     "\u1111\u1107",
+	'\u11f4':     "\u1111\u110b",
+	'\u11f5':     "\u1112\u1102",
+	'\u11f6':     "\u1112\u1105",
+	'\u11f7':     "\u1112\u1106",
+	'\u11f8':     "\u1112\u1107",
+	'\u11f9':     "\u1159",
+	'\u11fa':     "\u1100\u1102",
+	'\u11fb':     "\u1100\u1107",
+	'\u11fc':     "\u1100\u110e",
+	'\u11fd':     "\u1100\u110f",
+	'\u11fe':     "\u1100\u1112",
+	'\u11ff':     "\u1102\u1102",
+	'\u1200':     "U",
+	'\u1223':     "\u0270",
+	'\u1240':     "\u03a6",
+	'\u1260':     "\u0548",
+	'\u1294':     "\u0571",
+	'\u12d0':     "O",
+	'\u13a0':     "D",
+	'\u13a1':     "R",
+	'\u13a2':     "T",
+	'\u13a4':     "O'",
+	'\u13a5':     "i",
+	'\u13a8':     "\u2c75",
+	'\u13a9':     "Y",
+	'\u13aa':     "A",
+	'\u13ab':     "J",
+	'\u13ac':     "E",
+	'\u13ae':     "?",
+	'\u13b0':     "\u2c75",
+	'\u13b1':     "\u0393",
+	'\u13b3':     "W",
+	'\u13b7':     "M",
+	'\u13bb':     "H",
+	'\u13bd':     "Y",
+	'\u13be':     "O\u0335",
+	'\u13bf':     "\u01ab",
+	'\u13c0':     "G",
+	'\u13c2':     "h",
+	'\u13c3':     "Z",
+	'\u13c7':     "\u0460",
+	'\u13cb':     "\u0190",
+	'\u13cc':     "U\u0335",
+	'\u13ce':     "4",
+	'\u13cf':     "b",
+	'\u13d2':     "R",
+	'\u13d4':     "W",
+	'\u13d5':     "S",
+	'\u13d9':     "V",
+	'\u13da':     "S",
+	'\u13de':     "L",
+	'\u13df':     "C",
+	'\u13e2':     "P",
+	'\u13e6':     "K",
+	'\u13e7':     "d",
+	'\u13eb':     "O\u0335",
+	'\u13ee':     "6",
+	'\u13f0':     "\u00df",
+	'\u13f2':     "h\u0314",
+	'\u13f3':     "G",
+	'\u13f4':     "B",
+	'\u1400':     "=",
+	'\u1403':     "\u0394",
+	'\u140c':     "\u00b7\u1401",
+	'\u140d':     "\u1401\u00b7",
+	'\u140e':     "\u00b7\u0394",
+	'\u140f':     "\u0394\u00b7",
+	'\u1410':     "\u00b7\u1404",
+	'\u1411':     "\u1404\u00b7",
+	'\u1412':     "\u00b7\u1405",
+	'\u1413':     "\u1405\u00b7",
+	'\u1414':     "\u00b7\u1406",
+	'\u1415':     "\u1406\u00b7",
+	'\u1417':     "\u00b7\u140a",
+	'\u1418':     "\u140a\u00b7",
+	'\u1419':     "\u00b7\u140b",
+	'\u141a':     "\u140b\u00b7",
+	'\u1427':     "\u00b7",
+	'\u142b':     "\u1401\u1420",
+	'\u142c':     "\u0394\u1420",
+	'\u142d':     "\u1405\u1420",
+	'\u142e':     "\u140a\u1420",
+	'\u142f':     "V",
+	'\u1431':     "\u0245",
+	'\u1433':     ">",
+	'\u1437':     "\u00b7>",
+	'\u1438':     "<",
+	'\u143a':     "\u00b7V",
+	'\u143b':     "V\u00b7",
+	'\u143c':     "\u00b7\u0245",
+	'\u143d':     "\u0245\u00b7",
+	'\u143e':     "\u00b7\u1432",
+	'\u143f':     "\u1432\u00b7",
+	'\u1440':     "\u00b7>",
+	'\u1441':     ">\u00b7",
+	'\u1442':     "\u00b7\u1434",
+	'\u1443':     "\u1434\u00b7",
+	'\u1444':     "\u00b7<",
+	'\u1445':     "<\u00b7",
+	'\u1446':     "\u00b7\u1439",
+	'\u1447':     "\u1439\u00b7",
+	'\u144a':     "'",
+	'\u144c':     "U",
+	'\u144e':     "\u0548",
+	'\u1454':     "\u00b7\u1450",
+	'\u1457':     "\u00b7U",
+	'\u1458':     "U\u00b7",
+	'\u1459':     "\u00b7\u0548",
+	'\u145a':     "\u0548\u00b7",
+	'\u145b':     "\u00b7\u144f",
+	'\u145c':     "\u144f\u00b7",
+	'\u145d':     "\u00b7\u1450",
+	'\u145e':     "\u1450\u00b7",
+	'\u145f':     "\u00b7\u1451",
+	'\u1460':     "\u1451\u00b7",
+	'\u1461':     "\u00b7\u1455",
+	'\u1462':     "\u1455\u00b7",
+	'\u1463':     "\u00b7\u1456",
+	'\u1464':     "\u1456\u00b7",
+	'\u1467':     "U'",
+	'\u1468':     "\u0548'",
+	'\u1469':     "\u1450'",
+	'\u146a':     "\u1455'",
+	'\u146d':     "P",
+	'\u146f':     "d",
+	'\u1472':     "b",
+	'\u1473':     "b\u0307",
+	'\u1474':     "\u00b7\u146b",
+	'\u1475':     "\u146b\u00b7",
+	'\u1476':     "\u00b7P",
+	'\u1477':     "p\u00b7",
+	'\u1478':     "\u00b7\u146e",
+	'\u1479':     "\u146e\u00b7",
+	'\u147a':     "\u00b7d",
+	'\u147b':     "d\u00b7",
+	'\u147c':     "\u00b7\u1470",
+	'\u147d':     "\u1470\u00b7",
+	'\u147e':     "\u00b7b",
+	'\u147f':     "b\u00b7",
+	'\u1480':     "\u00b7b\u0307",
+	'\u1481':     "b\u0307\u00b7",
+	'\u1485':     "\u146b'",
+	'\u1486':     "P'",
+	'\u1487':     "d'",
+	'\u1488':     "b'",
+	'\u148d':     "J",
+	'\u1492':     "\u00b7\u1489",
+	'\u1493':     "\u1489\u00b7",
+	'\u1494':     "\u00b7\u148b",
+	'\u1495':     "\u148b\u00b7",
+	'\u1496':     "\u00b7\u148c",
+	'\u1497':     "\u148c\u00b7",
+	'\u1498':     "\u00b7J",
+	'\u1499':     "J\u00b7",
+	'\u149a':     "\u00b7\u148e",
+	'\u149b':     "\u148e\u00b7",
+	'\u149c':     "\u00b7\u1490",
+	'\u149d':     "\u1490\u00b7",
+	'\u149e':     "\u00b7\u1491",
+	'\u149f':     "\u1491\u00b7",
+	'\u14a5':     "\u0393",
+	'\u14aa':     "L",
+	'\u14ac':     "\u00b7\u14a3",
+	'\u14ad':     "\u14a3\u00b7",
+	'\u14ae':     "\u00b7\u0393",
+	'\u14af':     "\u0393\u00b7",
+	'\u14b0':     "\u00b7\u14a6",
+	'\u14b1':     "\u14a6\u00b7",
+	'\u14b2':     "\u00b7\u14a7",
+	'\u14b3':     "\u14a7\u00b7",
+	'\u14b4':     "\u00b7\u14a8",
+	'\u14b5':     "\u14a8\u00b7",
+	'\u14b6':     "\u00b7L",
+	'\u14b7':     "l\u00b7",
+	'\u14b8':     "\u00b7\u14ab",
+	'\u14b9':     "\u14ab\u00b7",
+	'\u14bf':     "2",
+	'\u14c9':     "\u00b7\u14c0",
+	'\u14ca':     "\u14c0\u00b7",
+	'\u14cb':     "\u00b7\u14c7",
+	'\u14cc':     "\u14c7\u00b7",
+	'\u14cd':     "\u00b7\u14c8",
+	'\u14ce':     "\u14c8\u00b7",
+	'\u14d1':     "\u1421",
+	'\u14dc':     "\u00b7\u14d3",
+	'\u14dd':     "\u14d3\u00b7",
+	'\u14de':     "\u00b7\u14d5",
+	'\u14df':     "\u14d5\u00b7",
+	'\u14e0':     "\u00b7\u14d6",
+	'\u14e1':     "\u14d6\u00b7",
+	'\u14e2':     "\u00b7\u14d7",
+	'\u14e3':     "\u14d7\u00b7",
+	'\u14e4':     "\u00b7\u14d8",
+	'\u14e5':     "\u14d8\u00b7",
+	'\u14e6':     "\u00b7\u14da",
+	'\u14e7':     "\u14da\u00b7",
+	'\u14e8':     "\u00b7\u14db",
+	'\u14e9':     "\u14db\u00b7",
+	'\u14f6':     "\u00b7\u14ed",
+	'\u14f7':     "\u14ed\u00b7",
+	'\u14f8':     "\u00b7\u14ef",
+	'\u14f9':     "\u14ef\u00b7",
+	'\u14fa':     "\u00b7\u14f0",
+	'\u14fb':     "\u14f0\u00b7",
+	'\u14fc':     "\u00b7\u14f1",
+	'\u14fd':     "\u14f1\u00b7",
+	'\u14fe':     "\u00b7\u14f2",
+	'\u14ff':     "\u14f2\u00b7",
+	'\u1500':     "\u00b7\u14f4",
+	'\u1501':     "\u14f4\u00b7",
+	'\u1502':     "\u00b7\u14f5",
+	'\u1503':     "\u14f5\u00b7",
+	'\u150c':     "\u150b<",
+	'\u150d':     "\u150b\u1455",
+	'\u150e':     "\u150bb",
+	'\u150f':     "\u150b\u1490",
+	'\u1517':     "\u00b7\u1510",
+	'\u1518':     "\u1510\u00b7",
+	'\u1519':     "\u00b7\u1511",
+	'\u151a':     "\u1511\u00b7",
+	'\u151b':     "\u00b7\u1512",
+	'\u151c':     "\u1512\u00b7",
+	'\u151d':     "\u00b7\u1513",
+	'\u151e':     "\u1513\u00b7",
+	'\u151f':     "\u00b7\u1514",
+	'\u1520':     "\u1514\u00b7",
+	'\u1521':     "\u00b7\u1515",
+	'\u1522':     "\u1515\u00b7",
+	'\u1523':     "\u00b7\u1516",
+	'\u1524':     "\u1516\u00b7",
+	'\u152f':     "\u00b74",
+	'\u1530':     "4\u00b7",
+	'\u1531':     "\u00b7\u1528",
+	'\u1532':     "\u1528\u00b7",
+	'\u1533':     "\u00b7\u1529",
+	'\u1534':     "\u1529\u00b7",
+	'\u1535':     "\u00b7\u152a",
+	'\u1536':     "\u152a\u00b7",
+	'\u1537':     "\u00b7\u152b",
+	'\u1538':     "\u152b\u00b7",
+	'\u1539':     "\u00b7\u152d",
+	'\u153a':     "\u152d\u00b7",
+	'\u153b':     "\u00b7\u152e",
+	'\u153c':     "\u152e\u00b7",
+	'\u1540':     "\u1429",
+	'\u1541':     "x",
+	'\u154e':     "\u00b7\u154c",
+	'\u154f':     "\u154c\u00b7",
+	'\u155b':     "\u00b7\u155a",
+	'\u155c':     "\u155a\u00b7",
+	'\u1568':     "\u00b7\u1567",
+	'\u1569':     "\u1567\u00b7",
+	'\u1577':     "\u1e9f",
+	'\u157c':     "H",
+	'\u157d':     "x",
+	'\u157e':     "\u1550\u146c",
+	'\u157f':     "\u1550P",
+	'\u1580':     "\u1550\u146e",
+	'\u1581':     "\u1550d",
+	'\u1582':     "\u1550\u1470",
+	'\u1583':     "\u1550b",
+	'\u1584':     "\u1550b\u0307",
+	'\u1585':     "\u1550\u1483",
+	'\u1587':     "R",
+	'\u158e':     "\u1595\u148a",
+	'\u158f':     "\u1595\u148b",
+	'\u1590':     "\u1595\u148c",
+	'\u1591':     "\u1595J",
+	'\u1592':     "\u1595\u148e",
+	'\u1593':     "\u1595\u1490",
+	'\u1594':     "\u1595\u1491",
+	'\u15af':     "b",
+	'\u15b4':     "F",
+	'\u15b5':     "\u2132",
+	'\u15b7':     "\ua7fb",
+	'\u15c4':     "\u2c6f",
+	'\u15c5':     "A",
+	'\u15de':     "D",
+	'\u15ea':     "D",
+	'\u15ef':     "\u0460",
+	'\u15f0':     "M",
+	'\u15f7':     "B",
+	'\u1602':     "\u1490",
+	'\u1603':     "\u1489",
+	'\u1604':     "\u14d3",
+	'\u1607':     "\u14da",
+	'\u1622':     "\u1543",
+	'\u1623':     "\u1546",
+	'\u1624':     "\u154a",
+	'\u162e':     "\u01b1",
+	'\u162f':     "\u03a9",
+	'\u1634':     "\u01b1",
+	'\u1635':     "\u03a9",
+	'\u166d':     "X",
+	'\u166e':     "x",
+	'\u166f':     "\u1550\u146b",
+	'\u1670':     "\u1595\u1489",
+	'\u1671':     "\u1596\u148b",
+	'\u1672':     "\u1596\u148c",
+	'\u1673':     "\u1596J",
+	'\u1674':     "\u1596\u148e",
+	'\u1675':     "\u1596\u1490",
+	'\u1676':     "\u1596\u1491",
+	'\u1677':     "\u15a7\u00b7",
+	'\u1678':     "\u15a8\u00b7",
+	'\u1679':     "\u15a9\u00b7",
+	'\u167a':     "\u15aa\u00b7",
+	'\u167b':     "\u15ab\u00b7",
+	'\u167c':     "\u15ac\u00b7",
+	'\u167d':     "\u15ad\u00b7",
+	'\u16b2':     "<",
+	'\u16b7':     "X",
+	'\u16c1':     "l",
+	'\u16c2':     "\u16bd",
+	'\u16cc':     "'",
+	'\u16d5':     "K",
+	'\u16d6':     "M",
+	'\u16d8':     "\u03a8",
+	'\u16e1':     "\u16bc",
+	'\u16eb':     "\u00b7",
+	'\u16ec':     ":",
+	'\u16ed':     "+",
+	'\u16f0':     "\u03a6",
+	'\u1735':     "/",
+	'\u17a3':     "\u17a2",
+	'\u17b7':     "\u0e34",
+	'\u17b8':     "\u0e35",
+	'\u17b9':     "\u0e36",
+	'\u17ba':     "\u0e37",
+	'\u17c6':     "\u030a",
+	'\u17cb':     "\u0e48",
+	'\u17d3':     "\u030a",
+	'\u17d4':     "\u0e2f",
+	'\u17d5':     "\u0e5a",
+	'\u17d9':     "\u0e4f",
+	'\u17da':     "\u0e5b",
+	'\u1803':     ":",
+	'\u1809':     ":",
+	'\u1855':     "\u1835",
+	'\u1896':     "\u185c",
+	'\u18b3':     "\u00b7\u18b1",
+	'\u18b6':     "\u00b7\u18b4",
+	'\u18b9':     "\u00b7\u18b8",
+	'\u18c2':     "\u00b7\u18c0",
+	'\u18c6':     "\u00b7\u14c2",
+	'\u18c7':     "\u14c2\u00b7",
+	'\u18c8':     "\u00b7\u14c3",
+	'\u18c9':     "\u14c3\u00b7",
+	'\u18ca':     "\u00b7\u14c4",
+	'\u18cb':     "\u14c4\u00b7",
+	'\u18cc':     "\u00b7\u14c5",
+	'\u18cd':     "\u14c5\u00b7",
+	'\u18ce':     "\u00b7\u1543",
+	'\u18cf':     "\u00b7\u1546",
+	'\u18d0':     "\u00b7\u1547",
+	'\u18d1':     "\u00b7\u1548",
+	'\u18d2':     "\u00b7\u1549",
+	'\u18d3':     "\u00b7\u154b",
+	'\u18db':     "\u18f5",
+	'\u18dc':     "\u18df\u141e",
+	'\u18dd':     "\u141e\u18df",
+	'\u18e0':     "\u1543\u00b7",
+	'\u18e3':     "\u155e\u00b7",
+	'\u18e4':     "\u1566\u00b7",
+	'\u18e5':     "\u156b\u00b7",
+	'\u18e8':     "\u1586\u00b7",
+	'\u18ea':     "\u1597\u00b7",
+	'\u18ed':     "\u0460\u00b7",
+	'\u18f0':     "\u15f4\u00b7",
+	'\u18f2':     "\u161b\u00b7",
+	'\u19d0':     "\u199e",
+	'\u19d1':     "\u19b1",
+	'\u1a80':     "\u1a45",
+	'\u1a90':     "\u1a45",
+	'\u1aa9':     "\u1aa8\u1aa8",
+	'\u1aab':     "\u1aaa\u1aa8",
+	'\u1ab4':     "\u06db",
+	'\u1ab7':     "\u0328",
+	'\u1b52':     "\u1b0d",
+	'\u1b53':     "\u1b11",
+	'\u1b58':     "\u1b28",
+	'\u1b5c':     "\u1b50",
+	'\u1b5f':     "\u1b5e\u1b5e",
+	'\u1c3c':     "\u1c3b\u1c3b",
+	'\u1c7f':     "\u1c7e\u1c7e",
+	'\u1cd0':     "\u0302",
+	'\u1cd2':     "\u0304",
+	'\u1cd3':     "''",
+	'\u1cd5':     "\u032b",
+	'\u1cd8':     "\u032e",
+	'\u1cd9':     "\u032d",
+	'\u1cda':     "\u030e",
+	'\u1cdc':     "\u0329",
+	'\u1cdd':     "\u0323",
+	'\u1cde':     "\u0324",
+	'\u1ced':     "\u0316",
+	'\u1d04':     "c",
+	'\u1d08':     "\u025c",
+	'\u1d0b':     "\u0138",
+	'\u1d0d':     "\u028d",
+	'\u1d0f':     "o",
+	'\u1d10':     "\u0254",
+	'\u1d11':     "o",
+	'\u1d14':     "\u01ddo",
+	'\u1d1c':     "u",
+	'\u1d20':     "v",
+	'\u1d21':     "w",
+	'\u1d22':     "z",
+	'\u1d24':     "\u01a8",
+	'\u1d26':     "r",
+	'\u1d27':     "\u028c",
+	'\u1d28':     "\u03c0",
+	'\u1d29':     "\u1d18",
+	'\u1d2b':     "\u043b",
+	'\u1d6b':     "ue",
+	'\u1d6e':     "f\u0334",
+	'\u1d6f':     "rn\u0334",
+	'\u1d70':     "n\u0334",
+	'\u1d72':     "r\u0334",
+	'\u1d73':     "\u027e\u0334",
+	'\u1d74':     "s\u0334",
+	'\u1d75':     "t\u0334",
+	'\u1d76':     "z\u0334",
+	'\u1d7b':     "i\u0335",
+	'\u1d7c':     "i\u0335",
+	'\u1d7d':     "p\u0335",
+	'\u1d7e':     "u\u0335",
+	'\u1d7f':     "\u028a\u0335",
+	'\u1d83':     "g",
+	'\u1d8c':     "y",
+	'\u1d90':     "\u024b",
+	'\u1dee':     "\u2dec",
+	'\u1e9d':     "f",
+	'\u1eff':     "y",
+	'\u2010':     "-",
+	'\u2012':     "-",
+	'\u2013':     "-",
+	'\u2014':     "\u30fc",
+	'\u2015':     "\u30fc",
+	'\u2016':     "ll",
+	'\u2018':     "'",
+	'\u2019':     "'",
+	'\u201a':     ",",
+	'\u201b':     "'",
+	'\u201c':     "''",
+	'\u201d':     "''",
+	'\u201f':     "''",
+	'\u2022':     "\u00b7",
+	'\u2027':     "\u00b7",
+	'\u2030':     "\u00ba/\u2080\u2080",
+	'\u2031':     "\u00ba/\u2080\u2080\u2080",
+	'\u2032':     "'",
+	'\u2035':     "'",
+	'\u2039':     "<",
+	'\u203a':     ">",
+	'\u2041':     "/",
+	'\u2043':     "-",
+	'\u2044':     "/",
+	'\u204e':     "*",
+	'\u2052':     "\u00ba/\u2080",
+	'\u2053':     "~",
+	'\u205a':     ":",
+	'\u205d':     "\u2d57",
+	'\u205e':     "\u2d42",
+	'\u20a1':     "C\u20eb",
+	'\u20a4':     "\u00a3",
+	'\u20a5':     "rn\u0338",
+	'\u20a9':     "W\u0335",
+	'\u20ab':     "d\u0335\u0331",
+	'\u20ac':     "\ua792",
+	'\u20ad':     "K\u0335",
+	'\u20ae':     "T\u20eb",
+	'\u20b6':     "lt",
+	'\u20bd':     "\u0554",
+	'\u20db':     "\u06db",
+	'\u2108':     "\u042d",
+	'\u2127':     "\u01b1",
+	'\u2129':     "\u027f",
+	'\u212e':     "e",
+	'\u2141':     "\ua4e8",
+	'\u2142':     "\ua4f6",
+	'\u2143':     "\U00016f00",
+	'\u2184':     "\u0254",
+	'\u2191':     "\u16cf",
+	'\u2195':     "\u16e8",
+	'\u21b5':     "\u21b2",
+	'\u21ba':     "\U0001f10e",
+	'\u21be':     "\u16da",
+	'\u21bf':     "\u16d0",
+	'\u2200':     "\u2c6f",
+	'\u2203':     "\u018e",
+	'\u2206':     "\u0394",
+	'\u220f':     "\u03a0",
+	'\u2211':     "\u01a9",
+	'\u2212':     "-",
+	'\u2214':     "+\u0307",
+	'\u2215':     "/",
+	'\u2216':     "\\",
+	'\u2217':     "*",
+	'\u2218':     "\u00b0",
+	'\u2219':     "\u00b7",
+	'\u221e':     "oo",
+	'\u2223':     "l",
+	'\u2225':     "ll",
+	'\u2228':     "v",
+	'\u2229':     "\u0548",
+	'\u222a':     "U",
+	'\u222b':     "\u0283",
+	'\u2236':     ":",
+	'\u2238':     "-\u0307",
+	'\u223c':     "~",
+	'\u2250':     "=\u0307",
+	'\u2251':     "=\u0323\u0307",
+	'\u2257':     "=\u030a",
+	'\u2259':     "=\u0302",
+	'\u225a':     "=\u0306",
+	'\u225e':     "=\u036b",
+	'\u2263':     "\u2261",
+	'\u226a':     "<<",
+	'\u226b':     ">>",
+	'\u2282':     "\u1455",
+	'\u2283':     "\u1450",
+	'\u2295':     "\U000102a8",
+	'\u2296':     "O\u0335",
+	'\u2299':     "\u0298",
+	'\u229d':     "O\u0335",
+	'\u22a4':     "T",
+	'\u22a5':     "\ua4d5",
+	'\u22c0':     "\u2227",
+	'\u22c1':     "v",
+	'\u22c2':     "\u0548",
+	'\u22c3':     "U",
+	'\u22c4':     "\u16dc",
+	'\u22c5':     "\u00b7",
+	'\u22c8':     "\u16de",
+	'\u22d6':     "<\u00b7",
+	'\u22d7':     "\u00b7>",
+	'\u22d8':     "<<<",
+	'\u22d9':     ">>>",
+	'\u22ee':     "\u2d57",
+	'\u22ef':     "\u00b7\u00b7\u00b7",
+	'\u22f4':     "\ua793",
+	'\u22ff':     "E",
+	'\u2300':     "\u2205",
+	'\u2325':     "\u2324",
+	'\u2341':     "\u303c",
+	'\u2359':     "\u0394\u0332",
+	'\u235a':     "\u16dc\u0332",
+	'\u235c':     "\u00b0\u0332",
+	'\u235f':     "\u229b",
+	'\u2361':     "T\u0308",
+	'\u2362':     "\u2207\u0308",
+	'\u2363':     "\u22c6\u0308",
+	'\u2364':     "\u00b0\u0308",
+	'\u2365':     "\u0629",
+	'\u2368':     "~\u0308",
+	'\u2369':     "\u1435",
+	'\u236b':     "\u2207\u0334",
+	'\u236c':     "O\u0335",
+	'\u2373':     "i",
+	'\u2374':     "p",
+	'\u2375':     "\u03c9",
+	'\u2376':     "a\u0332",
+	'\u2377':     "\ua793\u0332",
+	'\u2378':     "i\u0332",
+	'\u2379':     "\u03c9\u0332",
+	'\u237a':     "a",
+	'\u237f':     "\u16bd",
+	'\u239c':     "\u4e28",
+	'\u239f':     "\u4e28",
+	'\u23a2':     "\u4e28",
+	'\u23a5':     "\u4e28",
+	'\u23aa':     "\u4e28",
+	'\u23ae':     "\u4e28",
+	'\u23c1':     "\u2355",
+	'\u23c2':     "\u234e",
+	'\u23c3':     "\u234b",
+	'\u23c6':     "\u236d",
+	'\u23e8':     "\u2081\u2080",
+	'\u23fc':     "\u23fb",
+	'\u23fd':     "l",
+	'\u23fe':     "\u263e",
+	'\u244a':     "\\\\",
+	'\u2500':     "\u30fc",
+	'\u2501':     "\u30fc",
+	'\u2503':     "\u2502",
+	'\u250f':     "\u250c",
+	'\u2523':     "\u251c",
+	'\u2571':     "/",
+	'\u2573':     "X",
+	'\u2588':     "\u220e",
+	'\u2590':     "\u258c",
+	'\u2594':     "\u02c9",
+	'\u2597':     "\u2596",
+	'\u259d':     "\u2598",
+	'\u25a0':     "\u220e",
+	'\u25b1':     "\u23e5",
+	'\u25b3':     "\u0394",
+	'\u25b7':     "\u22b3",
+	'\u25b8':     "\u25b6",
+	'\u25ba':     "\u25b6",
+	'\u25bd':     "\U000102bc",
+	'\u25c1':     "\u22b2",
+	'\u25c7':     "\u16dc",
+	'\u25ca':     "\u16dc",
+	'\u25cb':     "\u00b0",
+	'\u25ce':     "\u233e",
+	'\u25e0':     "\u2312",
+	'\u25e6':     "\u00b0",
+	'\u2609':     "\u0298",
+	'\u2610':     "\u25a1",
+	'\u2625':     "\U0001099e",
+	'\u2630':     "\u2cb6",
+	'\u2638':     "\u2388",
+	'\u264e':     "\u224f",
+	'\u2662':     "\u16dc",
+	'\u2669':     "\U0001d158\U0001d165",
+	'\u266a':     "\U0001d158\U0001d165\U0001d16e",
+	'\u26ac':     "\u0970",
+	'\u2768':     "(",
+	'\u2769':     ")",
+	'\u276e':     "<",
+	'\u276f':     ">",
+	'\u2772':     "(",
+	'\u2773':     ")",
+	'\u2774':     "{",
+	'\u2775':     "}",
+	'\u2795':     "+",
+	'\u2796':     "-",
+	'\u2797':     "\u00f7",
+	'\u27c2':     "\ua4d5",
+	'\u27c8':     "\\\u1455",
+	'\u27c9':     "\u1450/",
+	'\u27cb':     "/",
+	'\u27cd':     "\\",
+	'\u27d9':     "T",
+	'\u27e8':     "\u276c",
+	'\u27e9':     "\u276d",
+	'\u292b':     "x",
+	'\u292c':     "x",
+	'\u2963':     "\u16d0\u16da",
+	'\u2965':     "\u21c3\u21c2",
+	'\u296e':     "\u16d0\u21c2",
+	'\u296f':     "\u21c3\u16da",
+	'\u2999':     "\u2d42",
+	'\u29b0':     "\u2349",
+	'\u29be':     "\u233e",
+	'\u29c4':     "\u303c",
+	'\u29c5':     "\u2342",
+	'\u29c7':     "\u233b",
+	'\u29d6':     "\U000102c0",
+	'\u29d9':     "\u299a",
+	'\u29f4':     ":\u2192",
+	'\u29f5':     "\\",
+	'\u29f6':     "/\u0304",
+	'\u29f8':     "/",
+	'\u29f9':     "\\",
+	'\u2a00':     "\u0298",
+	'\u2a01':     "\U000102a8",
+	'\u2a02':     "\u2297",
+	'\u2a03':     "\u228d",
+	'\u2a04':     "\u228e",
+	'\u2a05':     "\u2293",
+	'\u2a06':     "\u2294",
+	'\u2a1d':     "\u16de",
+	'\u2a20':     ">>",
+	'\u2a21':     "\u16da",
+	'\u2a22':     "+\u030a",
+	'\u2a23':     "+\u0302",
+	'\u2a24':     "+\u0303",
+	'\u2a25':     "+\u0323",
+	'\u2a26':     "+\u0330",
+	'\u2a27':     "+\u2082",
+	'\u2a29':     "-\u0313",
+	'\u2a2a':     "-\u0323",
+	'\u2a2f':     "x",
+	'\u2a30':     "x\u0307",
+	'\u2a3d':     "\u2319",
+	'\u2a3e':     "\u2a1f",
+	'\u2a3f':     "\u2210",
+	'\u2a6a':     "~\u0307",
+	'\u2a6e':     "=\u20f0",
+	'\u2aa5':     "><",
+	'\u2aaa':     "\u15d5",
+	'\u2aab':     "\u15d2",
+	'\u2ad7':     "\u1450\u1455",
+	'\u2afb':     "///",
+	'\u2afd':     "//",
+	'\u2bec':     "\u219e",
+	'\u2bed':     "\u219f",
+	'\u2bee':     "\u21a0",
+	'\u2bef':     "\u21a1",
+	'\u2c85':     "r",
+	'\u2c89':     "\ua793",
+	'\u2c95':     "\u0138",
+	'\u2c9f':     "o",
+	'\u2ca3':     "p",
+	'\u2ca5':     "c",
+	'\u2cab':     "\u0278",
+	'\u2cad':     "\u03c7",
+	'\u2cb1':     "\u03c9",
+	'\u2cbd':     "\u0448",
+	'\u2ccd':     "\u021d",
+	'\u2cd1':     "\u029f",
+	'\u2ce4':     "\u03d7",
+	'\u2ce9':     "\u2627",
+	'\u2cf9':     "\\\\",
+	'\u2d31':     "O\u0335",
+	'\u2d37':     "\u0245",
+	'\u2d38':     "V",
+	'\u2d39':     "E",
+	'\u2d3a':     "\u018e",
+	'\u2d41':     "O\u0338",
+	'\u2d48':     "\u00b7\u00b7\u00b7",
+	'\u2d49':     "\u01a9",
+	'\u2d4f':     "l",
+	'\u2d51':     "!",
+	'\u2d54':     "O",
+	'\u2d55':     "Q",
+	'\u2d59':     "\u0298",
+	'\u2d5d':     "X",
+	'\u2d60':     "\u0394",
+	'\u2d63':     "\u16ef",
+	'\u2de8':     "\u1ddf",
+	'\u2dea':     "\u030a",
+	'\u2ded':     "\u0368",
+	'\u2def':     "\u036f",
+	'\u2df6':     "\u0363",
+	'\u2df7':     "\u0364",
+	'\u2e1a':     "-\u0308",
+	'\u2e1e':     "~\u0307",
+	'\u2e1f':     "~\u0323",
+	'\u2e26':     "\u1455",
+	'\u2e27':     "\u1450",
+	'\u2e28':     "((",
+	'\u2e29':     "))",
+	'\u2e2a':     "\u2235",
+	'\u2e2b':     "\u2234",
+	'\u2e2c':     "\u2237",
+	'\u2e2e':     "\u061f",
+	'\u2e30':     "\u00b0",
+	'\u2e31':     "\u00b7",
+	'\u2e32':     "\u060c",
+	'\u2e35':     "\u061b",
+	'\u2e39':     "\u1e9f",
+	'\u2e3d':     "\u2d42",
+	'\u2e3f':     "\u00b6",
+	'\u2e40':     "=",
+	'\u2e82':     "\u4e5b",
+	'\u2e83':     "\u4e5a",
+	'\u2e85':     "\u4ebb",
+	'\u2e89':     "\u5202",
+	'\u2e8b':     "\u353e",
+	'\u2e8e':     "\u5140",
+	'\u2e8f':     "\u5c23",
+	'\u2e90':     "\u5c22",
+	'\u2e92':     "\u5df3",
+	'\u2e93':     "\u5e7a",
+	'\u2e94':     "\u5f51",
+	'\u2e96':     "\u5fc4",
+	'\u2e97':     "\u38fa",
+	'\u2e98':     "\u624c",
+	'\u2e99':     "\u6535",
+	'\u2e9b':     "\u65e1",
+	'\u2e9e':     "\u6b7a",
+	'\u2ea0':     "\u6c11",
+	'\u2ea1':     "\u6c35",
+	'\u2ea2':     "\u6c3a",
+	'\u2ea3':     "\u706c",
+	'\u2ea4':     "\u722b",
+	'\u2ea6':     "\u4e2c",
+	'\u2ea8':     "\u72ad",
+	'\u2eab':     "\u7f52",
+	'\u2ead':     "\u793b",
+	'\u2eaf':     "\u7cf9",
+	'\u2eb1':     "\u7f53",
+	'\u2eb2':     "\u7f52",
+	'\u2eb9':     "\u8002",
+	'\u2eba':     "\u8080",
+	'\u2ebe':     "\u8279",
+	'\u2ebf':     "\u8279",
+	'\u2ec0':     "\u8279",
+	'\u2ec1':     "\u864e",
+	'\u2ec2':     "\u8864",
+	'\u2ec3':     "\u8980",
+	'\u2ec4':     "\u897f",
+	'\u2ec5':     "\u89c1",
+	'\u2ec8':     "\u8ba0",
+	'\u2ec9':     "\u8d1d",
+	'\u2ecb':     "\u8f66",
+	'\u2ecc':     "\u8fb6",
+	'\u2ecd':     "\u8fb6",
+	'\u2ecf':     "\u961d",
+	'\u2ed0':     "\u9485",
+	'\u2ed1':     "\u9577",
+	'\u2ed2':     "\u9578",
+	'\u2ed3':     "\u957f",
+	'\u2ed4':     "\u95e8",
+	'\u2ed6':     "\u961d",
+	'\u2ed8':     "\u9752",
+	'\u2ed9':     "\u97e6",
+	'\u2eda':     "\u9875",
+	'\u2edb':     "\u98ce",
+	'\u2edc':     "\u98de",
+	'\u2edd':     "\u98df",
+	'\u2edf':     "\u98e0",
+	'\u2ee0':     "\u9963",
+	'\u2ee2':     "\u9a6c",
+	'\u2ee4':     "\u9b3c",
+	'\u2ee5':     "\u9c7c",
+	'\u2ee8':     "\u9ea6",
+	'\u2ee9':     "\u9ec4",
+	'\u2eeb':     "\u6589",
+	'\u2eec':     "\u9f50",
+	'\u2eed':     "\u6b6f",
+	'\u2eee':     "\u9f7f",
+	'\u2eef':     "\u7adc",
+	'\u2ef0':     "\u9f99",
+	'\u2ef2':     "\u4e80",
+	'\u3003':     "''",
+	'\u3007':     "O",
+	'\u3008':     "\u276c",
+	'\u3009':     "\u276d",
+	'\u3012':     "\u20b8",
+	'\u3014':     "(",
+	'\u3015':     ")",
+	'\u301a':     "\u27e6",
+	'\u301b':     "\u27e7",
+	'\u302c':     "\u0309",
+	'\u302d':     "\u0325",
+	'\u3033':     "/",
+	'\u304f':     "\u276c",
+	'\u309a':     "\u030a",
+	'\u30a0':     "=",
+	'\u30a4':     "\u4ebb",
+	'\u30a8':     "\u5de5",
+	'\u30ab':     "\u529b",
+	'\u30bf':     "\u5915",
+	'\u30c8':     "\u535c",
+	'\u30cb':     "\u4e8c",
+	'\u30ce':     "/",
+	'\u30cf':     "\u516b",
+	'\u30d8':     "\u3078",
+	'\u30ed':     "\u53e3",
+	'\u30fb':     "\u00b7",
+	'\u31d0':     "\u30fc",
+	'\u31d1':     "\u4e28",
+	'\u31d3':     "/",
+	'\u31d4':     "\\",
+	'\u31d6':     "\u4e5b",
+	'\u31da':     "\u4e85",
+	'\u31db':     "\u276c",
+	'\u31df':     "\u4e5a",
+	'\u31e0':     "\u4e59",
+	'\u39b3':     "\u363d",
+	'\u439b':     "\u3588",
+	'\u4420':     "\u3b3b",
+	'\u4e00':     "\u30fc",
+	'\u4e36':     "\\",
+	'\u4e3f':     "/",
+	'\u5002':     "\u4f75",
+	'\u503c':     "\u5024",
+	'\u555f':     "\u5553",
+	'\u56d7':     "\u53e3",
+	'\u586b':     "\u5861",
+	'\u58eb':     "\u571f",
+	'\u58ff':     "\u58ab",
+	'\u5b00':     "\u5aaf",
+	'\u5e32':     "\u5e21",
+	'\u5e50':     "\u3b3a",
+	'\u6238':     "\u6236",
+	'\u6409':     "\u3a41",
+	'\u6663':     "\u403f",
+	'\u6669':     "\u665a",
+	'\u66f6':     "\u3ada",
+	'\u6726':     "\u4443",
+	'\u67ff':     "\u676e",
+	'\u69e9':     "\u3ba3",
+	'\u6a27':     "\u699d",
+	'\u6f59':     "\u6e88",
+	'\u784f':     "\u7814",
+	'\u7d76':     "\u7d55",
+	'\u80a6':     "\u670c",
+	'\u80ca':     "\u6710",
+	'\u80d0':     "\u670f",
+	'\u80f6':     "\u3b35",
+	'\u8101':     "\u6713",
+	'\u8127':     "\u6718",
+	'\u8141':     "\u80fc",
+	'\u81a7':     "\u6723",
+	'\u853f':     "\u848d",
+	'\u8641':     "\u8637",
+	'\u8a1e':     "\u46b6",
+	'\u8a7d':     "\u8a2e",
+	'\u8b8f':     "\u8b86",
+	'\u8c63':     "\u8c5c",
+	'\u8d86':     "\u8d7f",
+	'\u8dfa':     "\u8de5",
+	'\u8e9b':     "\u8e97",
+	'\u8f27':     "\u8eff",
+	'\u90de':     "\u90ce",
+	'\u93ae':     "\u93ad",
+	'\u96b8':     "\u96b7",
+	'\u9e43':     "\u9e42",
+	'\u9ed2':     "\u9ed1",
+	'\u9fc3':     "\u4039",
+	'\ua494':     "\ua2cd",
+	'\ua49c':     "\ua0c0",
+	'\ua49e':     "\ua04a",
+	'\ua4a7':     "\ua458",
+	'\ua4a8':     "\ua132",
+	'\ua4ac':     "\ua050",
+	'\ua4b0':     "\ua3c2",
+	'\ua4ba':     "\ua3bf",
+	'\ua4be':     "\ua2b1",
+	'\ua4bf':     "\ua259",
+	'\ua4c0':     "\ua3ab",
+	'\ua4c2':     "\ua3b5",
+	'\ua4d0':     "B",
+	'\ua4d1':     "P",
+	'\ua4d2':     "d",
+	'\ua4d3':     "D",
+	'\ua4d4':     "T",
+	'\ua4d6':     "G",
+	'\ua4d7':     "K",
+	'\ua4d9':     "J",
+	'\ua4da':     "C",
+	'\ua4db':     "\u0186",
+	'\ua4dc':     "Z",
+	'\ua4dd':     "F",
+	'\ua4de':     "\u2132",
+	'\ua4df':     "M",
+	'\ua4e0':     "N",
+	'\ua4e1':     "L",
+	'\ua4e2':     "S",
+	'\ua4e3':     "R",
+	'\ua4e5':     "\u0245",
+	'\ua4e6':     "V",
+	'\ua4e7':     "H",
+	'\ua4ea':     "W",
+	'\ua4eb':     "X",
+	'\ua4ec':     "Y",
+	'\ua4ed':     "\u1660",
+	'\ua4ee':     "A",
+	'\ua4ef':     "\u2c6f",
+	'\ua4f0':     "E",
+	'\ua4f1':     "\u018e",
+	'\ua4f2':     "l",
+	'\ua4f3':     "O",
+	'\ua4f4':     "U",
+	'\ua4f5':     "\u0548",
+	'\ua4f7':     "\u15e1",
+	'\ua4f8':     ".",
+	'\ua4f9':     ",",
+	'\ua4fa':     "..",
+	'\ua4fb':     ".,",
+	'\ua4fd':     ":",
+	'\ua4fe':     "-.",
+	'\ua4ff':     "=",
+	'\ua60e':     ".",
+	'\ua645':     "\u01a8",
+	'\ua647':     "i",
+	'\ua64d':     "\u03c9",
+	'\ua651':     "\u02c9bi",
+	'\ua66f':     "\u20e9",
+	'\ua67c':     "\u0306",
+	'\ua67e':     "\u02c7",
+	'\ua695':     "h\u0314",
+	'\ua699':     "oo",
+	'\ua6a1':     "\u0418",
+	'\ua6b0':     "\u16b9",
+	'\ua6b1':     "\u2c75",
+	'\ua6cd':     "\u02a1",
+	'\ua6ce':     "\u0245",
+	'\ua6db':     "\u03a0",
+	'\ua6df':     "V",
+	'\ua6eb':     "?",
+	'\ua6ef':     "2",
+	'\ua6f0':     "\u0302",
+	'\ua6f1':     "\u0304",
+	'\ua6f4':     "\ua6f3\ua6f3",
+	'\ua714':     "\u02eb",
+	'\ua716':     "\u02ea",
+	'\ua729':     "t\u021d",
+	'\ua731':     "s",
+	'\ua733':     "aa",
+	'\ua735':     "ao",
+	'\ua737':     "au",
+	'\ua739':     "av",
+	'\ua73b':     "av",
+	'\ua73d':     "ay",
+	'\ua74b':     "o\u0335",
+	'\ua74f':     "oo",
+	'\ua761':     "w\u0326",
+	'\ua76b':     "\u021d",
+	'\ua777':     "tf",
+	'\ua778':     "&",
+	'\ua77a':     "\ua779",
+	'\ua789':     ":",
+	'\ua78c':     "'",
+	'\ua78f':     "\u00b7",
+	'\ua795':     "\ua727",
+	'\ua799':     "f",
+	'\ua79b':     "\U0001043a",
+	'\ua79d':     "\u029a",
+	'\ua79f':     "u",
+	'\ua7b5':     "\u00df",
+	'\ua7b7':     "\u03c9",
+	'\ua7f7':     "\u30fc",
+	'\ua830':     "\u0964",
+	'\ua960':     "\u1103\u1106",
+	'\ua961':     "\u1103\u1107",
+	'\ua962':     "\u1103\u1109",
+	'\ua963':     "\u1103\u110c",
+	'\ua964':     "\u1105\u1100",
+	'\ua965':     "\u1105\u1100\u1100",
+	'\ua966':     "\u1105\u1103",
+	'\ua967':     "\u1105\u1103\u1103",
+	'\ua968':     "\u1105\u1106",
+	'\ua969':     "\u1105\u1107",
+	'\ua96a':     "\u1105\u1107\u1107",
+	'\ua96b':     "\u1105\u1107\u110b",
+	'\ua96c':     "\u1105\u1109",
+	'\ua96d':     "\u1105\u110c",
+	'\ua96e':     "\u1105\u110f",
+	'\ua96f':     "\u1106\u1100",
+	'\ua970':     "\u1106\u1103",
+	'\ua971':     "\u1106\u1109",
+	'\ua972':     "\u1107\u1109\u1110",
+	'\ua973':     "\u1107\u110f",
+	'\ua974':     "\u1107\u1112",
+	'\ua975':     "\u1109\u1109\u1107",
+	'\ua976':     "\u110b\u1105",
+	'\ua977':     "\u110b\u1112",
+	'\ua978':     "\u110c\u110c\u1112",
+	'\ua979':     "\u1110\u1110",
+	'\ua97a':     "\u1111\u1112",
+	'\ua97b':     "\u1112\u1109",
+	'\ua97c':     "\u1159\u1159",
+	'\ua992':     "\u2c3f",
+	'\ua9a3':     "\ua99d",
+	'\ua9c6':     "\ua9d0",
+	'\ua9cf':     "\u0662",
+	'\uaa53':     "\uaa01",
+	'\uaa56':     "\uaa23",
+	'\uab32':     "e",
+	'\uab35':     "f",
+	'\uab3d':     "o",
+	'\uab3e':     "o\u0338",
+	'\uab3f':     "\u0254\u0338",
+	'\uab41':     "\u01ddo\u0338",
+	'\uab42':     "\u01ddo\u0335",
+	'\uab47':     "r",
+	'\uab48':     "r",
+	'\uab4d':     "\u0283",
+	'\uab4e':     "u",
+	'\uab52':     "u",
+	'\uab53':     "\u03c7",
+	'\uab55':     "\u03c7",
+	'\uab5a':     "y",
+	'\uab60':     "\u0459",
+	'\uab62':     "\u0254e",
+	'\uab63':     "uo",
+	'\ud7b0':     "\u1169\u1167",
+	'\ud7b1':     "\u1169\u1169\u4e28",
+	'\ud7b2':     "\u116d\u1161",
+	'\ud7b3':     "\u116d\u1161\u4e28",
+	'\ud7b4':     "\u116d\u1165",
+	'\ud7b5':     "\u116e\u1167",
+	'\ud7b6':     "\u116e\u4e28\u4e28",
+	'\ud7b7':     "\u1172\u1161\u4e28",
+	'\ud7b8':     "\u1172\u1169",
+	'\ud7b9':     "\u30fc\u1161",
+	'\ud7ba':     "\u30fc\u1165",
+	'\ud7bb':     "\u30fc\u1165\u4e28",
+	'\ud7bc':     "\u30fc\u1169",
+	'\ud7bd':     "\u4e28\u1163\u1169",
+	'\ud7be':     "\u4e28\u1163\u4e28",
+	'\ud7bf':     "\u4e28\u1167",
+	'\ud7c0':     "\u4e28\u1167\u4e28",
+	'\ud7c1':     "\u4e28\u1169\u4e28",
+	'\ud7c2':     "\u4e28\u116d",
+	'\ud7c3':     "\u4e28\u1172",
+	'\ud7c4':     "\u4e28\u4e28",
+	'\ud7c5':     "\u119e\u1161",
+	'\ud7c6':     "\u119e\u1165\u4e28",
+	'\ud7cb':     "\u1102\u1105",
+	'\ud7cc':     "\u1102\u110e",
+	'\ud7cd':     "\u1103\u1103",
+	'\ud7ce':     "\u1103\u1103\u1107",
+	'\ud7cf':     "\u1103\u1107",
+	'\ud7d0':     "\u1103\u1109",
+	'\ud7d1':     "\u1103\u1109\u1100",
+	'\ud7d2':     "\u1103\u110c",
+	'\ud7d3':     "\u1103\u110e",
+	'\ud7d4':     "\u1103\u1110",
+	'\ud7d5':     "\u1105\u1100\u1100",
+	'\ud7d6':     "\u1105\u1100\u1112",
+	'\ud7d7':     "\u1105\u1105\u110f",
+	'\ud7d8':     "\u1105\u1106\u1112",
+	'\ud7d9':     "\u1105\u1107\u1103",
+	'\ud7da':     "\u1105\u1107\u1111",
+	'\ud7db':     "\u1105\u114c",
+	'\ud7dc':     "\u1105\u1159\u1112",
+	'\ud7dd':     "\u1105\u110b",
+	'\ud7de':     "\u1106\u1102",
+	'\ud7df':     "\u1106\u1102\u1102",
+	'\ud7e0':     "\u1106\u1106",
+	'\ud7e1':     "\u1106\u1107\u1109",
+	'\ud7e2':     "\u1106\u110c",
+	'\ud7e3':     "\u1107\u1103",
+	'\ud7e4':     "\u1107\u1105\u1111",
+	'\ud7e5':     "\u1107\u1106",
+	'\ud7e6':     "\u1107\u1107",
+	'\ud7e7':     "\u1107\u1109\u1103",
+	'\ud7e8':     "\u1107\u110c",
+	'\ud7e9':     "\u1107\u110e",
+	'\ud7ea':     "\u1109\u1106",
+	'\ud7eb':     "\u1109\u1107\u110b",
+	'\ud7ec':     "\u1109\u1109\u1100",
+	'\ud7ed':     "\u1109\u1109\u1103",
+	'\ud7ee':     "\u1109\u1140",
+	'\ud7ef':     "\u1109\u110c",
+	'\ud7f0':     "\u1109\u110e",
+	'\ud7f1':     "\u1109\u1110",
+	'\ud7f2':     "\u1105\u1112",
+	'\ud7f3':     "\u1140\u1107",
+	'\ud7f4':     "\u1140\u1107\u110b",
+	'\ud7f5':     "\u114c\u1106",
+	'\ud7f6':     "\u114c\u1112",
+	'\ud7f7':     "\u110c\u1107",
+	'\ud7f8':     "\u110c\u1107\u1107",
+	'\ud7f9':     "\u110c\u110c",
+	'\ud7fa':     "\u1111\u1109",
+	'\ud7fb':     "\u1111\u1110",
+	'\ufd3e':     "(",
+	'\ufd3f':     ")",
+	'\U00010101': "\u00b7",
+	'\U0001018e': "N\u030a",
+	'\U00010196': "X\u0335",
+	'\U00010197': "V\u0335",
+	'\U00010198': "l\u0335l\u0335S\u0335",
+	'\U00010199': "l\u0335l\u0335",
+	'\U000101a0': "\u2ce8",
+	'\U00010282': "B",
+	'\U00010285': "\u0394",
+	'\U00010286': "E",
+	'\U00010287': "F",
+	'\U0001028a': "l",
+	'\U0001028d': "\u0245",
+	'\U00010290': "X",
+	'\U00010292': "O",
+	'\U00010294': "\u16dc",
+	'\U00010295': "P",
+	'\U00010296': "S",
+	'\U00010297': "T",
+	'\U0001029b': "+",
+	'\U000102a0': "A",
+	'\U000102a1': "B",
+	'\U000102a2': "C",
+	'\U000102a3': "\u0394",
+	'\U000102a5': "F",
+	'\U000102ab': "O",
+	'\U000102ad': "\u03d8",
+	'\U000102b0': "M",
+	'\U000102b1': "T",
+	'\U000102b2': "Y",
+	'\U000102b3': "\u03a6",
+	'\U000102b4': "X",
+	'\U000102b5': "\u03a8",
+	'\U000102b6': "\u03a9",
+	'\U000102b8': "\u2d40",
+	'\U000102cf': "H",
+	'\U000102e1': "\u062f",
+	'\U000102e4': "\u0648",
+	'\U000102e8': "\u0637",
+	'\U000102f2': "\u0635",
+	'\U000102f5': "Z",
+	'\U00010301': "B",
+	'\U00010302': "C",
+	'\U00010309': "l",
+	'\U00010311': "M",
+	'\U00010312': "\u03d8",
+	'\U00010315': "T",
+	'\U00010317': "X",
+	'\U0001031a': "8",
+	'\U0001031f': "*",
+	'\U00010320': "l",
+	'\U00010322': "X",
+	'\U000103d1': "\U00010382",
+	'\U000103d3': "\U00010393",
+	'\U00010429': "\ua793",
+	'\U0001042a': "\u029a",
+	'\U0001042c': "o",
+	'\U0001043d': "c",
+	'\U0001043f': "\u0277",
+	'\U00010442': "\u025e",
+	'\U00010443': "\u029f",
+	'\U00010448': "s",
+	'\U0001044b': "\u0254",
+	'\U0001044d': "\u1d0e",
+	'\U000104a0': "\U00010486",
+	'\U000104d8': "\u028c",
+	'\U000104db': "\u03bb",
+	'\U000104ea': "o",
+	'\U000104eb': "\ua669",
+	'\U000104f6': "u",
+	'\U000104f9': "\u03c8",
+	'\U00010513': "N",
+	'\U00010516': "O",
+	'\U00010518': "K",
+	'\U0001051c': "C",
+	'\U0001051d': "V",
+	'\U00010525': "F",
+	'\U00010526': "L",
+	'\U00010527': "X",
+	'\U00010a3a': "\u0323",
+	'\U00010a50': ".",
+	'\U00010a57': "\U00010a56\U00010a56",
+	'\U00010cfa': "\U00010ca5",
+	'\U00010cfc': "\U00010c82",
+	'\U000110bb': "\u0970",
+	'\U000111c7': "\u0970",
+	'\U000111ca': "\u0323",
+	'\U000111cb': "\u093a",
+	'\U000111db': "\ua8fc",
+	'\U000111dc': "\ua8fb",
+	'\U000111de': "\u2248",
+	'\U00011300': "\u030a",
+	'\U00011413': "\U00011434\U00011442\U00011412",
+	'\U00011419': "\U00011434\U00011442\U00011418",
+	'\U00011424': "\U00011434\U00011442\U00011423",
+	'\U0001142a': "\U00011434\U00011442\U00011429",
+	'\U0001142d': "\U00011434\U00011442\U0001142c",
+	'\U0001142f': "\U00011434\U00011442\U0001142e",
+	'\U0001144c': "\U0001144b\U0001144b",
+	'\U00011492': "\u0998",
+	'\U00011494': "\u099a",
+	'\U00011496': "\u099c",
+	'\U00011498': "\u099e",
+	'\U00011499': "\u099f",
+	'\U0001149b': "\u09a1",
+	'\U0001149d': "\u09b2",
+	'\U0001149e': "\u09a4",
+	'\U0001149f': "\u09a5",
+	'\U000114a0': "\u09a6",
+	'\U000114a1': "\u09a7",
+	'\U000114a2': "\u09a8",
+	'\U000114a3': "\u09aa",
+	'\U000114a7': "\u09ae",
+	'\U000114a8': "\u09af",
+	'\U000114a9': "\u09ac",
+	'\U000114aa': "\u09a3",
+	'\U000114ab': "\u09b0",
+	'\U000114ad': "\u09b7",
+	'\U000114ae': "\u09b8",
+	'\U000114b0': "\u09be",
+	'\U000114b1': "\u09bf",
+	'\U000114b9': "\u09c7",
+	'\U000114bd': "\u09d7",
+	'\U000114bf': "\u0306\u0307",
+	'\U000114c1': "\u0983",
+	'\U000114c2': "\u09cd",
+	'\U000114c3': "\u0323",
+	'\U000114c4': "\u09bd",
+	'\U000114c5': "w\u0307",
+	'\U000114d0': "O",
+	'\U000114d1': "\u09e7",
+	'\U000114d2': "\u09e8",
+	'\U000114d6': "\u09ec",
+	'\U000115d8': "\U00011582",
+	'\U000115d9': "\U00011582",
+	'\U000115da': "\U00011583",
+	'\U000115db': "\U00011584",
+	'\U000115dc': "\U000115b2",
+	'\U000115dd': "\U000115b3",
+	'\U00011642': "\U00011641\U00011641",
+	'\U00011700': "rn",
+	'\U00011706': "v",
+	'\U0001170a': "w",
+	'\U0001170e': "w",
+	'\U0001170f': "w",
+	'\U000118c0': "v",
+	'\U000118c1': "s",
+	'\U000118c2': "F",
+	'\U000118c3': "i",
+	'\U000118c4': "z",
+	'\U000118c6': "7",
+	'\U000118c8': "o",
+	'\U000118ca': "3",
+	'\U000118cc': "9",
+	'\U000118ce': "\ua793",
+	'\U000118d5': "6",
+	'\U000118d6': "9",
+	'\U000118d7': "o",
+	'\U000118d8': "u",
+	'\U000118dc': "y",
+	'\U000118e0': "O",
+	'\U000118e3': "rn",
+	'\U000118e4': "\u0669",
+	'\U000118e5': "Z",
+	'\U000118e6': "W",
+	'\U000118e9': "C",
+	'\U000118ec': "X",
+	'\U000118ef': "W",
+	'\U000118f2': "C",
+	'\U00011ae6': "\U00011ae5\U00011aef",
+	'\U00011ae7': "\U00011ae5\U00011af0",
+	'\U00011ae8': "\U00011ae5\U00011ae5",
+	'\U00011ae9': "\U00011ae5\U00011ae5\U00011aef",
+	'\U00011aea': "\U00011ae5\U00011ae5\U00011af0",
+	'\U00011aec': "\U00011aeb\U00011aef",
+	'\U00011aed': "\U00011aeb\U00011aeb",
+	'\U00011aee': "\U00011aeb\U00011aeb\U00011aef",
+	'\U00011af4': "\U00011af3\U00011aef",
+	'\U00011af5': "\U00011af3\U00011af0",
+	'\U00011af6': "\U00011af3\U00011af3",
+	'\U00011af7': "\U00011af3\U00011af3\U00011aef",
+	'\U00011af8': "\U00011af3\U00011af3\U00011af0",
+	'\U00011c42': "\U00011c41\U00011c41",
+	'\U00011cb2': "\U00011caa",
+	'\U00012038': "\U0001039a",
+	'\U000132f9': "\U0001099e",
+	'\U00016f07': "\u0393",
+	'\U00016f08': "V",
+	'\U00016f0a': "T",
+	'\U00016f16': "L",
+	'\U00016f1a': "\u0394",
+	'\U00016f1c': "\ua658",
+	'\U00016f26': "\ua4f6",
+	'\U00016f28': "l",
+	'\U00016f2d': "\u0190",
+	'\U00016f35': "R",
+	'\U00016f3a': "S",
+	'\U00016f3b': "3",
+	'\U00016f3d': "\u0245",
+	'\U00016f3f': ">",
+	'\U00016f40': "A",
+	'\U00016f42': "U",
+	'\U00016f43': "Y",
+	'\U00016f51': "'",
+	'\U00016f52': "'",
+	'\U0001d114': "{",
+	'\U0001d16d': ".",
+	'\U0001d202': "\u04fe",
+	'\U0001d206': "3",
+	'\U0001d20b': "\u0418",
+	'\U0001d20d': "V",
+	'\U0001d20f': "\\",
+	'\U0001d212': "7",
+	'\U0001d213': "F",
+	'\U0001d214': "\U000102bc",
+	'\U0001d215': "\ua4f6",
+	'\U0001d216': "R",
+	'\U0001d217': "\u2c6f",
+	'\U0001d21a': "O\u0335",
+	'\U0001d21b': "\u2144",
+	'\U0001d21c': "\ua4d5",
+	'\U0001d221': "\u0190",
+	'\U0001d222': "\u0460",
+	'\U0001d22a': "L",
+	'\U0001d22b': "\ua4f6",
+	'\U0001d230': "\ua7fb",
+	'\U0001d236': "<",
+	'\U0001d237': ">",
+	'\U0001d238': "\u228f",
+	'\U0001d239': "\u2290",
+	'\U0001d23a': "/",
+	'\U0001d23b': "\\",
+	'\U0001d23f': "\u16cb",
+	'\U0001d245': "\u0548",
+	'\U0001e8c7': "l",
+	'\U0001e8c8': "\u2220",
+	'\U0001e8c9': "\u0663",
+	'\U0001e8cb': "8",
+	'\U0001e8cc': "\u2202",
+	'\U0001e8cd': "\u2202\u0335",
+	'\U0001f10f': "$\u20e0",
+	'\U0001f16d': "\u33c4\t\u20dd",
+	'\U0001f16e': "C\u20e0",
+	'\U0001f312': "\u263d",
+	'\U0001f318': "\u263e",
+	'\U0001f319': "\u263d",
+	'\U0001f700': "QE",
+	'\U0001f701': "\ua658",
+	'\U0001f702': "\u0394",
+	'\U0001f704': "\U000102bc",
+	'\U0001f707': "AR",
+	'\U0001f708': "V\u1de4",
+	'\U0001f70a': "\u2629",
+	'\U0001f714': "O\u0335",
+	'\U0001f728': "\U000102a8",
+	'\U0001f73a': "\u29df",
+	'\U0001f74c': "C",
+	'\U0001f754': "\u16dc",
+	'\U0001f755': "\u22a1",
+	'\U0001f75c': "sss",
+	'\U0001f75e': "\u224f",
+	'\U0001f768': "T",
+	'\U0001f76b': "MB",
+	'\U0001f76c': "VB",
+	'\U0001f771': "\u22a0",
+	'\U00021fe8': "\u276c",
+}
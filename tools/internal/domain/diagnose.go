@@ -0,0 +1,593 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/text/secure/bidirule"
+	"golang.org/x/text/unicode/bidi"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Check is one of the IDNA2008/TR46 processing steps that Parse
+// applies to domain names.
+type Check int
+
+const (
+	// CheckMapping is the TR46 mapping step (section 4, step 1),
+	// which rejects disallowed and unassigned codepoints.
+	CheckMapping Check = iota + 1
+	// CheckPunycode is the decoding and validation of A-labels
+	// ("xn--" labels) into U-labels (TR46 section 4, step 4).
+	CheckPunycode
+	// CheckHyphens is the hyphen placement rule of RFC 5891 section
+	// 4.2.3.1.
+	CheckHyphens
+	// CheckCombiningMark is the leading combining mark rule of RFC
+	// 5891 section 4.2.3.2.
+	CheckCombiningMark
+	// CheckContextJ is the contextual rule for zero-width joiners and
+	// non-joiners, from RFC 5892 appendix A.1 and A.2.
+	CheckContextJ
+	// CheckStrictASCII is the letters-digits-hyphen rule for ASCII
+	// codepoints, from RFC 1034 section 3.5.
+	CheckStrictASCII
+	// CheckBidi is the Bidi Rule of RFC 5893 section 2.
+	CheckBidi
+	// CheckLength is the DNS length rule of RFC 1035 section 2.3.4:
+	// labels must be 1 to 63 octets long and the name at most 253
+	// octets long, excluding a trailing dot, in A-label form.
+	CheckLength
+	// CheckOther is a validation failure that Diagnose could not
+	// attribute to a more specific step.
+	CheckOther
+)
+
+func (c Check) String() string {
+	switch c {
+	case CheckMapping:
+		return "mapping"
+	case CheckPunycode:
+		return "punycode"
+	case CheckHyphens:
+		return "hyphens"
+	case CheckCombiningMark:
+		return "combining-mark"
+	case CheckContextJ:
+		return "contextj"
+	case CheckStrictASCII:
+		return "strict-ascii"
+	case CheckBidi:
+		return "bidi"
+	case CheckLength:
+		return "length"
+	case CheckOther:
+		return "other"
+	default:
+		return fmt.Sprintf("Check(%d)", int(c))
+	}
+}
+
+// Issue is one validation failure found by Diagnose.
+type Issue struct {
+	// Check is the processing step that failed.
+	Check Check
+	// Label is the 1-based index of the offending label, counting
+	// from the left as in the conventional string form. Label is 0
+	// for issues that affect the entire name.
+	Label int
+	// Pos is the 0-based codepoint offset of the offending codepoint
+	// within its label, or -1 if the issue is not about a specific
+	// codepoint.
+	Pos int
+	// Rune is the offending codepoint, if Pos is not -1.
+	Rune rune
+	// Mapped reports whether the issue was found in the mapped form
+	// of the label, rather than in the original input.
+	Mapped bool
+	// Msg describes the issue.
+	Msg string
+}
+
+func (i Issue) Error() string {
+	var b strings.Builder
+	if i.Mapped {
+		b.WriteString("after mapping, ")
+	}
+	if i.Label > 0 {
+		fmt.Fprintf(&b, "label %d ", i.Label)
+	}
+	b.WriteString(i.Msg)
+	if i.Pos >= 0 {
+		fmt.Fprintf(&b, " (codepoint %d)", i.Pos+1)
+	}
+	return b.String()
+}
+
+// Mapping is one codepoint rewrite applied by the TR46 mapping step,
+// for example case folding or width normalization.
+type Mapping struct {
+	// Label is the 1-based index of the label containing the
+	// codepoint, counting from the left.
+	Label int
+	// Pos is the 0-based codepoint offset within the label of the
+	// original input.
+	Pos int
+	// From is the original codepoint.
+	From rune
+	// To is the replacement text. It is empty if the codepoint was
+	// removed by mapping.
+	To string
+}
+
+func (m Mapping) String() string {
+	if m.To == "" {
+		return fmt.Sprintf("label %d codepoint %d: %U removed", m.Label, m.Pos+1, m.From)
+	}
+	return fmt.Sprintf("label %d codepoint %d: %U mapped to %q", m.Label, m.Pos+1, m.From, m.To)
+}
+
+// Diagnosis is the detailed outcome of processing a domain name
+// string with Parse.
+type Diagnosis struct {
+	// Input is the string that was diagnosed.
+	Input string
+	// Mapped is the input after TR46 mapping, normalization and
+	// A-label decoding, before validation. It may contain invalid
+	// codepoints if the input failed to parse.
+	Mapped string
+	// Mappings are the codepoint rewrites applied by the mapping
+	// step.
+	Mappings []Mapping
+	// Issues are the reasons why Parse rejects the input. Issues is
+	// empty if and only if the input is a valid domain name.
+	Issues []Issue
+	// Name is the parsed domain name, or the zero Name if Issues is
+	// not empty.
+	Name Name
+}
+
+// Err returns the issues in d as an error, or nil if d has no issues.
+func (d *Diagnosis) Err() error {
+	if len(d.Issues) == 0 {
+		return nil
+	}
+	return &ParseError{
+		Input:  d.Input,
+		Issues: d.Issues,
+	}
+}
+
+// ParseError is the error returned by Parse and ParseLabel for
+// invalid inputs.
+type ParseError struct {
+	// Input is the string that failed to parse.
+	Input string
+	// Issues are the validation failures that were found.
+	Issues []Issue
+
+	// err is the underlying IDNA error, if any.
+	err error
+}
+
+func (e *ParseError) Error() string {
+	msgs := make([]string, 0, len(e.Issues))
+	for _, i := range e.Issues {
+		msgs = append(msgs, i.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e *ParseError) Unwrap() error { return e.err }
+
+// Diagnose parses s like Parse, and reports the outcome of each IDNA
+// and TR46 processing step in detail.
+//
+// The validity verdict is always identical to Parse's: Diagnose
+// reports issues if and only if Parse returns an error.
+func Diagnose(s string) *Diagnosis {
+	canonical, err := toCanonical(s)
+	ret := diagnose(s)
+	if err == nil {
+		// Parse is authoritative. Whatever the individual steps
+		// think, a successful parse has no issues.
+		ret.Issues = nil
+		ret.Name = nameFromCanonical(canonical)
+		return ret
+	}
+
+	if len(ret.Issues) == 0 {
+		// We failed to attribute the error to a specific step, but
+		// still need to report that validation failed.
+		ret.Issues = append(ret.Issues, Issue{
+			Check: CheckOther,
+			Pos:   -1,
+			Msg:   strings.TrimPrefix(err.Error(), "idna: "),
+		})
+	}
+	return ret
+}
+
+// parseError returns the ParseError that explains why domainValidator
+// rejected s with err.
+func parseError(s string, err error) error {
+	d := Diagnose(s)
+	if len(d.Issues) == 0 {
+		// Shouldn't happen, Diagnose runs the same validator as
+		// Parse. Safer to report the original error than nothing.
+		return err
+	}
+	return &ParseError{
+		Input:  s,
+		Issues: d.Issues,
+		err:    err,
+	}
+}
+
+// The following IDNA profiles run subsets of domainValidator's
+// checks, so that Diagnose can attribute failures to a single step.
+var (
+	// mapOnly applies TR46 mapping without STD3 restrictions and
+	// without label validation.
+	mapOnly = idna.New(
+		idna.MapForLookup(),
+		idna.Transitional(false),
+		idna.StrictDomainName(false),
+		idna.ValidateLabels(false),
+		idna.RemoveLeadingDots(false),
+	)
+	// mapStrict is mapOnly plus STD3 restrictions.
+	mapStrict = idna.New(
+		idna.MapForLookup(),
+		idna.Transitional(false),
+		idna.ValidateLabels(false),
+		idna.RemoveLeadingDots(false),
+	)
+	// decodeOnly decodes A-labels without any other processing.
+	decodeOnly = idna.New()
+	// joinerCheck applies RFC 5891 label validation, without
+	// mapping.
+	joinerCheck = idna.New(idna.ValidateLabels(true))
+)
+
+const (
+	zwnj = '\u200c'
+	zwj  = '\u200d'
+)
+
+// diagnose runs each step of IDNA processing on s, and records
+// mappings and issues in the returned Diagnosis.
+func diagnose(s string) *Diagnosis {
+	ret := &Diagnosis{Input: s}
+
+	// Step 1: mapping. Map each codepoint individually, so that we
+	// can attribute mappings and disallowed codepoints to their
+	// precise location.
+	var (
+		pre      strings.Builder // mapped, not yet normalized
+		inLabels = []string{""}  // unmapped input labels
+		label    = 1
+		pos      = 0
+	)
+	for i, r := range s {
+		in := string(r)
+		out, err := mapOnly.ToUnicode(in)
+		switch {
+		case r == utf8.RuneError && !strings.HasPrefix(s[i:], string(utf8.RuneError)):
+			ret.Issues = append(ret.Issues, Issue{
+				Check: CheckMapping,
+				Label: label,
+				Pos:   pos,
+				Rune:  r,
+				Msg:   fmt.Sprintf("contains invalid UTF-8 byte %#x", s[i]),
+			})
+			pre.WriteString(in)
+		case err != nil || (strings.ContainsRune(out, utf8.RuneError) && r != utf8.RuneError):
+			ret.Issues = append(ret.Issues, Issue{
+				Check: CheckMapping,
+				Label: label,
+				Pos:   pos,
+				Rune:  r,
+				Msg:   fmt.Sprintf("contains disallowed %U", r),
+			})
+			pre.WriteString(in)
+		case out == "." && in != ".":
+			// Dot-like label separators, e.g. ideographic full stop.
+			pre.WriteString(out)
+		case out != in:
+			ret.Mappings = append(ret.Mappings, Mapping{
+				Label: label,
+				Pos:   pos,
+				From:  r,
+				To:    out,
+			})
+			pre.WriteString(out)
+		default:
+			pre.WriteString(out)
+		}
+
+		if out == "." {
+			inLabels = append(inLabels, "")
+			label++
+			pos = 0
+		} else {
+			inLabels[label-1] += in
+			pos++
+		}
+	}
+
+	labels := strings.Split(pre.String(), ".")
+	if last := len(labels) - 1; last > 0 && labels[last] == "" {
+		// One trailing dot is allowed, see Parse.
+		labels = labels[:last]
+	}
+
+	// Step 2: normalize and decode A-labels.
+	isBidi := false
+	for i, l := range labels {
+		l = norm.NFC.String(l)
+		if strings.HasPrefix(l, "xn--") {
+			u, err := decodeOnly.ToUnicode(l)
+			if err != nil {
+				ret.Issues = append(ret.Issues, Issue{
+					Check: CheckPunycode,
+					Label: i + 1,
+					Pos:   -1,
+					Msg:   fmt.Sprintf("is not a valid A-label: %q", l),
+				})
+			} else {
+				ret.Issues = append(ret.Issues, checkULabel(i+1, u)...)
+				l = u
+			}
+		}
+		labels[i] = l
+		if bidirule.DirectionString(l) == bidi.RightToLeft {
+			isBidi = true
+		}
+	}
+	ret.Mapped = strings.Join(labels, ".")
+	if len(ret.Issues) > 0 {
+		// Later checks assume all codepoints are valid, and would
+		// just produce noise.
+		return ret
+	}
+
+	// Step 3: validate each label.
+	if len(labels) == 1 && labels[0] == "" {
+		ret.Issues = append(ret.Issues, Issue{
+			Check: CheckLength,
+			Pos:   -1,
+			Msg:   "domain name is empty",
+		})
+		return ret
+	}
+	for i, l := range labels {
+		issues := checkLabel(i+1, l, isBidi)
+		for j := range issues {
+			issues[j].Mapped = l != inLabels[i]
+		}
+		ret.Issues = append(ret.Issues, issues...)
+	}
+	if len(ret.Issues) == 0 {
+		ret.Issues = checkDNSLength(labels)
+	}
+
+	return ret
+}
+
+const (
+	// maxLabelLen is the maximum length of a DNS label in octets,
+	// from RFC 1035 section 2.3.4.
+	maxLabelLen = 63
+	// maxNameLen is the maximum length of a DNS name in octets in
+	// its text form, without a trailing dot. RFC 1035 allows 255
+	// octets in wire format, which is 253 in text form.
+	maxNameLen = 253
+)
+
+// checkDNSLength checks the A-label form of labels, the otherwise
+// valid labels of a domain name, against the DNS length limits.
+func checkDNSLength(labels []string) []Issue {
+	var ret []Issue
+	total := len(labels) - 1 // dots between labels
+	for i, l := range labels {
+		a, err := decodeOnly.ToASCII(l)
+		if err != nil {
+			// Shouldn't happen, the label passed all other checks.
+			a = l
+		}
+		total += len(a)
+		if len(a) > maxLabelLen {
+			ret = append(ret, Issue{
+				Check: CheckLength,
+				Label: i + 1,
+				Pos:   -1,
+				Msg:   fmt.Sprintf("is %d octets long in A-label form, more than the maximum of %d", len(a), maxLabelLen),
+			})
+		}
+	}
+	if total > maxNameLen {
+		ret = append(ret, Issue{
+			Check: CheckLength,
+			Pos:   -1,
+			Msg:   fmt.Sprintf("domain name is %d octets long in A-label form, more than the maximum of %d", total, maxNameLen),
+		})
+	}
+	return ret
+}
+
+// checkULabel checks that u, the decoded form of an A-label, is a
+// valid U-label.
+func checkULabel(label int, u string) []Issue {
+	var ret []Issue
+	if !norm.NFC.IsNormalString(u) {
+		ret = append(ret, Issue{
+			Check: CheckPunycode,
+			Label: label,
+			Pos:   -1,
+			Msg:   "decodes to text that is not in Unicode NFC form",
+		})
+	}
+	bad, first := findRunes([]rune(u), func(r rune) bool {
+		out, err := mapOnly.ToUnicode(string(r))
+		return err != nil || out != string(r)
+	})
+	if len(bad) > 0 {
+		ret = append(ret, Issue{
+			Check: CheckPunycode,
+			Label: label,
+			Pos:   first,
+			Rune:  bad[0],
+			Msg:   fmt.Sprintf("decodes to non-canonical %s", listRunes(bad, "%U")),
+		})
+	}
+	return ret
+}
+
+// findRunes returns the distinct codepoints of runes for which match
+// returns true, in order of first appearance, and the position of the
+// first one. It returns -1 as the position if nothing matches.
+func findRunes(runes []rune, match func(rune) bool) (found []rune, first int) {
+	first = -1
+	for pos, r := range runes {
+		if !match(r) {
+			continue
+		}
+		if first < 0 {
+			first = pos
+		}
+		if !slices.Contains(found, r) {
+			found = append(found, r)
+		}
+	}
+	return found, first
+}
+
+// listRunes formats each of runes with format, which takes the rune
+// as its only argument, and joins the results into an English list
+// like "a, b and c".
+func listRunes(runes []rune, format string) string {
+	strs := make([]string, 0, len(runes))
+	for _, r := range runes {
+		strs = append(strs, fmt.Sprintf(format, r))
+	}
+	if len(strs) == 1 {
+		return strs[0]
+	}
+	return strings.Join(strs[:len(strs)-1], ", ") + " and " + strs[len(strs)-1]
+}
+
+// checkLabel runs label-level validation on the mapped label l.
+func checkLabel(label int, l string, isBidi bool) []Issue {
+	if l == "" {
+		return []Issue{{
+			Check: CheckLength,
+			Label: label,
+			Pos:   -1,
+			Msg:   "is empty",
+		}}
+	}
+
+	var ret []Issue
+	runes := []rune(l)
+
+	if len(l) > 4 && l[2:4] == "--" {
+		ret = append(ret, Issue{
+			Check: CheckHyphens,
+			Label: label,
+			Pos:   2,
+			Rune:  '-',
+			Msg:   "has hyphens in the third and fourth positions",
+		})
+	}
+	if runes[0] == '-' {
+		ret = append(ret, Issue{
+			Check: CheckHyphens,
+			Label: label,
+			Pos:   0,
+			Rune:  '-',
+			Msg:   "starts with a hyphen",
+		})
+	}
+	if last := len(runes) - 1; runes[last] == '-' {
+		ret = append(ret, Issue{
+			Check: CheckHyphens,
+			Label: label,
+			Pos:   last,
+			Rune:  '-',
+			Msg:   "ends with a hyphen",
+		})
+	}
+	if unicode.Is(unicode.M, runes[0]) {
+		ret = append(ret, Issue{
+			Check: CheckCombiningMark,
+			Label: label,
+			Pos:   0,
+			Rune:  runes[0],
+			Msg:   fmt.Sprintf("starts with combining mark %U", runes[0]),
+		})
+	}
+
+	if len(ret) == 0 && strings.ContainsAny(l, string(zwj)+string(zwnj)) {
+		// The joiner rules depend on complex context, which we
+		// delegate to x/net/idna. All other things checked by
+		// joinerCheck passed above, so a failure here must be about
+		// joiners.
+		if _, err := joinerCheck.ToUnicode(l); err != nil {
+			joiners, first := findRunes(runes, func(r rune) bool { return r == zwj || r == zwnj })
+			ret = append(ret, Issue{
+				Check: CheckContextJ,
+				Label: label,
+				Pos:   first,
+				Rune:  joiners[0],
+				Msg:   fmt.Sprintf("contains %s in a context where it is not allowed", listRunes(joiners, "%U")),
+			})
+		}
+	}
+
+	// Report each check once per label, listing all the offending
+	// codepoints, so that a label full of spaces or punctuation
+	// doesn't produce a wall of near-identical issues.
+	if bad, first := findRunes(runes, func(r rune) bool {
+		_, err := mapStrict.ToUnicode(string(r))
+		return err != nil
+	}); len(bad) > 0 {
+		msg := fmt.Sprintf("contains %s, which is not a letter, digit or hyphen", listRunes(bad, "%[1]U %[1]q"))
+		if len(bad) > 1 {
+			msg = fmt.Sprintf("contains %s, which are not letters, digits or hyphens", listRunes(bad, "%[1]U %[1]q"))
+		}
+		ret = append(ret, Issue{
+			Check: CheckStrictASCII,
+			Label: label,
+			Pos:   first,
+			Rune:  bad[0],
+			Msg:   msg,
+		})
+	}
+
+	if isBidi {
+		t := bidirule.New()
+		if n, err := t.Span([]byte(l), true); errors.Is(err, bidirule.ErrInvalid) {
+			issue := Issue{
+				Check: CheckBidi,
+				Label: label,
+				Pos:   -1,
+				Msg:   "violates the Bidi Rule",
+			}
+			if n < len(l) {
+				r, _ := utf8.DecodeRuneInString(l[n:])
+				issue.Pos = utf8.RuneCountInString(l[:n])
+				issue.Rune = r
+				issue.Msg = fmt.Sprintf("violates the Bidi Rule with %U", r)
+			}
+			ret = append(ret, issue)
+		}
+	}
+
+	return ret
+}
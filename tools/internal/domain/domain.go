@@ -3,12 +3,12 @@
 package domain
 
 import (
-	"bytes"
 	"cmp"
 	"fmt"
 	"slices"
 	"strings"
 	"sync"
+	"unicode/utf8"
 
 	"golang.org/x/net/idna"
 	"golang.org/x/text/collate"
@@ -39,7 +39,9 @@ type Name struct {
 // Parse parses and validates a domain name string.
 //
 // s is validated and canonicalized into the Unicode form suitable for
-// use in domain registrations, as defined by IDNA2008.
+// use in domain registrations, as defined by IDNA2008. If s is
+// invalid, the returned error is a *ParseError that describes the
+// failed checks (see Diagnose).
 func Parse(s string) (Name, error) {
 	// Note that some documentation around Unicode in domain names and
 	// the PSL state that domain names must be normalized to NFKC. We
@@ -52,11 +54,45 @@ func Parse(s string) (Name, error) {
 	// So, if you found this comment because you were looking for NFKC
 	// normalization logic, that's why you can't find it: this call to
 	// ToUnicode is doing the work.
+	canonical, err := toCanonical(s)
+	if err != nil {
+		return Name{}, parseError(s, err)
+	}
+	return nameFromCanonical(canonical), nil
+}
+
+// toCanonical validates s with domainValidator, and returns its
+// canonical form.
+//
+// x/net/idna treats one trailing dot as the DNS root and doesn't check
+// the label before it, so it accepts names like "foo.." that end in
+// an empty label. It also silently replaces invalid UTF-8 with U+FFFD,
+// which it otherwise rejects, and skips the length checks of
+// VerifyDNSLength when converting to Unicode. toCanonical rejects all
+// of these.
+func toCanonical(s string) (string, error) {
+	if !utf8.ValidString(s) {
+		return "", fmt.Errorf("idna: invalid UTF-8 in %q", s)
+	}
 	canonical, err := domainValidator.ToUnicode(s)
 	if err != nil {
-		return Name{}, err
+		return "", err
+	}
+	labels := strings.Split(strings.TrimSuffix(canonical, "."), ".")
+	if slices.Contains(labels, "") {
+		return "", fmt.Errorf("idna: invalid label %q", s)
+	}
+	// domainValidator only checks DNS lengths when converting to
+	// A-labels, which we don't do.
+	if issues := checkDNSLength(labels); len(issues) > 0 {
+		return "", fmt.Errorf("idna: %v", issues[0])
 	}
+	return canonical, nil
+}
 
+// nameFromCanonical returns the Name for canonical, which must be the
+// output of a successful toCanonical.
+func nameFromCanonical(canonical string) Name {
 	// Note we cannot split on "." first and then use ParseLabel here,
 	// because ToUnicode canonicalizes several other dot-like
 	// codepoints into ".". We have to canonicalize the whole string
@@ -75,9 +111,9 @@ func Parse(s string) (Name, error) {
 		labels: make([]Label, 0, len(labels)),
 	}
 	for _, l := range labels {
-		ret.labels = append(ret.labels, Label{l})
+		ret.labels = append(ret.labels, newLabel(l))
 	}
-	return ret, nil
+	return ret
 }
 
 // String returns the domain name in its canonicalized PSL string
@@ -186,21 +222,40 @@ func (d Name) MustAddPrefix(labels ...Label) Name {
 // Label is a domain name label.
 type Label struct {
 	label string
+	// key is the collation key of label, precomputed so that
+	// comparisons don't have to redo the expensive collation work
+	// every time. See labelKey.
+	key string
+}
+
+// newLabel returns the Label for canonical, which must be a valid
+// U-label in canonical form.
+func newLabel(canonical string) Label {
+	return Label{canonical, labelKey(canonical)}
 }
 
 // ParseLabel parses and validates a domain name label.
 //
 // s is validated and canonicalized into the Unicode form suitable for
-// use in domain registrations, as defined by IDNA2008.
+// use in domain registrations, as defined by IDNA2008, with the same
+// checks as Parse. If s is invalid, or is a domain name with more
+// than one label, the returned error is a *ParseError.
 func ParseLabel(s string) (Label, error) {
-	canonical, err := domainValidator.ToUnicode(s)
+	canonical, err := toCanonical(s)
 	if err != nil {
-		return Label{}, err
+		return Label{}, parseError(s, err)
 	} else if strings.Contains(canonical, ".") {
-		return Label{}, fmt.Errorf("label %q cannot contain a dot", s)
+		return Label{}, &ParseError{
+			Input: s,
+			Issues: []Issue{{
+				Check: CheckOther,
+				Pos:   -1,
+				Msg:   fmt.Sprintf("label %q cannot contain a dot", s),
+			}},
+		}
 	}
 
-	return Label{canonical}, nil
+	return newLabel(canonical), nil
 }
 
 func (l Label) String() string { return l.label }
@@ -252,11 +307,11 @@ func (l Label) Compare(m Label) int {
 	// If two labels aren't equal, we are free to order them however
 	// we want. We choose to order them with the English Unicode
 	// collation.
-	if res := compareLabel(l, m); res != 0 {
+	if res := cmp.Compare(l.key, m.key); res != 0 {
 		return res
 	}
 
-	// labelCollator reported equivalent but not bit-identical
+	// The collation reported equivalent but not bit-identical
 	// strings. To avoid violating IDNA's definition of equality,
 	// break the tie using byte order.
 	//
@@ -333,9 +388,9 @@ var domainValidator = idna.New(
 // collation, which produces the right result for English, and
 // reasonably good results for other languages.
 //
-// Nothing except Label.Compare should use this collator. In
-// particular, it MUST NOT be used by itself to establish equality of
-// labels, because we have to obey IDNA's strict definition of
+// Nothing except Label.Compare should use these collation keys. In
+// particular, they MUST NOT be used by themselves to establish
+// equality of labels, because we have to obey IDNA's strict definition of
 // equality.
 //
 // In theory, x/text/collate has the collate.Force option for exactly
@@ -343,18 +398,28 @@ var domainValidator = idna.New(
 // byte compare. However, this option is buggy and silently ignored in
 // some cases (https://github.com/golang/go/issues/68379), so we do
 // this tie breaking ourselves in Label.Compare.
-var labelCollatorMu sync.Mutex
-var labelCollator = collate.New(language.English)
-
-func compareLabel(a, b Label) int {
-	// Unfortunately individual collators are not safe for concurrent
-	// use. Wrap them in a global mutex. We could also construct a new
-	// collator for each use, but that ends up being more expensive
-	// and less performant than sharing one collator with a mutex.
-	labelCollatorMu.Lock()
-	defer labelCollatorMu.Unlock()
-	var buf collate.Buffer
-	kl := labelCollator.KeyFromString(&buf, a.label)
-	km := labelCollator.KeyFromString(&buf, b.label)
-	return bytes.Compare(kl, km)
+//
+// Individual collators are not safe for concurrent use, and are
+// somewhat expensive to construct, so we keep a pool of them. Each
+// pooled collator comes with its own scratch buffer, which is also
+// too large to allocate on every use.
+var labelCollators = sync.Pool{
+	New: func() any {
+		return &labelCollator{c: collate.New(language.English)}
+	},
+}
+
+type labelCollator struct {
+	c   *collate.Collator
+	buf collate.Buffer
+}
+
+// labelKey returns the collation key for the label string s. Labels
+// can be ordered by comparing their keys bytewise.
+func labelKey(s string) string {
+	lc := labelCollators.Get().(*labelCollator)
+	defer labelCollators.Put(lc)
+	ret := string(lc.c.KeyFromString(&lc.buf, s))
+	lc.buf.Reset()
+	return ret
 }
@@ -2,6 +2,7 @@ package domain_test
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"slices"
@@ -162,7 +163,9 @@ func forEachIDNATestVector(t *testing.T, fn func(input, want string, wantErr boo
 	for _, tc := range tests {
 		input := tc.fields[0]
 		want := tc.fields[1]
-		wantErr := tc.fields[2] != ""
+		// Parse checks the DNS length of the A-label form, which
+		// the test file only reports for ToASCII.
+		wantErr := tc.fields[2] != "" || strings.Contains(tc.fields[4], "A4_")
 
 		// the input and want strings contain Unicode escape
 		// sequences, so that the test can express precise invalid
@@ -323,3 +326,274 @@ func TestNameCompare(t *testing.T) {
 		}
 	}
 }
+
+func TestParseLabelErrors(t *testing.T) {
+	// ParseLabel applies the same checks as Parse, and reports
+	// failures the same way.
+	tests := []struct {
+		in      string
+		wantErr string
+	}{
+		{"", "domain name is empty"},
+		{"a\x86", "label 1 contains invalid UTF-8 byte 0x86 (codepoint 2)"},
+		{strings.Repeat("a", 64), "label 1 is 64 octets long in A-label form, more than the maximum of 63"},
+		{"foo_bar", `label 1 contains U+005F '_', which is not a letter, digit or hyphen (codepoint 4)`},
+		{"foo.com", `label "foo.com" cannot contain a dot`},
+	}
+	for _, tc := range tests {
+		_, err := domain.ParseLabel(tc.in)
+		var perr *domain.ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("ParseLabel(%q) err=%v, want a *ParseError", tc.in, err)
+			continue
+		}
+		if got := err.Error(); got != tc.wantErr {
+			t.Errorf("ParseLabel(%q) err=%q, want %q", tc.in, got, tc.wantErr)
+		}
+	}
+}
+
+func TestDiagnoseVectors(t *testing.T) {
+	// Diagnose must agree with Parse on every IDNA test vector, and
+	// must attribute every failure to a specific processing step.
+	forEachIDNATestVector(t, func(input, want string, wantErr bool) {
+		d := domain.Diagnose(input)
+		if gotErr := len(d.Issues) > 0; gotErr != wantErr {
+			t.Errorf("domain.Diagnose(%q) gotErr=%v, want %v", input, gotErr, wantErr)
+		}
+		for _, issue := range d.Issues {
+			if issue.Check == domain.CheckOther {
+				t.Errorf("domain.Diagnose(%q) reported unattributed issue: %v", input, issue)
+			}
+		}
+	})
+}
+
+func TestDiagnose(t *testing.T) {
+	tests := []struct {
+		in           string
+		wantChecks   []domain.Check
+		wantErr      string
+		wantMappings int
+	}{
+		{
+			in: "example.com",
+		},
+		{
+			in:           "EXAMPLE.Com",
+			wantMappings: 8,
+		},
+		{
+			in:         "foo_bar.com",
+			wantChecks: []domain.Check{domain.CheckStrictASCII},
+			wantErr:    `label 1 contains U+005F '_', which is not a letter, digit or hyphen (codepoint 4)`,
+		},
+		{
+			in:         "<<<<<<< ours.com",
+			wantChecks: []domain.Check{domain.CheckStrictASCII},
+			wantErr:    `label 1 contains U+003C '<' and U+0020 ' ', which are not letters, digits or hyphens (codepoint 1)`,
+		},
+		{
+			in:         "foo.\u00a0.com",
+			wantChecks: []domain.Check{domain.CheckStrictASCII},
+			wantErr:    `after mapping, label 2 contains U+0020 ' ', which is not a letter, digit or hyphen (codepoint 1)`,
+			// U+00A0 is mapped to a regular space
+			wantMappings: 1,
+		},
+		{
+			in:         "foo.⒈.com",
+			wantChecks: []domain.Check{domain.CheckMapping},
+			wantErr:    "label 2 contains disallowed U+2488 (codepoint 1)",
+		},
+		{
+			in:         "-foo.com",
+			wantChecks: []domain.Check{domain.CheckHyphens},
+			wantErr:    "label 1 starts with a hyphen (codepoint 1)",
+		},
+		{
+			in:         "ab--cd.com",
+			wantChecks: []domain.Check{domain.CheckHyphens},
+			wantErr:    "label 1 has hyphens in the third and fourth positions (codepoint 3)",
+		},
+		{
+			in:         "foo..com",
+			wantChecks: []domain.Check{domain.CheckLength},
+			wantErr:    "label 2 is empty",
+		},
+		{
+			in: strings.Repeat("a", 63) + ".com",
+		},
+		{
+			in:         strings.Repeat("a", 64) + ".com",
+			wantChecks: []domain.Check{domain.CheckLength},
+			wantErr:    "label 1 is 64 octets long in A-label form, more than the maximum of 63",
+		},
+		{
+			// 59 codepoints, but 65 octets as an A-label.
+			in:         strings.Repeat("ü", 59) + ".com",
+			wantChecks: []domain.Check{domain.CheckLength},
+			wantErr:    "label 1 is 65 octets long in A-label form, more than the maximum of 63",
+		},
+		{
+			in: strings.Repeat("a.", 126) + "a.",
+		},
+		{
+			in:         strings.Repeat("a.", 127) + "a",
+			wantChecks: []domain.Check{domain.CheckLength},
+			wantErr:    "domain name is 255 octets long in A-label form, more than the maximum of 253",
+		},
+		{
+			in:         "a\x86.com",
+			wantChecks: []domain.Check{domain.CheckMapping},
+			wantErr:    "label 1 contains invalid UTF-8 byte 0x86 (codepoint 2)",
+		},
+		{
+			in:         "foo.com..",
+			wantChecks: []domain.Check{domain.CheckLength},
+			wantErr:    "label 3 is empty",
+		},
+		{
+			in:         "\u0301foo.com",
+			wantChecks: []domain.Check{domain.CheckCombiningMark},
+			wantErr:    "label 1 starts with combining mark U+0301 (codepoint 1)",
+		},
+		{
+			in:         "a\u200db.com",
+			wantChecks: []domain.Check{domain.CheckContextJ},
+			wantErr:    "label 1 contains U+200D in a context where it is not allowed (codepoint 2)",
+		},
+		{
+			in:         "אa.com",
+			wantChecks: []domain.Check{domain.CheckBidi},
+			wantErr:    "label 1 violates the Bidi Rule with U+0061 (codepoint 2)",
+		},
+		{
+			in:         "xn--a-ecp.com",
+			wantChecks: []domain.Check{domain.CheckPunycode},
+		},
+	}
+
+	for _, tc := range tests {
+		d := domain.Diagnose(tc.in)
+		var gotChecks []domain.Check
+		for _, issue := range d.Issues {
+			gotChecks = append(gotChecks, issue.Check)
+		}
+		if !slices.Equal(gotChecks, tc.wantChecks) {
+			t.Errorf("Diagnose(%q) checks = %v, want %v (issues: %v)", tc.in, gotChecks, tc.wantChecks, d.Issues)
+		}
+		if got := len(d.Mappings); got != tc.wantMappings {
+			t.Errorf("Diagnose(%q) got %d mappings, want %d: %v", tc.in, got, tc.wantMappings, d.Mappings)
+		}
+
+		_, err := domain.Parse(tc.in)
+		if (err != nil) != (len(tc.wantChecks) > 0) {
+			t.Errorf("Parse(%q) err=%v, want error=%v", tc.in, err, len(tc.wantChecks) > 0)
+		}
+		if tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+			t.Errorf("Parse(%q) err=%v, want %q", tc.in, err, tc.wantErr)
+		}
+	}
+}
+
+func TestSkeleton(t *testing.T) {
+	tests := []struct {
+		a, b          string
+		wantConfusing bool
+	}{
+		{"apple.com", "apple.com", true},
+		{"apple.com", "аpple.com", true},   // Cyrillic a
+		{"paypal.com", "pаypаl.com", true}, // Cyrillic a
+		{"modern.com", "rnodern.com", true},
+		{"google.com", "goog1e.com", true},
+		{"example.com", "examp1e.org", false},
+		{"apple.com", "appel.com", false},
+	}
+
+	for _, tc := range tests {
+		a, err := domain.Parse(tc.a)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tc.a, err)
+		}
+		b, err := domain.Parse(tc.b)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tc.b, err)
+		}
+		if got := a.Skeleton() == b.Skeleton(); got != tc.wantConfusing {
+			t.Errorf("Skeleton(%q) == Skeleton(%q) is %v, want %v (%q vs. %q)", tc.a, tc.b, got, tc.wantConfusing, a.Skeleton(), b.Skeleton())
+		}
+	}
+}
+
+func TestConfusableRunes(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want []rune
+	}{
+		{"pаypal.com", "paypal.com", []rune{'а'}}, // Cyrillic a
+		{"paypal.com", "pаypаl.com", []rune{'a', 'a'}},
+		{"modern.com", "rnodern.com", []rune{'m'}},
+		{"rnodern.com", "modern.com", []rune{'r', 'n'}},
+		{"goog1e.com", "google.com", []rune{'1'}},
+	}
+
+	for _, tc := range tests {
+		a, err := domain.Parse(tc.a)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tc.a, err)
+		}
+		b, err := domain.Parse(tc.b)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tc.b, err)
+		}
+		if got := a.ConfusableRunes(b); !slices.Equal(got, tc.want) {
+			t.Errorf("ConfusableRunes(%q, %q) = %q, want %q", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestMixedScriptRunes(t *testing.T) {
+	tests := []struct {
+		in        string
+		wantMixed bool
+		wantRunes []rune
+	}{
+		{"example", false, nil},
+		{"123-foo", false, nil},
+		{"пример", false, nil},
+		{"аpple", true, []rune{'а'}},  // Cyrillic a
+		{"pаypal", true, []rune{'а'}}, // Cyrillic a
+		{"abα", true, []rune{'α'}},    // Greek alpha
+		{"ゆーざー", false, nil},
+		{"東京abc", false, nil},
+		{"서울abc", false, nil},
+	}
+
+	for _, tc := range tests {
+		l, err := domain.ParseLabel(tc.in)
+		if err != nil {
+			t.Fatalf("ParseLabel(%q) failed: %v", tc.in, err)
+		}
+		gotRunes, gotMixed := l.MixedScriptRunes()
+		if gotMixed != tc.wantMixed || !slices.Equal(gotRunes, tc.wantRunes) {
+			t.Errorf("MixedScriptRunes(%q) = %q, %v, want %q, %v", tc.in, gotRunes, gotMixed, tc.wantRunes, tc.wantMixed)
+		}
+	}
+}
+
+func BenchmarkLabelCompare(b *testing.B) {
+	la, err := domain.ParseLabel("ธุรกิจ")
+	if err != nil {
+		b.Fatal(err)
+	}
+	lb, err := domain.ParseLabel("ทหาร")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			la.Compare(lb)
+		}
+	})
+}
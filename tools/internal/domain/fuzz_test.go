@@ -0,0 +1,60 @@
+package domain_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/publicsuffix/list/tools/internal/domain"
+)
+
+func FuzzParse(f *testing.F) {
+	bs, err := os.ReadFile("../../../public_suffix_list.dat")
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, line := range strings.Split(string(bs), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "*.")
+		line = strings.TrimPrefix(line, "!")
+		f.Add(line)
+	}
+
+	f.Fuzz(func(t *testing.T, in string) {
+		d := domain.Diagnose(in)
+		got, err := domain.Parse(in)
+		if (err == nil) != (len(d.Issues) == 0) {
+			t.Fatalf("Parse(%q) error %v, but Diagnose found issues %v", in, err, d.Issues)
+		}
+		if err != nil {
+			return
+		}
+		if !got.Equal(d.Name) {
+			t.Fatalf("Parse(%q) = %q, but Diagnose parsed %q", in, got, d.Name)
+		}
+
+		// The canonical forms must parse back to the same name.
+		for _, s := range []string{got.String(), got.ASCIIString()} {
+			again, err := domain.Parse(s)
+			if err != nil {
+				t.Fatalf("Parse(%q) = %q, which doesn't parse: %v", in, s, err)
+			}
+			if !again.Equal(got) || again.String() != got.String() {
+				t.Fatalf("Parse(%q) = %q, which parses as %q", in, got, again)
+			}
+		}
+
+		labels := got.Labels()
+		if len(labels) != got.NumLabels() {
+			t.Fatalf("Parse(%q) has %d labels, but NumLabels() = %d", in, len(labels), got.NumLabels())
+		}
+		for _, l := range labels {
+			if _, err := domain.ParseLabel(l.String()); err != nil {
+				t.Fatalf("Parse(%q) has label %q, which doesn't parse: %v", in, l, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,171 @@
+//go:build ignore
+
+// This script is run by `go generate` (see confusables.go) to
+// regenerate the confusables table in confusables_table.go from the
+// Unicode confusables.txt data file.
+//
+// By default the data file is downloaded from unicode.org, for the
+// version of Unicode that x/net/idna uses. Alternatively, the path to
+// a local copy of the file can be given as an argument.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/natefinch/atomic"
+	"golang.org/x/net/idna"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	confusablesURLPattern = "https://www.unicode.org/Public/security/%s/confusables.txt"
+	confusablesTablePath  = "confusables_table.go"
+)
+
+// mapStrict matches the mapping step of domain.Parse, including its
+// STD3 restrictions. Codepoints that it changes or rejects never
+// appear in a parsed label.
+var mapStrict = idna.New(
+	idna.MapForLookup(),
+	idna.Transitional(false),
+	idna.ValidateLabels(false),
+	idna.RemoveLeadingDots(false),
+)
+
+func main() {
+	var src io.Reader
+	switch len(os.Args) {
+	case 1:
+		// New releases of Unicode add and change confusables, so use
+		// the data for the specific version of Unicode that
+		// x/net/idna uses.
+		url := fmt.Sprintf(confusablesURLPattern, idna.UnicodeVersion)
+		resp, err := http.Get(url)
+		if err != nil {
+			log.Fatal(err)
+		} else if resp.StatusCode != http.StatusOK {
+			log.Fatalf("Fetching %q: %s", url, resp.Status)
+		}
+		defer resp.Body.Close()
+		src = resp.Body
+	case 2:
+		f, err := os.Open(os.Args[1])
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		src = f
+	default:
+		log.Fatal("usage: go run update_confusables.go [path to confusables.txt]")
+	}
+
+	table, err := parseConfusables(src)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, `// Code generated by update_confusables.go. DO NOT EDIT.
+
+package domain
+
+// confusablePrototypes maps codepoints to the prototype string they
+// are visually confusable with, for the purpose of computing
+// skeletons. It holds the mappings of confusables.txt for Unicode
+// %s whose source codepoint is left unchanged by IDNA mapping and
+// NFD, with prototypes in NFD.
+var confusablePrototypes = map[rune]string{
+`, idna.UnicodeVersion)
+	keys := make([]rune, 0, len(table))
+	for r := range table {
+		keys = append(keys, r)
+	}
+	slices.Sort(keys)
+	for _, r := range keys {
+		fmt.Fprintf(&out, "\t%+q: %+q,\n", r, table[r])
+	}
+	out.WriteString("}\n")
+
+	bs, err := format.Source(out.Bytes())
+	if err != nil {
+		log.Fatalf("Formatting generated code: %v", err)
+	}
+	if err := atomic.WriteFile(confusablesTablePath, bytes.NewReader(bs)); err != nil {
+		log.Fatalf("Writing %q: %v", confusablesTablePath, err)
+	}
+}
+
+// parseConfusables returns the mappings of the confusables.txt file
+// in r that are relevant to domain labels.
+//
+// Each mapping line has the form:
+//
+//	<source> ; <target codepoints> ; MA # comment
+func parseConfusables(r io.Reader) (map[rune]string, error) {
+	ret := map[rune]string{}
+	sc := bufio.NewScanner(r)
+	line := 0
+	for sc.Scan() {
+		line++
+		ln, _, _ := strings.Cut(sc.Text(), "#")
+		ln = strings.TrimPrefix(ln, "\ufeff")
+		if strings.TrimSpace(ln) == "" {
+			continue
+		}
+		fs := strings.Split(ln, ";")
+		if len(fs) != 3 {
+			return nil, fmt.Errorf("line %d: unrecognized format: %q", line, sc.Text())
+		}
+		src, err := parseCodepoints(fs[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		target, err := parseCodepoints(fs[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if len([]rune(src)) != 1 {
+			return nil, fmt.Errorf("line %d: source %q is not a single codepoint", line, src)
+		}
+
+		// Skeletons are computed on the NFD form of parsed labels,
+		// so only codepoints that survive IDNA mapping and NFD can
+		// ever be looked up.
+		if out, err := mapStrict.ToUnicode(src); err != nil || out != src || !norm.NFD.IsNormalString(src) {
+			continue
+		}
+		target = norm.NFD.String(target)
+		if target == src {
+			continue
+		}
+		ret[[]rune(src)[0]] = target
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// parseCodepoints parses a space-separated list of hexadecimal
+// codepoints.
+func parseCodepoints(s string) (string, error) {
+	var ret strings.Builder
+	for _, f := range strings.Fields(s) {
+		cp, err := strconv.ParseUint(f, 16, 32)
+		if err != nil {
+			return "", fmt.Errorf("invalid codepoint %q: %w", f, err)
+		}
+		ret.WriteRune(rune(cp))
+	}
+	return ret.String(), nil
+}
@@ -153,3 +153,18 @@ func gitStdout(repoPath string, args ...string) ([]byte, error) {
 	}
 	return bytes.TrimSpace(bs), nil
 }
+
+// MergeBase returns the hash of the best common ancestor of the
+// commits a and b in the git repository at gitPath.
+func MergeBase(gitPath, a, b string) (string, error) {
+	toplevel, err := gitToplevel(gitPath)
+	if err != nil {
+		return "", err
+	}
+
+	bs, err := gitStdout(toplevel, "merge-base", a, b)
+	if err != nil {
+		return "", err
+	}
+	return string(bs), nil
+}
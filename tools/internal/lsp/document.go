@@ -0,0 +1,288 @@
+package lsp
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/publicsuffix/list/tools/internal/parser"
+)
+
+// document is a parsed snapshot of an open PSL file.
+type document struct {
+	uri   string
+	text  string
+	lines []string
+
+	// list is the lossless parse of text, and errs the parse errors.
+	list *parser.List
+	errs []error
+}
+
+func newDocument(uri, text string) *document {
+	l, errs := parser.ParseLossless([]byte(text))
+	return &document{
+		uri:   uri,
+		text:  text,
+		lines: strings.Split(text, "\n"),
+		list:  l,
+		errs:  errs,
+	}
+}
+
+// diagnostics returns the parse, formatting and validation problems in
+// d.
+func (d *document) diagnostics() []diagnostic {
+	ret := []diagnostic{}
+	add := func(severity int, err error) {
+		if _, ok := err.(parser.SuppressedError); ok {
+			// Still show the problem, but without flagging it.
+			severity = severityInformation
+		}
+		diag := diagnostic{
+			Range:    d.errorRange(err),
+			Severity: severity,
+			Source:   "psltool",
+			Message:  err.Error(),
+		}
+		if code, ok := parser.ErrorCode(err); ok {
+			diag.Code = code.ID
+		}
+		ret = append(ret, diag)
+	}
+
+	for _, err := range d.errs {
+		add(severityError, err)
+	}
+
+	// Validation and cleaning both need their own copy of the list,
+	// because Clean edits the list in place.
+	validated, _ := parser.ParseLossless([]byte(d.text))
+	for _, err := range parser.ValidateOffline(validated) {
+		add(severityError, err)
+	}
+	cleaned, _ := parser.Parse([]byte(d.text))
+	for _, err := range cleaned.Clean() {
+		add(severityWarning, err)
+	}
+
+	if len(d.errs) == 0 && string(cleaned.MarshalPSL()) != d.text {
+		ret = append(ret, diagnostic{
+			Range:    d.lineRange(0, 1),
+			Severity: severityInformation,
+			Source:   "psltool",
+			Message:  "file is not formatted, run psltool fmt",
+		})
+	}
+
+	return ret
+}
+
+// format returns the edits that reformat d.
+func (d *document) format() ([]textEdit, error) {
+	if len(d.errs) > 0 {
+		return nil, fmt.Errorf("cannot format file with %d parse errors", len(d.errs))
+	}
+	l, _ := parser.Parse([]byte(d.text))
+	l.Clean()
+	formatted := string(l.MarshalPSL())
+	if formatted == d.text {
+		return []textEdit{}, nil
+	}
+	return []textEdit{{Range: d.lineRange(0, len(d.lines)), NewText: formatted}}, nil
+}
+
+// symbols returns the outline of d: sections, the suffix blocks within
+// them, and the suffixes within those.
+func (d *document) symbols() []documentSymbol {
+	ret := []documentSymbol{}
+	for _, b := range d.list.Blocks {
+		switch v := b.(type) {
+		case *parser.Section:
+			sym := documentSymbol{
+				Name:           v.Name,
+				Kind:           symbolNamespace,
+				Range:          d.srcRange(v.SourceRange),
+				SelectionRange: d.lineRange(v.FirstLine, v.FirstLine+1),
+			}
+			for _, s := range parser.BlocksOfType[*parser.Suffixes](v) {
+				sym.Children = append(sym.Children, d.suffixesSymbol(s))
+			}
+			ret = append(ret, sym)
+		case *parser.Suffixes:
+			ret = append(ret, d.suffixesSymbol(v))
+		}
+	}
+	return ret
+}
+
+func (d *document) suffixesSymbol(s *parser.Suffixes) documentSymbol {
+	ret := documentSymbol{
+		Name:           s.Info.Name,
+		Kind:           symbolClass,
+		Range:          d.srcRange(s.SourceRange),
+		SelectionRange: d.lineRange(s.FirstLine, s.FirstLine+1),
+	}
+	for _, child := range s.Blocks {
+		var name string
+		switch v := child.(type) {
+		case *parser.Suffix:
+			name = v.Domain.String()
+		case *parser.Wildcard:
+			name = "*." + v.Domain.String()
+		default:
+			continue
+		}
+		r := d.srcRange(child.SrcRange())
+		ret.Children = append(ret.Children, documentSymbol{
+			Name:           name,
+			Kind:           symbolConstant,
+			Range:          r,
+			SelectionRange: r,
+		})
+	}
+	if ret.Name == "" {
+		if len(ret.Children) > 0 {
+			ret.Name = ret.Children[0].Name
+		} else {
+			ret.Name = "(unnamed suffix block)"
+		}
+	}
+	if n := len(ret.Children); n == 1 {
+		ret.Detail = "1 suffix"
+	} else {
+		ret.Detail = fmt.Sprintf("%d suffixes", n)
+	}
+	return ret
+}
+
+// definition returns the location of the header of the suffix block
+// containing the suffix at pos, or nil if pos is not on a suffix.
+func (d *document) definition(pos position) []location {
+	block := d.suffixesAt(pos.Line)
+	if block == nil || d.inComment(block, pos.Line) {
+		return nil
+	}
+	target := block.SourceRange
+	if len(block.Blocks) > 0 {
+		if hdr, ok := block.Blocks[0].(*parser.Comment); ok {
+			target = hdr.SourceRange
+		}
+	}
+	return []location{{URI: d.uri, Range: d.srcRange(target)}}
+}
+
+// hover returns the maintainer information of the suffix block at
+// pos, or nil if pos is not in a suffix block.
+func (d *document) hover(pos position) *hover {
+	block := d.suffixesAt(pos.Line)
+	if block == nil {
+		return nil
+	}
+	inf := block.Info
+
+	var b strings.Builder
+	name := inf.Name
+	if name == "" {
+		name = "(no entity name)"
+	}
+	fmt.Fprintf(&b, "**%s**\n", name)
+	for _, u := range inf.URLs {
+		fmt.Fprintf(&b, "\n- URL: %s", u)
+	}
+	for _, m := range inf.Maintainers {
+		fmt.Fprintf(&b, "\n- Contact: %s", strings.TrimSpace(fmt.Sprintf("%s <%s>", m.Name, m.Address)))
+	}
+	if !inf.Requested.IsZero() {
+		fmt.Fprintf(&b, "\n- Requested: %s", inf.Requested.Format(time.DateOnly))
+	}
+	if inf.PR != 0 {
+		fmt.Fprintf(&b, "\n- PR: [#%d](https://github.com/publicsuffix/list/pull/%d)", inf.PR, inf.PR)
+	}
+	for _, n := range inf.Notes {
+		fmt.Fprintf(&b, "\n- Note: %s", n)
+	}
+	for _, o := range inf.Other {
+		fmt.Fprintf(&b, "\n- %s", o)
+	}
+	if !inf.MachineEditable {
+		b.WriteString("\n\nThe header of this block is not in the structured format.")
+	}
+
+	return &hover{
+		Contents: markupContent{Kind: "markdown", Value: b.String()},
+		Range:    d.srcRange(block.SourceRange),
+	}
+}
+
+// suffixesAt returns the suffix block that contains line, or nil.
+func (d *document) suffixesAt(line int) *parser.Suffixes {
+	for _, s := range parser.BlocksOfType[*parser.Suffixes](d.list) {
+		if s.FirstLine <= line && line < s.LastLine {
+			return s
+		}
+	}
+	return nil
+}
+
+// inComment reports whether line is part of a comment in block.
+func (d *document) inComment(block *parser.Suffixes, line int) bool {
+	for _, c := range parser.BlocksOfType[*parser.Comment](block) {
+		if c.FirstLine <= line && line < c.LastLine {
+			return true
+		}
+	}
+	return false
+}
+
+// errorRange returns the range of the source that err is about. Errors
+// with no location are reported on the first line.
+func (d *document) errorRange(err error) lspRange {
+	src, ok := parser.ErrorSource(err)
+	if !ok {
+		return d.lineRange(0, 1)
+	}
+	return d.srcRange(src)
+}
+
+// srcRange converts src to an LSP range.
+func (d *document) srcRange(src parser.SourceRange) lspRange {
+	if src.LastColumn == 0 {
+		return d.lineRange(src.FirstLine, src.LastLine)
+	}
+	return lspRange{
+		Start: position{src.FirstLine, d.utf16Offset(src.FirstLine, src.FirstColumn)},
+		End:   position{src.LastLine - 1, d.utf16Offset(src.LastLine-1, src.LastColumn)},
+	}
+}
+
+// lineRange returns the range covering lines [first:last) in their
+// entirety.
+func (d *document) lineRange(first, last int) lspRange {
+	first = min(max(first, 0), len(d.lines)-1)
+	last = min(max(last, first+1), len(d.lines))
+	end := d.lines[last-1]
+	return lspRange{
+		Start: position{first, 0},
+		End:   position{last - 1, d.utf16Offset(last-1, len(end))},
+	}
+}
+
+// utf16Offset converts a byte offset within a line into the UTF-16
+// code unit offset that LSP uses.
+func (d *document) utf16Offset(line, byteOffset int) int {
+	if line < 0 || line >= len(d.lines) {
+		return 0
+	}
+	s := d.lines[line]
+	s = s[:min(byteOffset, len(s))]
+	ret := 0
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		ret += utf16.RuneLen(r)
+		s = s[size:]
+	}
+	return ret
+}
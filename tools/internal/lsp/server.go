@@ -0,0 +1,284 @@
+// Package lsp implements a Language Server Protocol server for PSL
+// files.
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Serve runs an LSP server that reads requests from r and writes
+// responses to w, until the client asks the server to exit, r reaches
+// EOF, or ctx is canceled.
+func Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	s := &server{
+		in:   bufio.NewReader(r),
+		out:  w,
+		docs: map[string]string{},
+	}
+
+	// Reading blocks, so handle cancellation by giving up on the
+	// pending read.
+	done := make(chan error, 1)
+	go func() { done <- s.run() }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// server is the state of one LSP session.
+type server struct {
+	in *bufio.Reader
+
+	outMu sync.Mutex
+	out   io.Writer
+
+	// docs is the current text of open documents, by URI.
+	docs map[string]string
+	// shutdown is whether the client has requested a shutdown.
+	shutdown bool
+}
+
+// run processes messages until the session ends.
+func (s *server) run() error {
+	for {
+		msg, err := s.read()
+		var rerr *responseError
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil
+		case errors.As(err, &rerr):
+			// The message was framed correctly but is not valid
+			// JSON, we can keep going after reporting the problem.
+			if err := s.respond(json.RawMessage("null"), nil, rerr); err != nil {
+				return err
+			}
+			continue
+		case err != nil:
+			return err
+		}
+
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return errors.New("client exited without shutting down")
+			}
+			return nil
+		}
+
+		result, err := s.handle(msg)
+		if msg.isNotification() {
+			continue
+		}
+		if err := s.respond(msg.ID, result, err); err != nil {
+			return err
+		}
+	}
+}
+
+// read reads one message from the client.
+func (s *server) read() (*message, error) {
+	hdr, err := textproto.NewReader(s.in).ReadMIMEHeader()
+	if err != nil {
+		if errors.Is(err, io.EOF) && len(hdr) == 0 {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("reading message header: %w", err)
+	}
+	n, err := strconv.Atoi(hdr.Get("Content-Length"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Content-Length: %w", err)
+	}
+	body := make([]byte, n)
+	if _, err := io.ReadFull(s.in, body); err != nil {
+		return nil, fmt.Errorf("reading message body: %w", err)
+	}
+
+	var ret message
+	if err := json.Unmarshal(body, &ret); err != nil {
+		return nil, &responseError{codeParseError, err.Error()}
+	}
+	return &ret, nil
+}
+
+// write sends msg to the client.
+func (s *server) write(msg *message) error {
+	msg.JSONRPC = "2.0"
+	bs, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	s.outMu.Lock()
+	defer s.outMu.Unlock()
+	if _, err := fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n", len(bs)); err != nil {
+		return err
+	}
+	_, err = s.out.Write(bs)
+	return err
+}
+
+// respond sends the response to the request with the given id.
+func (s *server) respond(id json.RawMessage, result any, err error) error {
+	resp := &message{ID: id}
+	if err != nil {
+		var rerr *responseError
+		if !errors.As(err, &rerr) {
+			rerr = &responseError{codeRequestFailed, err.Error()}
+		}
+		resp.Error = rerr
+		return s.write(resp)
+	}
+
+	bs, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	resp.Result = bs
+	return s.write(resp)
+}
+
+// notify sends a notification to the client.
+func (s *server) notify(method string, params any) error {
+	bs, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return s.write(&message{Method: method, Params: bs})
+}
+
+// handle dispatches msg to the appropriate handler, and returns the
+// result to send back to the client, if msg is a request.
+func (s *server) handle(msg *message) (any, error) {
+	switch msg.Method {
+	case "initialize":
+		return initializeResult{
+			Capabilities: serverCapabilities{
+				TextDocumentSync:           syncFull,
+				DocumentFormattingProvider: true,
+				DocumentSymbolProvider:     true,
+				DefinitionProvider:         true,
+				HoverProvider:              true,
+			},
+			ServerInfo: serverInfo{Name: "psltool"},
+		}, nil
+	case "initialized":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+
+	case "textDocument/didOpen":
+		var p didOpenParams
+		if err := unmarshalParams(msg, &p); err != nil {
+			return nil, err
+		}
+		return nil, s.update(p.TextDocument.URI, p.TextDocument.Text)
+	case "textDocument/didChange":
+		var p didChangeParams
+		if err := unmarshalParams(msg, &p); err != nil {
+			return nil, err
+		}
+		if len(p.ContentChanges) == 0 {
+			return nil, nil
+		}
+		// We only support full document sync, so the last change has
+		// the entire new text.
+		return nil, s.update(p.TextDocument.URI, p.ContentChanges[len(p.ContentChanges)-1].Text)
+	case "textDocument/didClose":
+		var p didCloseParams
+		if err := unmarshalParams(msg, &p); err != nil {
+			return nil, err
+		}
+		delete(s.docs, p.TextDocument.URI)
+		// Clear out diagnostics for the closed file.
+		return nil, s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+			URI:         p.TextDocument.URI,
+			Diagnostics: []diagnostic{},
+		})
+
+	case "textDocument/formatting":
+		var p textDocumentParams
+		if err := unmarshalParams(msg, &p); err != nil {
+			return nil, err
+		}
+		doc, err := s.document(p.TextDocument.URI)
+		if err != nil {
+			return nil, err
+		}
+		return doc.format()
+	case "textDocument/documentSymbol":
+		var p textDocumentParams
+		if err := unmarshalParams(msg, &p); err != nil {
+			return nil, err
+		}
+		doc, err := s.document(p.TextDocument.URI)
+		if err != nil {
+			return nil, err
+		}
+		return doc.symbols(), nil
+	case "textDocument/definition":
+		var p textDocumentPositionParams
+		if err := unmarshalParams(msg, &p); err != nil {
+			return nil, err
+		}
+		doc, err := s.document(p.TextDocument.URI)
+		if err != nil {
+			return nil, err
+		}
+		return doc.definition(p.Position), nil
+	case "textDocument/hover":
+		var p textDocumentPositionParams
+		if err := unmarshalParams(msg, &p); err != nil {
+			return nil, err
+		}
+		doc, err := s.document(p.TextDocument.URI)
+		if err != nil {
+			return nil, err
+		}
+		return doc.hover(p.Position), nil
+
+	default:
+		if strings.HasPrefix(msg.Method, "$/") || msg.isNotification() {
+			// Optional notifications can be ignored.
+			return nil, nil
+		}
+		return nil, &responseError{codeMethodNotFound, fmt.Sprintf("method %q not supported", msg.Method)}
+	}
+}
+
+// unmarshalParams decodes the parameters of msg into out.
+func unmarshalParams(msg *message, out any) error {
+	if err := json.Unmarshal(msg.Params, out); err != nil {
+		return &responseError{codeInvalidParams, fmt.Sprintf("invalid params for %s: %v", msg.Method, err)}
+	}
+	return nil
+}
+
+// update records new text for the document at uri, and publishes
+// fresh diagnostics for it.
+func (s *server) update(uri, text string) error {
+	s.docs[uri] = text
+	return s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: newDocument(uri, text).diagnostics(),
+	})
+}
+
+// document returns the parsed current version of the document at uri.
+func (s *server) document(uri string) (*document, error) {
+	text, ok := s.docs[uri]
+	if !ok {
+		return nil, &responseError{codeRequestFailed, fmt.Sprintf("document %q is not open", uri)}
+	}
+	return newDocument(uri, text), nil
+}
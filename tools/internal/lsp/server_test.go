@@ -0,0 +1,325 @@
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// testClient is a minimal LSP client for driving a server in tests.
+type testClient struct {
+	t      *testing.T
+	w      io.Writer
+	r      *bufio.Reader
+	nextID int
+
+	// notifications are the notifications received from the server
+	// so far.
+	notifications []*message
+}
+
+func newTestClient(t *testing.T) *testClient {
+	t.Helper()
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- Serve(ctx, serverR, serverW) }()
+	t.Cleanup(func() {
+		clientW.Close()
+		cancel()
+		<-done
+	})
+
+	return &testClient{
+		t: t,
+		w: clientW,
+		r: bufio.NewReader(clientR),
+	}
+}
+
+func (c *testClient) send(msg *message) {
+	c.t.Helper()
+	msg.JSONRPC = "2.0"
+	bs, err := json.Marshal(msg)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n%s", len(bs), bs); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func (c *testClient) recv() *message {
+	c.t.Helper()
+	hdr, err := textproto.NewReader(c.r).ReadMIMEHeader()
+	if err != nil {
+		c.t.Fatalf("reading header: %v", err)
+	}
+	n, err := strconv.Atoi(hdr.Get("Content-Length"))
+	if err != nil {
+		c.t.Fatalf("bad Content-Length: %v", err)
+	}
+	body := make([]byte, n)
+	if _, err := io.ReadFull(c.r, body); err != nil {
+		c.t.Fatalf("reading body: %v", err)
+	}
+	var ret message
+	if err := json.Unmarshal(body, &ret); err != nil {
+		c.t.Fatalf("decoding message: %v", err)
+	}
+	return &ret
+}
+
+// call sends a request and decodes the result into out. It returns
+// the error sent by the server, if any.
+func (c *testClient) call(method string, params, out any) *responseError {
+	c.t.Helper()
+	c.nextID++
+	id := json.RawMessage(strconv.Itoa(c.nextID))
+	bs, err := json.Marshal(params)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	c.send(&message{ID: id, Method: method, Params: bs})
+	for {
+		msg := c.recv()
+		if msg.isNotification() {
+			c.notifications = append(c.notifications, msg)
+			continue
+		}
+		if string(msg.ID) != string(id) {
+			c.t.Fatalf("got response for request %s, want %s", msg.ID, id)
+		}
+		if msg.Error != nil {
+			return msg.Error
+		}
+		if out != nil {
+			if err := json.Unmarshal(msg.Result, out); err != nil {
+				c.t.Fatalf("decoding %s result: %v", method, err)
+			}
+		}
+		return nil
+	}
+}
+
+// notify sends a notification and waits for the diagnostics it
+// produces.
+func (c *testClient) notifyAndWait(method string, params any) publishDiagnosticsParams {
+	c.t.Helper()
+	bs, err := json.Marshal(params)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	c.send(&message{Method: method, Params: bs})
+	msg := c.recv()
+	if msg.Method != "textDocument/publishDiagnostics" {
+		c.t.Fatalf("got %q message, want diagnostics", msg.Method)
+	}
+	var ret publishDiagnosticsParams
+	if err := json.Unmarshal(msg.Params, &ret); err != nil {
+		c.t.Fatal(err)
+	}
+	return ret
+}
+
+func lines(ls ...string) string {
+	return strings.Join(ls, "\n")
+}
+
+const testURI = "file:///psl/public_suffix_list.dat"
+
+func TestServer(t *testing.T) {
+	c := newTestClient(t)
+
+	var init initializeResult
+	if err := c.call("initialize", map[string]any{}, &init); err != nil {
+		t.Fatalf("initialize failed: %v", err)
+	}
+	if !init.Capabilities.HoverProvider || init.Capabilities.TextDocumentSync != syncFull {
+		t.Errorf("unexpected capabilities: %+v", init.Capabilities)
+	}
+
+	broken := lines(
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// Bravo : https://bravo.net",
+		"// Submitted by Bravo <psl@bravo.net>",
+		"bravo.net",
+		"  app..bravo.net",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+		"",
+		"// ===BEGIN ICANN DOMAINS===",
+		"",
+		"// com",
+		"com",
+		"",
+		"// ===END ICANN DOMAINS===",
+		"",
+	)
+	diags := c.notifyAndWait("textDocument/didOpen", didOpenParams{
+		TextDocument: textDocumentItem{URI: testURI, Version: 1, Text: broken},
+	})
+	var errDiags []diagnostic
+	for _, d := range diags.Diagnostics {
+		if d.Severity == severityError {
+			errDiags = append(errDiags, d)
+		}
+	}
+	wantDiag := []diagnostic{
+		{
+			Range:    lspRange{position{5, 2}, position{5, 16}},
+			Severity: severityError,
+			Code:     "PSL0018",
+			Source:   "psltool",
+			Message:  `line 6:3-16: invalid suffix "app..bravo.net": label 2 is empty`,
+		},
+	}
+	if diff := cmp.Diff(errDiags, wantDiag); diff != "" {
+		t.Errorf("wrong diagnostics (-got+want):\n%s", diff)
+	}
+
+	text := lines(
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// Delta : https://delta.net",
+		"// Submitted by Delta <psl@delta.net>",
+		"// PR: #1234",
+		"delta.net",
+		"",
+		"// Bravo : https://bravo.net",
+		"// Submitted by Bravo <psl@bravo.net>",
+		"bravo.net",
+		"*.users.bravo.net",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+		"",
+		"// ===BEGIN ICANN DOMAINS===",
+		"",
+		"// com",
+		"com",
+		"",
+		"// ===END ICANN DOMAINS===",
+		"",
+	)
+	diags = c.notifyAndWait("textDocument/didChange", didChangeParams{
+		TextDocument:   textDocumentIdentifier{URI: testURI},
+		ContentChanges: []contentChange{{Text: text}},
+	})
+	for _, d := range diags.Diagnostics {
+		if d.Severity == severityError {
+			t.Errorf("unexpected error diagnostic after fix: %+v", d)
+		}
+	}
+
+	doc := textDocumentIdentifier{URI: testURI}
+
+	var edits []textEdit
+	if err := c.call("textDocument/formatting", textDocumentParams{doc}, &edits); err != nil {
+		t.Fatalf("formatting failed: %v", err)
+	}
+	wantFormatted := lines(
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// Bravo : https://bravo.net",
+		"// Submitted by Bravo <psl@bravo.net>",
+		"bravo.net",
+		"*.users.bravo.net",
+		"",
+		"// Delta : https://delta.net",
+		"// Submitted by Delta <psl@delta.net>",
+		"// PR: #1234",
+		"delta.net",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+		"",
+		"// ===BEGIN ICANN DOMAINS===",
+		"",
+		"// com",
+		"com",
+		"",
+		"// ===END ICANN DOMAINS===",
+		"",
+	)
+	wantEdits := []textEdit{{Range: lspRange{position{0, 0}, position{20, 0}}, NewText: wantFormatted}}
+	if diff := cmp.Diff(edits, wantEdits); diff != "" {
+		t.Errorf("wrong formatting edits (-got+want):\n%s", diff)
+	}
+
+	var syms []documentSymbol
+	if err := c.call("textDocument/documentSymbol", textDocumentParams{doc}, &syms); err != nil {
+		t.Fatalf("documentSymbol failed: %v", err)
+	}
+	var names []string
+	var walk func([]documentSymbol, string)
+	walk = func(ss []documentSymbol, indent string) {
+		for _, s := range ss {
+			names = append(names, indent+s.Name)
+			walk(s.Children, indent+"  ")
+		}
+	}
+	walk(syms, "")
+	wantNames := []string{
+		"PRIVATE DOMAINS",
+		"  Delta",
+		"    delta.net",
+		"  Bravo",
+		"    bravo.net",
+		"    *.users.bravo.net",
+		"ICANN DOMAINS",
+		"  com",
+		"    com",
+	}
+	if diff := cmp.Diff(names, wantNames); diff != "" {
+		t.Errorf("wrong document symbols (-got+want):\n%s", diff)
+	}
+
+	var locs []location
+	if err := c.call("textDocument/definition", textDocumentPositionParams{doc, position{10, 3}}, &locs); err != nil {
+		t.Fatalf("definition failed: %v", err)
+	}
+	wantLocs := []location{{URI: testURI, Range: lspRange{position{7, 0}, position{8, 37}}}}
+	if diff := cmp.Diff(locs, wantLocs); diff != "" {
+		t.Errorf("wrong definition (-got+want):\n%s", diff)
+	}
+	locs = nil
+	if err := c.call("textDocument/definition", textDocumentPositionParams{doc, position{1, 0}}, &locs); err != nil {
+		t.Fatalf("definition failed: %v", err)
+	}
+	if len(locs) != 0 {
+		t.Errorf("definition outside a suffix block returned %v, want nothing", locs)
+	}
+
+	var h hover
+	if err := c.call("textDocument/hover", textDocumentPositionParams{doc, position{5, 0}}, &h); err != nil {
+		t.Fatalf("hover failed: %v", err)
+	}
+	wantHover := lines(
+		"**Delta**",
+		"",
+		"- URL: https://delta.net",
+		"- Contact: Delta <psl@delta.net>",
+		"- PR: [#1234](https://github.com/publicsuffix/list/pull/1234)",
+	)
+	if diff := cmp.Diff(h.Contents.Value, wantHover); diff != "" {
+		t.Errorf("wrong hover (-got+want):\n%s", diff)
+	}
+
+	if err := c.call("textDocument/rename", textDocumentPositionParams{doc, position{5, 0}}, nil); err == nil || err.Code != codeMethodNotFound {
+		t.Errorf("unsupported method returned %v, want method not found error", err)
+	}
+
+	if err := c.call("shutdown", nil, nil); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+}
@@ -0,0 +1,379 @@
+package parser
+
+import (
+	"strings"
+)
+
+// Codes of the errors reported by this package.
+//
+// Codes are part of psltool's interface: suppressions, dashboards and
+// documentation refer to them. Once assigned, a code's number and
+// name never change and are never reused, even if its error type is
+// removed.
+var (
+	CodeInvalidEncoding               = Code{"PSL0001", "invalid-encoding"}
+	CodeInvalidUnicode                = Code{"PSL0002", "invalid-unicode"}
+	CodeSectionInSuffixBlock          = Code{"PSL0003", "section-in-suffix-block"}
+	CodeUnclosedSection               = Code{"PSL0004", "unclosed-section"}
+	CodeNestedSection                 = Code{"PSL0005", "nested-section"}
+	CodeUnstartedSection              = Code{"PSL0006", "unstarted-section"}
+	CodeMismatchedSection             = Code{"PSL0007", "mismatched-section"}
+	CodeUnknownSectionMarker          = Code{"PSL0008", "unknown-section-marker"}
+	CodeMissingEntityName             = Code{"PSL0009", "missing-entity-name"}
+	CodeMissingEntityEmail            = Code{"PSL0010", "missing-email"}
+	CodeInsecureEntityURL             = Code{"PSL0011", "insecure-url"}
+	CodeEntityURLHasCredentials       = Code{"PSL0012", "url-has-credentials"}
+	CodeInvalidEntityURL              = Code{"PSL0013", "invalid-url"}
+	CodeUnrelatedEntityURL            = Code{"PSL0014", "unrelated-url"}
+	CodeInvalidEntityEmail            = Code{"PSL0015", "invalid-email"}
+	CodePlaceholderEntityContact      = Code{"PSL0016", "placeholder-contact"}
+	CodeUnstructuredHeader            = Code{"PSL0017", "unstructured-header"}
+	CodeInvalidSuffix                 = Code{"PSL0018", "invalid-suffix"}
+	CodeCommentPreventsSuffixSort     = Code{"PSL0019", "comment-prevents-suffix-sort"}
+	CodeCommentPreventsSectionSort    = Code{"PSL0020", "comment-prevents-section-sort"}
+	CodeDuplicateSection              = Code{"PSL0021", "duplicate-section"}
+	CodeUnknownSection                = Code{"PSL0022", "unknown-section"}
+	CodeMissingSection                = Code{"PSL0023", "missing-section"}
+	CodeDuplicateSuffix               = Code{"PSL0024", "duplicate-suffix"}
+	CodeConflictingSuffixAndException = Code{"PSL0025", "conflicting-exception"}
+	CodeConfusableSuffix              = Code{"PSL0026", "confusable-suffix"}
+	CodeMixedScriptSuffix             = Code{"PSL0027", "mixed-script-suffix"}
+	CodeMissingTXTRecord              = Code{"PSL0028", "missing-txt-record"}
+	CodeTXTRecordMismatch             = Code{"PSL0029", "txt-record-mismatch"}
+	CodeTXTCheckFailure               = Code{"PSL0030", "txt-check-failure"}
+	CodeInvalidSuppression            = Code{"PSL0031", "invalid-suppression"}
+	CodeInvalidGroupMarker            = Code{"PSL0032", "invalid-group-marker"}
+	CodeNestedGroup                   = Code{"PSL0033", "nested-group"}
+	CodeUnclosedGroup                 = Code{"PSL0034", "unclosed-group"}
+	CodeUnstartedGroup                = Code{"PSL0035", "unstarted-group"}
+	CodeMismatchedGroup               = Code{"PSL0036", "mismatched-group"}
+	CodeMisplacedGroupMarker          = Code{"PSL0037", "misplaced-group-marker"}
+	CodeEmptyGroup                    = Code{"PSL0038", "empty-group"}
+	CodeDuplicateGroup                = Code{"PSL0039", "duplicate-group"}
+	CodeNewSuppression                = Code{"PSL0040", "new-suppression"}
+)
+
+// A CatalogEntry documents one error code.
+type CatalogEntry struct {
+	Code
+	// Summary is a one-line description of the problem.
+	Summary string
+	// Explanation describes the problem in more detail.
+	Explanation string
+	// Rationale is the reason the PSL's policies reject the problem.
+	Rationale string
+	// Fix describes how to fix the problem.
+	Fix string
+	// Suppressible is whether a suffix block can opt out of the check
+	// with a psl:allow annotation.
+	Suppressible bool
+}
+
+// Catalog returns the documentation of all error codes, in code
+// order.
+func Catalog() []CatalogEntry {
+	return catalog
+}
+
+// LookupCode returns the catalog entry for a code, given either as
+// its ID ("PSL0010") or its name ("missing-email"). The lookup is
+// case-insensitive.
+func LookupCode(s string) (CatalogEntry, bool) {
+	for _, e := range catalog {
+		if strings.EqualFold(s, e.ID) || strings.EqualFold(s, e.Name) {
+			return e, true
+		}
+	}
+	return CatalogEntry{}, false
+}
+
+var catalog = []CatalogEntry{
+	{
+		Code:        CodeInvalidEncoding,
+		Summary:     "The file is not encoded in UTF-8.",
+		Explanation: "The file was decoded from another character encoding, such as UTF-16, or starts with a byte order mark.",
+		Rationale:   "The PSL is consumed by many independent parsers, most of which only understand plain UTF-8.",
+		Fix:         "Convert the file to UTF-8 without a byte order mark. 'psltool validate --fix' does this automatically.",
+	},
+	{
+		Code:        CodeInvalidUnicode,
+		Summary:     "A line contains invalid Unicode characters.",
+		Explanation: "A line contains bytes that are not valid UTF-8, or Unicode characters that are not allowed in the file.",
+		Rationale:   "Invalid characters are interpreted differently by different consumers, and can hide the real content of a line.",
+		Fix:         "Remove or replace the offending characters. Check that the editor used to change the file saves UTF-8.",
+	},
+	{
+		Code:        CodeSectionInSuffixBlock,
+		Summary:     "A section marker appears inside a suffix block.",
+		Explanation: "A '// ===BEGIN ...===' or '// ===END ...===' line is part of the comments of a suffix block, rather than separated from it by a blank line.",
+		Rationale:   "Section markers delimit the ICANN and private parts of the list, and consumers find them by scanning lines. A marker inside a suffix block makes the block's section ambiguous.",
+		Fix:         "Separate the section marker from the surrounding suffix blocks with blank lines.",
+	},
+	{
+		Code:        CodeUnclosedSection,
+		Summary:     "A section is missing its END marker.",
+		Explanation: "A section was started with '// ===BEGIN NAME===', but the file ends before the matching '// ===END NAME===' line.",
+		Rationale:   "Consumers use the section markers to tell ICANN suffixes from private suffixes. An unclosed section makes everything after it ambiguous.",
+		Fix:         "Add the missing '// ===END NAME===' line at the end of the section.",
+	},
+	{
+		Code:        CodeNestedSection,
+		Summary:     "A section starts inside another section.",
+		Explanation: "A '// ===BEGIN ...===' line appears before the END marker of the previous section.",
+		Rationale:   "Sections cannot be nested, each suffix belongs to exactly one section.",
+		Fix:         "Close the previous section before starting the next one, or remove the stray BEGIN marker.",
+	},
+	{
+		Code:        CodeUnstartedSection,
+		Summary:     "A section END marker has no matching BEGIN marker.",
+		Explanation: "A '// ===END NAME===' line appears outside of any section.",
+		Rationale:   "Unbalanced section markers make it unclear which section suffixes belong to.",
+		Fix:         "Remove the stray END marker, or add the missing BEGIN marker.",
+	},
+	{
+		Code:        CodeMismatchedSection,
+		Summary:     "A section is closed with a different name than it was opened with.",
+		Explanation: "The '// ===END NAME===' line that closes a section doesn't have the same name as its '// ===BEGIN NAME===' line.",
+		Rationale:   "Consumers match BEGIN and END markers by name.",
+		Fix:         "Correct the name in the END marker.",
+	},
+	{
+		Code:        CodeUnknownSectionMarker,
+		Summary:     "A line looks like a section marker, but isn't one.",
+		Explanation: "A comment starts with '// ===', but is not of the form '// ===BEGIN NAME===' or '// ===END NAME==='. This is usually a typo in a section marker.",
+		Rationale:   "Consumers recognize section markers by their exact text. A misspelled marker is silently ignored by some of them.",
+		Fix:         "Correct the spelling of the marker. 'psltool validate --fix' corrects common misspellings.",
+	},
+	{
+		Code:        CodeMissingEntityName,
+		Summary:     "A suffix block has no owner name.",
+		Explanation: "The header comment of a suffix block doesn't start with the name of the entity that owns the block's suffixes.",
+		Rationale:   "Every suffix in the private section must be attributable to an owner, so that PSL maintainers know who requested it and who to contact about it.",
+		Fix:         "Start the block's header with a line of the form '// Entity Name : https://entity.example'.",
+	},
+	{
+		Code:         CodeMissingEntityEmail,
+		Summary:      "A suffix block has no contact email.",
+		Explanation:  "The header comment of a private suffix block has no 'Submitted by Name <email>' line.",
+		Rationale:    "PSL maintainers must be able to contact the owner of private suffixes, for example to confirm that the suffixes are still in use or to resolve a conflict.",
+		Fix:          "Add a line of the form '// Submitted by Name <email@entity.example>' to the block's header.",
+		Suppressible: true,
+	},
+	{
+		Code:         CodeInsecureEntityURL,
+		Summary:      "A suffix block URL does not use https.",
+		Explanation:  "The header comment of a private suffix block links to its owner's website with a URL that doesn't use https.",
+		Rationale:    "Links in the PSL are used to verify suffix owners, and should not be open to tampering in transit.",
+		Fix:          "Use an https:// URL. If the site doesn't support https, link to another page that does.",
+		Suppressible: true,
+	},
+	{
+		Code:        CodeEntityURLHasCredentials,
+		Summary:     "A suffix block URL contains login credentials.",
+		Explanation: "The header comment of a private suffix block has a URL with a user name or password in it.",
+		Rationale:   "The PSL is public, any credentials in it are leaked to everyone.",
+		Fix:         "Remove the credentials from the URL, and revoke them.",
+	},
+	{
+		Code:         CodeInvalidEntityURL,
+		Summary:      "A suffix block URL has an invalid host.",
+		Explanation:  "The host of a URL in the header comment of a private suffix block is not a valid domain name.",
+		Rationale:    "A URL that can't be resolved can't be used to verify or contact the suffix owner.",
+		Fix:          "Correct the URL.",
+		Suppressible: true,
+	},
+	{
+		Code:         CodeUnrelatedEntityURL,
+		Summary:      "A suffix block URL is unrelated to the block's suffixes.",
+		Explanation:  "A URL that a change adds to the header comment of a private suffix block is not a parent or child of the block's suffixes, and shares no distinctive label with them. This is only reported when checking a change, for example by check-pr.",
+		Rationale:    "The URL of a suffix block should identify the owner of its suffixes. An unrelated URL is often a copy-paste mistake, or a sign that the requester doesn't own the suffixes.",
+		Fix:          "Link to a website of the organization that owns the suffixes. If the URL is correct, explain the relationship in the pull request.",
+		Suppressible: true,
+	},
+	{
+		Code:         CodeInvalidEntityEmail,
+		Summary:      "A suffix block contact email is invalid.",
+		Explanation:  "The domain of a contact email address in the header comment of a private suffix block is not a valid domain name.",
+		Rationale:    "PSL maintainers must be able to contact the owner of private suffixes.",
+		Fix:          "Correct the email address.",
+		Suppressible: true,
+	},
+	{
+		Code:         CodePlaceholderEntityContact,
+		Summary:      "A suffix block contact is a placeholder.",
+		Explanation:  "A URL or email address in the header comment of a private suffix block uses a domain reserved for documentation, such as example.com. Blocks added by overlays are exempt.",
+		Rationale:    "Placeholder contacts are usually left over from the example in the PSL's documentation, and don't reach the suffix owner.",
+		Fix:          "Replace the placeholder with a real URL or email address of the suffix owner.",
+		Suppressible: true,
+	},
+	{
+		Code:        CodeUnstructuredHeader,
+		Summary:     "A suffix block header can't be migrated to the structured format automatically.",
+		Explanation: "'psltool migrate-headers' couldn't rewrite the header comment of a suffix block without losing information, for example because of free-form notes between the owner name and the contact lines.",
+		Rationale:   "Structured headers can be edited by tools without human review.",
+		Fix:         "Rewrite the header by hand in the structured format, moving free-form notes after the structured lines.",
+	},
+	{
+		Code:        CodeInvalidSuffix,
+		Summary:     "A suffix is not a valid PSL entry.",
+		Explanation: "A line that is not a comment or blank could not be parsed as a suffix, wildcard or wildcard exception. Common causes are empty labels, invalid characters, and exceptions that don't belong to a wildcard in the same block.",
+		Rationale:   "Consumers of the PSL treat invalid entries in different ways, which leads to inconsistent behavior across browsers and libraries.",
+		Fix:         "Correct the suffix. Exceptions like '!www.example.com' must follow the wildcard '*.example.com' they belong to. 'psltool validate --fix' rewrites suffixes that are not in canonical form, such as uppercase or punycode.",
+	},
+	{
+		Code:         CodeCommentPreventsSuffixSort,
+		Summary:      "A comment prevents sorting the suffixes of a block.",
+		Explanation:  "The suffixes of a block are not sorted, but sorting them would move them across a comment inside the block, which might describe only some of the suffixes.",
+		Rationale:    "Sorted suffixes make the list easier to review and reduce merge conflicts, but comments inside blocks can't be moved safely by tools.",
+		Fix:          "Sort the suffixes by hand, keeping each comment with the suffixes it describes.",
+		Suppressible: true,
+	},
+	{
+		Code:        CodeCommentPreventsSectionSort,
+		Summary:     "A comment prevents sorting the suffix blocks of a section.",
+		Explanation: "The suffix blocks of the private section are not sorted by entity name, but sorting them would move them across a comment between blocks, which might describe a group of blocks.",
+		Rationale:   "Sorted blocks make the list easier to review and reduce merge conflicts, but comments between blocks can't be moved safely by tools.",
+		Fix:         "Move the blocks to their sorted position by hand, keeping each comment with the blocks it describes.",
+	},
+	{
+		Code:        CodeDuplicateSection,
+		Summary:     "A section appears more than once.",
+		Explanation: "The file contains two sections with the same name.",
+		Rationale:   "Each of the PSL's sections must appear exactly once.",
+		Fix:         "Merge the contents of the duplicate section into the first one.",
+	},
+	{
+		Code:        CodeUnknownSection,
+		Summary:     "The file has a section other than ICANN DOMAINS and PRIVATE DOMAINS.",
+		Explanation: "A section marker names a section that the PSL format doesn't define.",
+		Rationale:   "Consumers only understand the ICANN DOMAINS and PRIVATE DOMAINS sections.",
+		Fix:         "Move the section's suffixes into the ICANN DOMAINS or PRIVATE DOMAINS section, and remove its markers.",
+	},
+	{
+		Code:        CodeMissingSection,
+		Summary:     "A required section is missing.",
+		Explanation: "The file doesn't contain the ICANN DOMAINS or the PRIVATE DOMAINS section.",
+		Rationale:   "Consumers rely on both sections being present.",
+		Fix:         "Add the missing section markers, even if the section is empty.",
+	},
+	{
+		Code:        CodeDuplicateSuffix,
+		Summary:     "A suffix is listed more than once.",
+		Explanation: "The same suffix or wildcard appears in two places in the list.",
+		Rationale:   "Each suffix must have a single owner. Duplicates make it unclear who to contact, and are left behind when one of the copies is removed.",
+		Fix:         "Remove one of the copies. If the suffix changed owners, remove it from the old owner's block. 'psltool validate --fix' removes copies within a single suffix block.",
+	},
+	{
+		Code:        CodeConflictingSuffixAndException,
+		Summary:     "A suffix is also an exception to a wildcard.",
+		Explanation: "A suffix is listed as a rule of its own, but also as an exception to a wildcard, which states that it is not a public suffix.",
+		Rationale:   "Exceptions take priority over all other rules, so the suffix rule has no effect and the list contradicts itself.",
+		Fix:         "Remove either the suffix or the exception, depending on whether the name should be a public suffix.",
+	},
+	{
+		Code:        CodeConfusableSuffix,
+		Summary:     "A suffix is visually confusable with another suffix.",
+		Explanation: "A suffix uses characters that look like the characters of another suffix in the list, so that the two can't be told apart by eye.",
+		Rationale:   "Confusable suffixes can be used to impersonate the owner of the other suffix.",
+		Fix:         "Check that the suffix is the one intended. If both suffixes are legitimate, the exemption must be added to psltool by the PSL maintainers.",
+	},
+	{
+		Code:         CodeMixedScriptSuffix,
+		Summary:      "A suffix label mixes Unicode scripts.",
+		Explanation:  "A label of the suffix contains characters from several writing systems, for example Latin and Cyrillic, in a combination that is not normally used.",
+		Rationale:    "Mixed-script labels are a common way to build names that impersonate other names.",
+		Fix:          "Write the label in a single script, or in a combination of scripts that is used by a real language.",
+		Suppressible: true,
+	},
+	{
+		Code:        CodeMissingTXTRecord,
+		Summary:     "A suffix has no _psl TXT record.",
+		Explanation: "A new or changed private suffix has no DNS TXT record at _psl.<suffix> pointing to the pull request that adds it.",
+		Rationale:   "The _psl TXT record proves that the person requesting a suffix controls its DNS.",
+		Fix:         "Add a TXT record at _psl.<suffix> with the URL of the pull request, for example \"https://github.com/publicsuffix/list/pull/1234\". If the suffix can't have a TXT record, the exemption must be added to psltool by the PSL maintainers.",
+	},
+	{
+		Code:        CodeTXTRecordMismatch,
+		Summary:     "A suffix's _psl TXT record points to an unrelated pull request.",
+		Explanation: "The _psl TXT record of a suffix points to a pull request that doesn't change that suffix.",
+		Rationale:   "The TXT record must prove control of the suffix for the change that is being made, not for an arbitrary change.",
+		Fix:         "Update the TXT record to point to the pull request that adds or changes the suffix.",
+	},
+	{
+		Code:        CodeTXTCheckFailure,
+		Summary:     "The _psl TXT record of a suffix could not be checked.",
+		Explanation: "Looking up the _psl TXT record of a suffix, or the pull request it points to, failed with an error.",
+		Rationale:   "Changes to private suffixes can only be accepted once their TXT records have been verified.",
+		Fix:         "Check that the suffix's DNS servers answer correctly, and retry the validation.",
+	},
+	{
+		Code:        CodeInvalidSuppression,
+		Summary:     "A psl:allow annotation is malformed.",
+		Explanation: "A comment line in a suffix block starts with 'psl:allow', but doesn't name a check that can be suppressed, or doesn't give a reason. Malformed annotations don't suppress anything.",
+		Rationale:   "Suppressions are exemptions from the PSL's policies, and reviewers need to know why each one was granted.",
+		Fix:         "Write the annotation as 'psl:allow <check> reason=\"<why the check doesn't apply>\"', where <check> is the name or ID of a suppressible check listed by 'psltool errors'.",
+	},
+	{
+		Code:        CodeInvalidGroupMarker,
+		Summary:     "A group marker is malformed.",
+		Explanation: "A comment starts with 'psl:begin-group' or 'psl:end-group', but the rest of the line is not a list of known key=\"value\" attributes, or has no owner. The comment is treated as an ordinary comment.",
+		Rationale:   "Managed groups change how a section is sorted, so their markers must be unambiguous.",
+		Fix:         "Write the markers as 'psl:begin-group owner=\"<name>\" sort-key=\"<name>\"' and 'psl:end-group owner=\"<name>\"'. The sort key is optional, and defaults to the owner.",
+	},
+	{
+		Code:        CodeNestedGroup,
+		Summary:     "A managed group starts inside another managed group.",
+		Explanation: "A 'psl:begin-group' marker appears before the end marker of the previous group. The inner group's blocks are treated as part of the outer group.",
+		Rationale:   "Groups cannot be nested, each block belongs to at most one group.",
+		Fix:         "End the previous group before starting the next one, or remove the stray begin marker.",
+	},
+	{
+		Code:        CodeUnclosedGroup,
+		Summary:     "A managed group is missing its end marker.",
+		Explanation: "A group was started with a 'psl:begin-group' marker, but the section ends before the matching 'psl:end-group' marker.",
+		Rationale:   "Without an end marker, every block after the group's start is sorted as part of the group, and escapes the section's sorting rules.",
+		Fix:         "Add the missing 'psl:end-group owner=\"<name>\"' marker after the group's last suffix block.",
+	},
+	{
+		Code:        CodeUnstartedGroup,
+		Summary:     "A group end marker has no matching begin marker.",
+		Explanation: "A 'psl:end-group' marker appears outside of any group.",
+		Rationale:   "Unbalanced group markers make it unclear which blocks belong to a group.",
+		Fix:         "Remove the stray end marker, or add the missing begin marker.",
+	},
+	{
+		Code:        CodeMismatchedGroup,
+		Summary:     "A managed group is closed with a different owner than it was opened with.",
+		Explanation: "The owner in the 'psl:end-group' marker that closes a group is not the owner in its 'psl:begin-group' marker.",
+		Rationale:   "Begin and end markers are matched by owner, so that a mistake in one group can't silently swallow another.",
+		Fix:         "Correct the owner in the end marker.",
+	},
+	{
+		Code:        CodeMisplacedGroupMarker,
+		Summary:     "A group marker is not at the start of a standalone comment.",
+		Explanation: "A 'psl:begin-group' or 'psl:end-group' line is part of a suffix block's comments, is not the first line of its comment, or is outside of any section. It doesn't delimit a group.",
+		Rationale:   "Group markers are only recognized as the first line of a comment that is separated from suffix blocks by blank lines, so that they can't be confused with a block's header.",
+		Fix:         "Move the marker to its own comment, separated from the surrounding suffix blocks by blank lines.",
+	},
+	{
+		Code:        CodeEmptyGroup,
+		Summary:     "A managed group has no suffix blocks.",
+		Explanation: "There are no suffix blocks between a group's begin and end markers.",
+		Rationale:   "Empty groups are usually left over after their blocks were removed, and only add noise to the list.",
+		Fix:         "Remove the group's markers.",
+	},
+	{
+		Code:        CodeDuplicateGroup,
+		Summary:     "A section has several managed groups with the same owner.",
+		Explanation: "Two groups in the same section have the same owner.",
+		Rationale:   "Each owner's blocks are kept together in a single group, so that the owner has one place to maintain.",
+		Fix:         "Merge the groups into the first one.",
+	},
+	{
+		Code:        CodeNewSuppression,
+		Summary:     "A change adds a psl:allow annotation.",
+		Explanation: "A suffix block gains a psl:allow annotation that opts it out of a check. This is only reported when checking a change, for example by check-pr.",
+		Rationale:   "Annotations exempt a block from the PSL's policies, so like the exemption lists in psltool, each one needs the approval of a PSL maintainer.",
+		Fix:         "Fix the problem that the annotation suppresses and remove the annotation, or ask a PSL maintainer to approve it.",
+	},
+}
@@ -0,0 +1,157 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestCatalog(t *testing.T) {
+	// Every error type in errors.go. Together with the check against
+	// errorTypeNames below, adding a new error type without a code
+	// and catalog entry fails this test.
+	allErrors := []error{
+		ErrInvalidEncoding{},
+		ErrInvalidUnicode{},
+		ErrSectionInSuffixBlock{},
+		ErrUnclosedSection{},
+		ErrNestedSection{},
+		ErrUnstartedSection{},
+		ErrMismatchedSection{},
+		ErrUnknownSectionMarker{},
+		ErrMissingEntityName{},
+		ErrMissingEntityEmail{},
+		ErrInsecureEntityURL{},
+		ErrEntityURLHasCredentials{},
+		ErrInvalidEntityURL{},
+		ErrUnrelatedEntityURL{},
+		ErrInvalidEntityEmail{},
+		ErrPlaceholderEntityContact{},
+		ErrUnstructuredHeader{},
+		ErrInvalidSuffix{},
+		ErrCommentPreventsSuffixSort{},
+		ErrCommentPreventsSectionSort{},
+		ErrDuplicateSection{},
+		ErrUnknownSection{},
+		ErrMissingSection{},
+		ErrDuplicateSuffix{},
+		ErrConflictingSuffixAndException{},
+		ErrConfusableSuffix{},
+		ErrMixedScriptSuffix{},
+		ErrMissingTXTRecord{},
+		ErrTXTRecordMismatch{},
+		ErrTXTCheckFailure{},
+		ErrInvalidSuppression{},
+		ErrNewSuppression{},
+		ErrInvalidGroupMarker{},
+		ErrNestedGroup{},
+		ErrUnclosedGroup{},
+		ErrUnstartedGroup{},
+		ErrMismatchedGroup{},
+		ErrMisplacedGroupMarker{},
+		ErrEmptyGroup{},
+		ErrDuplicateGroup{},
+	}
+
+	listed := map[string]bool{}
+	for _, err := range allErrors {
+		listed[reflect.TypeOf(err).Name()] = true
+	}
+	for _, name := range errorTypeNames(t) {
+		if !listed[name] {
+			t.Errorf("%s is missing from allErrors", name)
+		}
+	}
+
+	validID := regexp.MustCompile(`^PSL[0-9]{4}$`)
+	validName := regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+	ids := map[string]bool{}
+	names := map[string]bool{}
+	for _, e := range Catalog() {
+		if !validID.MatchString(e.ID) {
+			t.Errorf("code %q has invalid ID", e.Code)
+		}
+		if !validName.MatchString(e.Name) {
+			t.Errorf("code %q has invalid name", e.Code)
+		}
+		if ids[e.ID] {
+			t.Errorf("duplicate code ID %s", e.ID)
+		}
+		if names[e.Name] {
+			t.Errorf("duplicate code name %s", e.Name)
+		}
+		ids[e.ID], names[e.Name] = true, true
+		if e.Summary == "" || e.Explanation == "" || e.Rationale == "" || e.Fix == "" {
+			t.Errorf("catalog entry for %s is incomplete", e.Code)
+		}
+	}
+
+	used := map[Code]bool{}
+	for _, err := range allErrors {
+		code, ok := ErrorCode(err)
+		if !ok {
+			t.Errorf("%T has no error code", err)
+			continue
+		}
+		if used[code] {
+			t.Errorf("%T reuses code %s", err, code)
+		}
+		used[code] = true
+		if _, ok := LookupCode(code.ID); !ok {
+			t.Errorf("%T has code %s, which is not in the catalog", err, code)
+		}
+	}
+
+	wrapped := fmt.Errorf("wrapped: %w", ErrMissingTXTRecord{})
+	if code, ok := ErrorCode(wrapped); !ok || code != CodeMissingTXTRecord {
+		t.Errorf("ErrorCode(wrapped) = %v, %v, want %v, true", code, ok, CodeMissingTXTRecord)
+	}
+}
+
+// errorTypeNames returns the names of the Err* types declared in
+// errors.go.
+func errorTypeNames(t *testing.T) []string {
+	t.Helper()
+	f, err := goparser.ParseFile(token.NewFileSet(), "errors.go", nil, goparser.SkipObjectResolution)
+	if err != nil {
+		t.Fatalf("parsing errors.go: %v", err)
+	}
+	var ret []string
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			if name := spec.(*ast.TypeSpec).Name.Name; strings.HasPrefix(name, "Err") {
+				ret = append(ret, name)
+			}
+		}
+	}
+	if len(ret) == 0 {
+		t.Fatal("found no error types in errors.go")
+	}
+	return ret
+}
+
+func TestLookupCode(t *testing.T) {
+	for _, s := range []string{"PSL0028", "psl0028", "missing-txt-record", "Missing-TXT-Record"} {
+		e, ok := LookupCode(s)
+		if !ok {
+			t.Errorf("LookupCode(%q) found nothing", s)
+			continue
+		}
+		if e.Code != CodeMissingTXTRecord {
+			t.Errorf("LookupCode(%q) = %s, want %s", s, e.Code, CodeMissingTXTRecord)
+		}
+	}
+	if e, ok := LookupCode("PSL9999"); ok {
+		t.Errorf("LookupCode(PSL9999) = %s, want not found", e.Code)
+	}
+}
@@ -1,9 +1,9 @@
 package parser
 
 import (
+	"bytes"
 	"fmt"
 	"slices"
-	"strings"
 
 	"github.com/publicsuffix/list/tools/internal/domain"
 )
@@ -17,9 +17,11 @@ import (
 //
 // If the list's structure prevents Clean from applying some necessary
 // changes, Clean applies as many changes as possible then returns
-// errors describing the cleanups that could not take place.
+// errors describing the cleanups that could not take place. Errors
+// that are suppressed by a psl:allow annotation are returned as
+// SuppressedErrors.
 func (l *List) Clean() []error {
-	return cleanBlock(l, true)
+	return applySuppressions(l, cleanBlock(l, true))
 }
 
 func cleanBlock(b Block, reportCommentBlockages bool) []error {
@@ -42,6 +44,9 @@ func cleanBlock(b Block, reportCommentBlockages bool) []error {
 				// error.
 				ret = append(ret, cleanBlock(child, false)...)
 			}
+			// The ICANN section must be sorted by TLD, except for
+			// the gTLD region which belongs to the gTLD updater.
+			ret = append(ret, sortICANNSection(v)...)
 		case "PRIVATE DOMAINS":
 			for _, child := range v.Blocks {
 				ret = append(ret, cleanBlock(child, reportCommentBlockages)...)
@@ -50,6 +55,12 @@ func cleanBlock(b Block, reportCommentBlockages bool) []error {
 			// the names of the maintainers of suffix blocks.
 			ret = append(ret, sortSection(v)...)
 		}
+	case *Group:
+		// Blocks within a group stay in the order that the group's
+		// owner chose, only their contents are cleaned.
+		for _, child := range v.Blocks {
+			ret = append(ret, cleanBlock(child, reportCommentBlockages)...)
+		}
 	case *Suffixes:
 		for _, child := range v.Blocks {
 			ret = append(ret, cleanBlock(child, reportCommentBlockages)...)
@@ -74,8 +85,29 @@ func cleanWildcard(w *Wildcard) {
 }
 
 func sortSection(s *Section) []error {
+	// Managed groups of suffix blocks are treated as a single logical
+	// block for sorting, see sectionGroups.
+	groups := sectionGroups(s)
+
+	// Collation keys are expensive to compute, so compute each one
+	// once rather than on every comparison.
+	for i := range groups {
+		groups[i].SortKey = commentSortKey(groups[i].Key)
+	}
+
+	blocks, errs := sortSectionGroups(groups, func(a, b sectionGroup) int {
+		return bytes.Compare(a.SortKey, b.SortKey)
+	})
+	s.Blocks = blocks
+	return errs
+}
+
+// sortSectionGroups sorts groups using compare, and returns the
+// blocks of the sorted groups along with errors for the sorting
+// problems it could not fix.
+func sortSectionGroups(groups []sectionGroup, compare func(a, b sectionGroup) int) ([]Block, []error) {
 	// There are two difficult parts aspects to sorting a section:
-	// free-floating comments, and Amazon.
+	// free-floating comments, and managed groups.
 	//
 	// We cannot safely reorder suffix blocks across a free-floating
 	// comment, because we don't know if that breaks human-readable
@@ -90,102 +122,33 @@ func sortSection(s *Section) []error {
 	// ordering issues as possible, and report errors for the ones we
 	// can't fix without help.
 	//
-	// Amazon has a semi-automated group of suffix blocks, which are
-	// in the PSL at the correct sort location for "Amazon" but are
-	// not correctly sorted relative to non-Amazon blocks. This causes
-	// a lot of churn in the sorting for what is currently an edge
-	// case.
-	//
-	// For a smoother transition, we treat the entire amazon group as
-	// a single logical block for sorting, so that it stays in the
-	// correct position for the name "Amazon" but can do whatever it
-	// likes within.
-	//
-	// TODO: now that we have automated formatting, maybe stop
-	// treating Amazon specially? Machine edits can just update the
-	// Amazon entries and re-Clean as needed.
-	type group struct {
-		Key    string // name of block maintainer, or "" for barrier comments
-		Blocks []Block
-	}
-	var groups []group
-
-	const (
-		amazonGroupStart = "Amazon : https://www.amazon.com"
-		amazonGroupEnd   = "concludes Amazon"
-	)
-
-	inAmazonGroup := false
-	for _, block := range s.Blocks {
-		switch v := block.(type) {
-		case *Comment:
-			switch {
-			case !inAmazonGroup && strings.Contains(v.Text[0], amazonGroupStart):
-				// Start of the Amazon group. We will accumulate
-				// suffix blocks into here further down.
-				inAmazonGroup = true
-				groups = append(groups, group{
-					Key:    "Amazon",
-					Blocks: []Block{block},
-				})
-			case inAmazonGroup:
-				last := len(groups) - 1
-				groups[last].Blocks = append(groups[last].Blocks, block)
-				if strings.Contains(v.Text[0], amazonGroupEnd) {
-					// End of Amazon group, go back to normal
-					// behavior.
-					inAmazonGroup = false
-				}
-			default:
-				// Barrier comment
-				groups = append(groups, group{
-					Key:    "",
-					Blocks: []Block{block},
-				})
-			}
-		case *Suffixes:
-			if inAmazonGroup {
-				last := len(groups) - 1
-				groups[last].Blocks = append(groups[last].Blocks, v)
-			} else {
-				groups = append(groups, group{v.Info.Name, []Block{block}})
-			}
-		default:
-			panic("unknown ast node")
-		}
-	}
+	// Managed groups were already turned into single sectionGroups
+	// by the caller.
 
 	// Scan through the groups, looking for barrier comments. Sort the
 	// groups between the barriers, and just check ordering across
 	// barriers.
 	var (
 		errs           []error
-		prevGroupEnd   *Suffixes
-		prevComment    *Comment
+		prevGroup      sectionGroup // Last group before the barrier
+		prevBarrier    Block
 		thisGroupStart int
 	)
 
-	sortAndCheck := func(groups []group) {
+	sortAndCheck := func(groups []sectionGroup) {
 		if len(groups) == 0 {
 			return
 		}
 
-		slices.SortFunc(groups, func(a, b group) int {
-			return compareCommentText(a.Key, b.Key)
-		})
+		slices.SortStableFunc(groups, compare)
 
-		if prevGroupEnd == nil {
-			// First group.
-			lastGroup := groups[len(groups)-1]
-			prevGroupEnd = lastGroup.Blocks[len(lastGroup.Blocks)-1].(*Suffixes)
-		} else if compareCommentText(prevGroupEnd.Info.Name, groups[0].Key) <= 0 {
-			// Inter-group order is correct.
-			lastGroup := groups[len(groups)-1]
-			prevGroupEnd = lastGroup.Blocks[len(lastGroup.Blocks)-1].(*Suffixes)
+		if prevBarrier == nil || compare(prevGroup, groups[0]) <= 0 {
+			// First group, or inter-group order is correct.
+			prevGroup = groups[len(groups)-1]
 		} else {
 			// Wrong inter-group order. Report error and keep the same
-			// prevGroupEnd, since it's bigger.
-			errs = append(errs, ErrCommentPreventsSectionSort{prevComment.SourceRange})
+			// prevGroup, since it's bigger.
+			errs = append(errs, ErrCommentPreventsSectionSort{prevBarrier.SrcRange()})
 		}
 	}
 
@@ -197,28 +160,158 @@ func sortSection(s *Section) []error {
 
 		// Found a boundary.
 		sortAndCheck(groups[thisGroupStart:i])
-		prevComment = group.Blocks[0].(*Comment)
+		prevBarrier = group.Blocks[0]
 		thisGroupStart = i + 1
 	}
 	if thisGroupStart != len(groups) {
 		sortAndCheck(groups[thisGroupStart:])
 	}
 
-	// Reassemble the new s.Blocks from groups. Note, we must not
-	// reuse the s.Blocks slice, because all the slices in the groups
+	// Reassemble the new blocks from groups. Note, we must not reuse
+	// the caller's Blocks slice, because all the slices in the groups
 	// are referencing the same backing array. If we start overwriting
 	// that array, we might corrupt future groups and end up with a
 	// list that deletes a bunch of suffix blocks and duplicates a
 	// bunch of others in the wrong place.
-	ret := make([]Block, 0, len(s.Blocks))
+	ret := make([]Block, 0, len(groups))
 	for _, group := range groups {
 		ret = append(ret, group.Blocks...)
 	}
-	s.Blocks = ret
+	return ret, errs
+}
 
+// sectionGroup is a block in a section that sorts as a unit.
+type sectionGroup struct {
+	Key     string       // name of block maintainer or TLD, or "" for barriers
+	SortKey []byte       // collation key of Key, in the private section
+	TLD     domain.Label // TLD of the block, in the ICANN section
+	Blocks  []Block
+}
+
+// sectionGroups splits the blocks of s into units for sorting. Each
+// suffix block and each managed group is its own unit, sorted by the
+// block's entity name or the group's sort key. Free-floating comments
+// are in units of their own with an empty Key, and act as sorting
+// barriers.
+func sectionGroups(s *Section) []sectionGroup {
+	var groups []sectionGroup
+	for _, block := range s.Blocks {
+		switch v := block.(type) {
+		case *Comment:
+			// Barrier comment
+			groups = append(groups, sectionGroup{Key: "", Blocks: []Block{block}})
+		case *Group:
+			groups = append(groups, sectionGroup{Key: v.SortKey, Blocks: []Block{block}})
+		case *Suffixes:
+			groups = append(groups, sectionGroup{Key: v.Info.Name, Blocks: []Block{block}})
+		default:
+			panic("unknown ast node")
+		}
+	}
+	return groups
+}
+
+// gTLDRegionMarker is the first line of the free-floating comment
+// that starts the gTLD region at the end of the ICANN section. The
+// region is rewritten wholesale by the gTLD updater (see
+// tools/newgtlds.go), which also decides its order.
+const gTLDRegionMarker = "newGTLDs"
+
+// gTLDRegionStart returns the index in s.Blocks at which the gTLD
+// region begins, or len(s.Blocks) if s has no gTLD region.
+func gTLDRegionStart(s *Section) int {
+	for i, b := range s.Blocks {
+		if c, ok := b.(*Comment); ok && c.Text[0] == gTLDRegionMarker {
+			return i
+		}
+	}
+	return len(s.Blocks)
+}
+
+// sortICANNSection sorts the blocks of the ICANN section s by TLD,
+// and returns errors for the sorting problems it could not fix.
+//
+// Blocks are compared by the TLD of their first rule, and blocks for
+// the same TLD keep their relative order. As in the private section,
+// free-floating comments are barriers that blocks do not move across,
+// and the gTLD region is left alone entirely.
+//
+// IDN ccTLD blocks are sorted by hand, by the ISO 3166 code in their
+// header and then by U-label, so they stay where they are. The run of
+// IDN ccTLDs splits the section into regions that are sorted
+// separately, since the list keeps some ASCII ccTLDs after it.
+func sortICANNSection(s *Section) []error {
+	end := gTLDRegionStart(s)
+	var (
+		blocks []Block
+		errs   []error
+		start  int
+	)
+	for i := 0; i <= end; i++ {
+		if i < end && !isIDNBlock(s.Blocks[i]) {
+			continue
+		}
+		sorted, regionErrs := sortSectionGroups(icannGroups(s.Blocks[start:i]), func(a, b sectionGroup) int {
+			return a.TLD.Compare(b.TLD)
+		})
+		blocks = append(blocks, sorted...)
+		errs = append(errs, regionErrs...)
+		if i < end {
+			blocks = append(blocks, s.Blocks[i])
+		}
+		start = i + 1
+	}
+	s.Blocks = append(blocks, s.Blocks[end:]...)
 	return errs
 }
 
+// icannGroups splits blocks into units for sorting by TLD. Each
+// suffix block and each managed group is its own unit, keyed by the
+// TLD of its first rule. Free-floating comments, blocks without
+// rules and IDN ccTLD blocks are barriers.
+func icannGroups(blocks []Block) []sectionGroup {
+	var groups []sectionGroup
+	for _, block := range blocks {
+		tld, ok := blockTLD(block)
+		if !ok || isIDN(tld) {
+			groups = append(groups, sectionGroup{Key: "", Blocks: []Block{block}})
+			continue
+		}
+		groups = append(groups, sectionGroup{Key: tld.String(), TLD: tld, Blocks: []Block{block}})
+	}
+	return groups
+}
+
+// blockTLD returns the TLD of the first rule in b, if b contains any
+// rules.
+func blockTLD(b Block) (domain.Label, bool) {
+	for _, r := range BlocksOfType[Block](b) {
+		var d domain.Name
+		switch v := r.(type) {
+		case *Suffix:
+			d = v.Domain
+		case *Wildcard:
+			d = v.Domain
+		default:
+			continue
+		}
+		labels := d.Labels()
+		return labels[len(labels)-1], true
+	}
+	return domain.Label{}, false
+}
+
+// isIDNBlock reports whether b is a block for an IDN TLD.
+func isIDNBlock(b Block) bool {
+	tld, ok := blockTLD(b)
+	return ok && isIDN(tld)
+}
+
+// isIDN reports whether tld is an internationalized label.
+func isIDN(tld domain.Label) bool {
+	return tld.String() != tld.ASCIIString()
+}
+
 func sortSuffixes(s *Suffixes, reportCommentBlockages bool) []error {
 	// Suffix sorting has the same problem as section sorting: inline
 	// comments act as barriers that prevent movement of a suffix
@@ -397,28 +490,5 @@ func rewriteSuffixesMetadata(s *Suffixes) {
 	out.SourceRange = SourceRange{}
 	out.Text = out.Text[:0]
 
-	urls := inf.URLs
-	if inf.Name != "" && len(urls) > 0 {
-		// First line that looks like "<name> : <url>"
-		out.Text = append(out.Text, fmt.Sprintf("%s : %s", inf.Name, urls[0]))
-		urls = urls[1:]
-	} else if inf.Name != "" {
-		// First line that looks like "<name>"
-		out.Text = append(out.Text, inf.Name)
-	}
-
-	for _, u := range urls {
-		out.Text = append(out.Text, u.String())
-	}
-	for _, m := range inf.Maintainers {
-		// We could use m.String(), but doing that quotes the name,
-		// and ends up inserting a lot of escape chars for non-ascii
-		// names. The mail package can parse unquoted names just fine,
-		// so we prefer the form that is more human readable.
-		emailStr := strings.TrimSpace(fmt.Sprintf("%s <%s>", m.Name, m.Address))
-		out.Text = append(out.Text, strings.TrimSpace(fmt.Sprintf("Submitted by %s", emailStr)))
-	}
-	for _, o := range inf.Other {
-		out.Text = append(out.Text, o)
-	}
+	out.Text = append(out.Text, formatMaintainerInfo(inf)...)
 }
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"os"
 	"testing"
 )
 
@@ -199,7 +200,7 @@ func TestClean(t *testing.T) {
 		},
 
 		{
-			name: "sort_private_section_only",
+			name: "sort_icann_and_private_sections",
 			in: list(
 				section(1, 1, "ICANN DOMAINS",
 					suffixes(1, 1, info(".ZA", nil, nil, nil, true),
@@ -228,18 +229,18 @@ func TestClean(t *testing.T) {
 				),
 			),
 			want: list(
-				// Unchanged compared to above
+				// ICANN blocks are sorted by TLD.
 				section(1, 1, "ICANN DOMAINS",
+					suffixes(2, 2, info(".BE", nil, nil, nil, true),
+						comment(1, ".BE"),
+						suffix(3, "be"),
+						suffix(2, "com.be"),
+					),
 					suffixes(1, 1, info(".ZA", nil, nil, nil, true),
 						comment(1, ".ZA"),
 						suffix(2, "za"),
 						suffix(3, "co.za"),
 					),
-					suffixes(2, 2, info(".BE", nil, nil, nil, true),
-						comment(1, ".BE"),
-						suffix(2, "com.be"),
-						suffix(3, "be"),
-					),
 				),
 
 				// Suffix blocks and suffixes are sorted.
@@ -258,6 +259,140 @@ func TestClean(t *testing.T) {
 			),
 		},
 
+		{
+			name: "sort_icann_section_by_tld",
+			in: list(
+				// Blocks for the same TLD keep their order. IDN
+				// ccTLD blocks stay where they are, and the ASCII
+				// blocks on either side of them are sorted
+				// separately.
+				section(1, 1, "ICANN DOMAINS",
+					suffixes(1, 1, info("vn", nil, nil, nil, true),
+						comment(1, "vn"),
+						suffix(2, "vn"),
+					),
+					suffixes(2, 2, info("vn geographical names", nil, nil, nil, true),
+						comment(1, "vn geographical names"),
+						suffix(2, "angiang.vn"),
+					),
+					suffixes(3, 3, info("ar", nil, nil, nil, true),
+						comment(1, "ar"),
+						suffix(2, "ar"),
+					),
+					suffixes(4, 4, info("xn--p1ai", nil, nil, nil, true),
+						comment(1, "xn--p1ai"),
+						suffix(2, "рф"),
+					),
+					suffixes(5, 5, info("xn--90ae", nil, nil, nil, true),
+						comment(1, "xn--90ae"),
+						suffix(2, "бг"),
+					),
+					suffixes(6, 6, info("za", nil, nil, nil, true),
+						comment(1, "za"),
+						suffix(2, "za"),
+					),
+					suffixes(7, 7, info("xxx", nil, nil, nil, true),
+						comment(1, "xxx"),
+						suffix(2, "xxx"),
+					),
+				),
+			),
+			want: list(
+				section(1, 1, "ICANN DOMAINS",
+					suffixes(3, 3, info("ar", nil, nil, nil, true),
+						comment(1, "ar"),
+						suffix(2, "ar"),
+					),
+					suffixes(1, 1, info("vn", nil, nil, nil, true),
+						comment(1, "vn"),
+						suffix(2, "vn"),
+					),
+					suffixes(2, 2, info("vn geographical names", nil, nil, nil, true),
+						comment(1, "vn geographical names"),
+						suffix(2, "angiang.vn"),
+					),
+					suffixes(4, 4, info("xn--p1ai", nil, nil, nil, true),
+						comment(1, "xn--p1ai"),
+						suffix(2, "рф"),
+					),
+					suffixes(5, 5, info("xn--90ae", nil, nil, nil, true),
+						comment(1, "xn--90ae"),
+						suffix(2, "бг"),
+					),
+					suffixes(7, 7, info("xxx", nil, nil, nil, true),
+						comment(1, "xxx"),
+						suffix(2, "xxx"),
+					),
+					suffixes(6, 6, info("za", nil, nil, nil, true),
+						comment(1, "za"),
+						suffix(2, "za"),
+					),
+				),
+			),
+		},
+
+		{
+			name: "sort_icann_section_with_barriers",
+			in: list(
+				// Blocks are sorted between barrier comments, and
+				// misordering across a barrier is reported. The gTLD
+				// region is never touched.
+				section(1, 1, "ICANN DOMAINS",
+					suffixes(1, 1, info("de", nil, nil, nil, true),
+						comment(1, "de"),
+						suffix(2, "de"),
+					),
+					suffixes(2, 2, info("ca", nil, nil, nil, true),
+						comment(1, "ca"),
+						suffix(2, "ca"),
+					),
+					comment(3, "IDN ccTLDs"),
+					suffixes(4, 4, info("bg", nil, nil, nil, true),
+						comment(1, "bg"),
+						suffix(2, "bg"),
+					),
+					comment(5, "newGTLDs"),
+					suffixes(6, 6, info("zip", nil, nil, nil, true),
+						comment(1, "zip"),
+						suffix(2, "zip"),
+					),
+					suffixes(7, 7, info("aaa", nil, nil, nil, true),
+						comment(1, "aaa"),
+						suffix(2, "aaa"),
+					),
+				),
+			),
+			want: list(
+				section(1, 1, "ICANN DOMAINS",
+					suffixes(2, 2, info("ca", nil, nil, nil, true),
+						comment(1, "ca"),
+						suffix(2, "ca"),
+					),
+					suffixes(1, 1, info("de", nil, nil, nil, true),
+						comment(1, "de"),
+						suffix(2, "de"),
+					),
+					comment(3, "IDN ccTLDs"),
+					suffixes(4, 4, info("bg", nil, nil, nil, true),
+						comment(1, "bg"),
+						suffix(2, "bg"),
+					),
+					comment(5, "newGTLDs"),
+					suffixes(6, 6, info("zip", nil, nil, nil, true),
+						comment(1, "zip"),
+						suffix(2, "zip"),
+					),
+					suffixes(7, 7, info("aaa", nil, nil, nil, true),
+						comment(1, "aaa"),
+						suffix(2, "aaa"),
+					),
+				),
+			),
+			wantErr: []error{
+				ErrCommentPreventsSectionSort{mkSrc(3, 4)},
+			},
+		},
+
 		{
 			name: "sort_private_section_by_entity_name",
 			in: list(
@@ -426,26 +561,28 @@ func TestClean(t *testing.T) {
 		},
 
 		{
-			name: "sort_private_section_amazon_block",
+			name: "sort_private_section_managed_group",
 			in: list(
-				// Sorted correctly, despite the Amazon block in the
-				// middle being sorted incorrectly relative to its
-				// surroundings.
+				// Sorted correctly, despite the blocks in the
+				// middle group being sorted incorrectly relative to
+				// their surroundings.
 				section(1, 1, "PRIVATE DOMAINS",
 					suffixes(1, 1, info("AAA", nil, nil, nil, true),
 						comment(1, "AAA"),
 						suffix(2, "bar"),
 					),
-					comment(2, "Amazon : https://www.amazon.com", "Several blocks follow"),
-					suffixes(3, 3, info("AWS", nil, nil, nil, true),
-						comment(1, "AWS"),
-						suffix(2, "foo"),
-					),
-					suffixes(4, 4, info("eero", nil, nil, nil, true),
-						comment(1, "eero"),
-						suffix(2, "zot"),
+					group(2, 6, "Amazon", "Amazon",
+						comment(2, `psl:begin-group owner="Amazon"`, "Amazon : https://www.amazon.com", "Several blocks follow"),
+						suffixes(3, 3, info("AWS", nil, nil, nil, true),
+							comment(1, "AWS"),
+							suffix(2, "foo"),
+						),
+						suffixes(4, 4, info("eero", nil, nil, nil, true),
+							comment(1, "eero"),
+							suffix(2, "zot"),
+						),
+						comment(5, `psl:end-group owner="Amazon"`, "concludes Amazon"),
 					),
-					comment(5, "concludes Amazon"),
 					suffixes(6, 6, info("Aviating Ltd.", nil, nil, nil, true),
 						comment(1, "Aviating Ltd."),
 						suffix(2, "qux"),
@@ -458,16 +595,18 @@ func TestClean(t *testing.T) {
 						comment(1, "AAA"),
 						suffix(2, "bar"),
 					),
-					comment(2, "Amazon : https://www.amazon.com", "Several blocks follow"),
-					suffixes(3, 3, info("AWS", nil, nil, nil, true),
-						comment(1, "AWS"),
-						suffix(2, "foo"),
-					),
-					suffixes(4, 4, info("eero", nil, nil, nil, true),
-						comment(1, "eero"),
-						suffix(2, "zot"),
+					group(2, 6, "Amazon", "Amazon",
+						comment(2, `psl:begin-group owner="Amazon"`, "Amazon : https://www.amazon.com", "Several blocks follow"),
+						suffixes(3, 3, info("AWS", nil, nil, nil, true),
+							comment(1, "AWS"),
+							suffix(2, "foo"),
+						),
+						suffixes(4, 4, info("eero", nil, nil, nil, true),
+							comment(1, "eero"),
+							suffix(2, "zot"),
+						),
+						comment(5, `psl:end-group owner="Amazon"`, "concludes Amazon"),
 					),
-					comment(5, "concludes Amazon"),
 					suffixes(6, 6, info("Aviating Ltd.", nil, nil, nil, true),
 						comment(1, "Aviating Ltd."),
 						suffix(2, "qux"),
@@ -724,3 +863,34 @@ func TestClean(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkClean(b *testing.B) {
+	bs, err := os.ReadFile("../../../public_suffix_list.dat")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for range b.N {
+		b.StopTimer()
+		psl, _ := Parse(bs)
+		b.StartTimer()
+		psl.Clean()
+	}
+}
+
+func BenchmarkCleanParallel(b *testing.B) {
+	bs, err := os.ReadFile("../../../public_suffix_list.dat")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	// Parse outside of the timed portion. Clean on an already clean
+	// list doesn't change anything, so each goroutine can repeatedly
+	// clean its own copy without reparsing.
+	b.RunParallel(func(pb *testing.PB) {
+		psl, _ := Parse(bs)
+		for pb.Next() {
+			psl.Clean()
+		}
+	})
+}
@@ -296,6 +296,8 @@ func (d *differ) makeKey(b Block, parentKey string) string {
 		return fmt.Sprintf("%s;List", parentKey)
 	case *Section:
 		return fmt.Sprintf("%s;Section,%q", parentKey, v.Name)
+	case *Group:
+		return fmt.Sprintf("%s;Group,%q", parentKey, v.Owner)
 	case *Suffixes:
 		// Note parsed suffix metadata isn't included in the identity,
 		// to avoid marking all suffixes in a block changed when
@@ -308,7 +310,7 @@ func (d *differ) makeKey(b Block, parentKey string) string {
 	case *Suffix:
 		return fmt.Sprintf("%s;Suffix,%q", parentKey, v.Domain)
 	case *Wildcard:
-		return fmt.Sprintf("%s;Wildcard,%q,%#v", parentKey, v.Domain, v.Exceptions)
+		return fmt.Sprintf("%s;Wildcard,%q,%q", parentKey, v.Domain, v.Exceptions)
 	case *Comment:
 		return fmt.Sprintf("%s;Comment,%#v", parentKey, v.Text)
 	default:
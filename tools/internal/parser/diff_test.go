@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"os"
 	"slices"
 	"testing"
 )
@@ -462,6 +463,13 @@ func deleteUnchanged(b Block) {
 		if len(v.Blocks) == 0 {
 			v.Blocks = nil
 		}
+	case *Group:
+		v.Blocks = slices.DeleteFunc(v.Blocks, func(child Block) bool {
+			return !child.Changed()
+		})
+		if len(v.Blocks) == 0 {
+			v.Blocks = nil
+		}
 	case *Suffixes:
 		v.Blocks = slices.DeleteFunc(v.Blocks, func(child Block) bool {
 			return !child.Changed()
@@ -478,3 +486,17 @@ func deleteUnchanged(b Block) {
 		deleteUnchanged(child)
 	}
 }
+
+func BenchmarkSetBaseVersion(b *testing.B) {
+	bs, err := os.ReadFile("../../../public_suffix_list.dat")
+	if err != nil {
+		b.Fatal(err)
+	}
+	old, _ := Parse(bs)
+	cur, _ := Parse(bs)
+
+	b.ResetTimer()
+	for range b.N {
+		cur.SetBaseVersion(old, true)
+	}
+}
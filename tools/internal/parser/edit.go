@@ -0,0 +1,630 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/publicsuffix/list/tools/internal/domain"
+)
+
+// AddSuffixes adds suffixes to the private domains section of l, in
+// the suffix block maintained by info.Name.
+//
+// Each suffix is either a domain name, or a wildcard of the form
+// "*.example.com". If the section has no block for info.Name, a new
+// block with the given info is created in the correct sorted
+// position. Otherwise, the suffixes are added to the existing block,
+// along with any of info's URLs and maintainers that the block
+// doesn't already list.
+//
+// AddSuffixes refuses to add suffixes that duplicate or conflict with
+// existing entries in l. If it returns an error, l is unchanged.
+func (l *List) AddSuffixes(info MaintainerInfo, suffixes []string) (*Suffixes, error) {
+	if info.Name == "" {
+		return nil, errors.New("suffix block must have an entity name")
+	}
+	if len(suffixes) == 0 {
+		return nil, errors.New("no suffixes to add")
+	}
+
+	section := findSection(l, "PRIVATE DOMAINS")
+	if section == nil {
+		return nil, errors.New("list has no private domains section")
+	}
+
+	var added []Block
+	for _, s := range suffixes {
+		b, err := parseSuffixOrWildcard(s)
+		if err != nil {
+			return nil, err
+		}
+		added = append(added, b)
+	}
+
+	// Keep enough of the old state to undo our changes, if the new
+	// suffixes turn out to be duplicates.
+	var (
+		oldSectionBlocks = slices.Clone(section.Blocks)
+		block            = findSuffixBlock(section, info.Name)
+		oldBlocks        []Block
+		oldInfo          MaintainerInfo
+	)
+	if block == nil {
+		info.MachineEditable = true
+		block = &Suffixes{Info: info}
+		section.Blocks = slices.Insert(section.Blocks, sortedBlockIndex(section, info.Name), Block(block))
+	} else {
+		oldBlocks = slices.Clone(block.Blocks)
+		oldInfo = block.Info
+		if err := mergeMaintainerInfo(&block.Info, info); err != nil {
+			return nil, err
+		}
+	}
+	block.Blocks = append(block.Blocks, added...)
+	undo := func() {
+		section.Blocks = oldSectionBlocks
+		block.Blocks = oldBlocks
+		block.Info = oldInfo
+	}
+
+	if err := checkNewEntries(l, added); err != nil {
+		undo()
+		return nil, err
+	}
+
+	sortSuffixes(block, false)
+	rewriteSuffixesMetadata(block)
+	markChanged(l, block)
+	return block, nil
+}
+
+// checkNewEntries checks that the Suffix and Wildcard blocks in added,
+// which have already been inserted into l, don't duplicate or conflict
+// with other entries in l.
+func checkNewEntries(l *List, added []Block) error {
+	// validateSuffixUniqueness reports problems in terms of source
+	// locations, which new blocks don't have. Rephrase the errors that
+	// involve new suffixes in terms of what already exists.
+	var errs []error
+	for _, err := range validateSuffixUniqueness(l) {
+		switch v := err.(type) {
+		case ErrDuplicateSuffix:
+			newFirst, newSecond := slices.Contains(added, v.FirstDefinition), slices.Contains(added, v.Block)
+			switch {
+			case newFirst && newSecond:
+				errs = append(errs, fmt.Errorf("suffix %s is listed more than once", v.Name))
+			case newFirst:
+				errs = append(errs, fmt.Errorf("suffix %s already exists at %s", v.Name, v.Block.SrcRange().LocationString()))
+			case newSecond:
+				errs = append(errs, fmt.Errorf("suffix %s already exists at %s", v.Name, v.FirstDefinition.SrcRange().LocationString()))
+			}
+		case ErrConflictingSuffixAndException:
+			if slices.Contains(added, Block(v.Suffix)) || slices.Contains(added, Block(v.Wildcard)) {
+				errs = append(errs, fmt.Errorf("suffix %s conflicts with an exception to wildcard *.%s", v.Suffix.Domain, v.Wildcard.Domain))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// parseSuffixOrWildcard parses s as a Suffix, or as a Wildcard if it
+// starts with "*.".
+func parseSuffixOrWildcard(s string) (Block, error) {
+	if base, ok := strings.CutPrefix(s, "*."); ok {
+		d, err := domain.Parse(base)
+		if err != nil {
+			return nil, fmt.Errorf("invalid wildcard %q: %w", s, err)
+		}
+		return &Wildcard{Domain: d}, nil
+	}
+	d, err := domain.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid suffix %q: %w", s, err)
+	}
+	return &Suffix{Domain: d}, nil
+}
+
+// findSuffixBlock returns the suffix block in s maintained by entity,
+// or nil if there is no such block.
+func findSuffixBlock(s *Section, entity string) *Suffixes {
+	for _, block := range BlocksOfType[*Suffixes](s) {
+		if block.Info.Name == entity {
+			return block
+		}
+	}
+	return nil
+}
+
+// sortedBlockIndex returns the index in s.Blocks at which a new suffix
+// block maintained by entity should be inserted, to keep s sorted the
+// way sortSection would.
+func sortedBlockIndex(s *Section, entity string) int {
+	idx := 0
+	for _, group := range sectionGroups(s) {
+		if group.Key != "" && compareCommentText(entity, group.Key) < 0 {
+			return idx
+		}
+		idx += len(group.Blocks)
+	}
+	return idx
+}
+
+// sortedICANNIndex returns the index in s.Blocks at which a new
+// block for tld should be inserted, to keep the ICANN section s
+// sorted the way sortICANNSection would. The index is never in the
+// gTLD region.
+//
+// IDN ccTLD blocks are sorted by hand, so a block for an IDN tld goes
+// after the last IDN ccTLD block.
+func sortedICANNIndex(s *Section, tld domain.Label) int {
+	end := gTLDRegionStart(s)
+	if isIDN(tld) {
+		idx := end
+		for i, b := range s.Blocks[:end] {
+			if isIDNBlock(b) {
+				idx = i + 1
+			}
+		}
+		return idx
+	}
+	idx := 0
+	for _, group := range icannGroups(s.Blocks[:end]) {
+		if group.Key != "" && tld.Compare(group.TLD) < 0 {
+			return idx
+		}
+		idx += len(group.Blocks)
+	}
+	return idx
+}
+
+// mergeMaintainerInfo adds the URLs and maintainers of from that are
+// not already in into.
+func mergeMaintainerInfo(into *MaintainerInfo, from MaintainerInfo) error {
+	var (
+		urls        = slices.Clone(into.URLs)
+		maintainers = slices.Clone(into.Maintainers)
+	)
+	for _, u := range from.URLs {
+		if !slices.ContainsFunc(urls, func(v *url.URL) bool { return v.String() == u.String() }) {
+			urls = append(urls, u)
+		}
+	}
+	for _, m := range from.Maintainers {
+		if !slices.ContainsFunc(maintainers, func(n *mail.Address) bool { return strings.EqualFold(n.Address, m.Address) }) {
+			maintainers = append(maintainers, m)
+		}
+	}
+	if len(urls) == len(into.URLs) && len(maintainers) == len(into.Maintainers) {
+		return nil
+	}
+	if !into.MachineEditable {
+		return fmt.Errorf("cannot update header of suffix block %q, it is not machine-editable", into.Name)
+	}
+	into.URLs = urls
+	into.Maintainers = maintainers
+	return nil
+}
+
+// A Removal describes one entry deleted from a list by RemoveSuffixes.
+type Removal struct {
+	// Entry is the removed entry, in PSL syntax: "example.com",
+	// "*.example.com" or "!www.example.com".
+	Entry string
+	// Block is the suffix block the entry was removed from.
+	Block *Suffixes
+	// BlockRemoved is whether Block was also removed from the list,
+	// because it no longer contains any suffixes.
+	BlockRemoved bool
+}
+
+// RemoveSuffixes removes entries from l.
+//
+// Each entry is a suffix ("example.com"), a wildcard
+// ("*.example.com") or a wildcard exception ("!www.example.com"), as
+// they would appear in a PSL file. Removing a wildcard also removes
+// its exceptions. Suffix blocks that no longer contain any suffixes
+// are removed entirely, including their comments. Other comments are
+// left untouched.
+//
+// Removals are returned grouped by suffix block, in list order. If any
+// of the entries are not in l, RemoveSuffixes returns an error and
+// leaves l unchanged.
+func (l *List) RemoveSuffixes(entries []string) ([]Removal, error) {
+	// Find everything before changing anything, so that we can fail
+	// without leaving behind a partial edit.
+	var (
+		targets []entryLocation
+		errs    []error
+	)
+	for _, entry := range slices.Compact(slices.Sorted(slices.Values(entries))) {
+		t, err := findEntry(l, entry)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		targets = append(targets, t)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	// Report removals grouped by block, in list order.
+	blockOrder := BlocksOfType[*Suffixes](l)
+	slices.SortStableFunc(targets, func(a, b entryLocation) int {
+		return slices.Index(blockOrder, a.block) - slices.Index(blockOrder, b.block)
+	})
+
+	var ret []Removal
+	for _, t := range targets {
+		markChanged(l, t.child)
+		if t.isException {
+			w := t.child.(*Wildcard)
+			w.Exceptions = slices.DeleteFunc(w.Exceptions, t.exception.Equal)
+		} else {
+			t.block.Blocks = slices.DeleteFunc(t.block.Blocks, func(b Block) bool { return b == t.child })
+		}
+		ret = append(ret, Removal{Entry: t.entry, Block: t.block})
+	}
+
+	for i := range ret {
+		block := ret[i].Block
+		if len(BlocksOfType[*Suffix](block)) > 0 || len(BlocksOfType[*Wildcard](block)) > 0 {
+			continue
+		}
+		markChanged(l, block)
+		deleteBlock(l, block)
+		ret[i].BlockRemoved = true
+	}
+
+	return ret, nil
+}
+
+// RemoveEntity removes the suffix block maintained by entity from l,
+// and returns the removed block.
+func (l *List) RemoveEntity(entity string) (*Suffixes, error) {
+	var found []*Suffixes
+	for _, block := range BlocksOfType[*Suffixes](l) {
+		if block.Info.Name == entity {
+			found = append(found, block)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("no suffix block for entity %q", entity)
+	case 1:
+		markChanged(l, found[0])
+		deleteBlock(l, found[0])
+		return found[0], nil
+	default:
+		return nil, fmt.Errorf("entity %q has %d suffix blocks, refusing to guess which to remove", entity, len(found))
+	}
+}
+
+// entryLocation is the location of a PSL entry in a List.
+type entryLocation struct {
+	entry string    // the entry, in PSL syntax
+	block *Suffixes // the suffix block containing the entry
+	child Block     // the Suffix or Wildcard for the entry
+
+	// isException is whether the entry is an exception of the
+	// Wildcard child, rather than child itself.
+	isException bool
+	exception   domain.Label
+}
+
+// findEntry finds the suffix, wildcard or wildcard exception entry in
+// l.
+func findEntry(l *List, entry string) (entryLocation, error) {
+	if exc, ok := strings.CutPrefix(entry, "!"); ok {
+		d, err := domain.Parse(exc)
+		if err != nil {
+			return entryLocation{}, fmt.Errorf("invalid exception %q: %w", entry, err)
+		}
+		if d.NumLabels() < 2 {
+			return entryLocation{}, fmt.Errorf("invalid exception %q: exceptions must have at least two labels", entry)
+		}
+		label := d.Labels()[0]
+		var base domain.Name
+		for _, block := range BlocksOfType[*Suffixes](l) {
+			for _, w := range BlocksOfType[*Wildcard](block) {
+				if rest, ok := d.CutSuffix(w.Domain); !ok || len(rest) != 1 {
+					continue
+				}
+				base = w.Domain
+				if slices.ContainsFunc(w.Exceptions, label.Equal) {
+					return entryLocation{entry, block, w, true, label}, nil
+				}
+			}
+		}
+		if base.NumLabels() > 0 {
+			return entryLocation{}, fmt.Errorf("wildcard *.%s has no exception %q", base, entry)
+		}
+		return entryLocation{}, fmt.Errorf("exception %q not found", entry)
+	}
+
+	want, err := parseSuffixOrWildcard(entry)
+	if err != nil {
+		return entryLocation{}, err
+	}
+	for _, block := range BlocksOfType[*Suffixes](l) {
+		for _, child := range block.Blocks {
+			switch child.(type) {
+			case *Suffix, *Wildcard:
+				if compareSuffixAndWildcard(child, want) == 0 {
+					return entryLocation{entry: entry, block: block, child: child}, nil
+				}
+			}
+		}
+	}
+	return entryLocation{}, fmt.Errorf("suffix %q not found", entry)
+}
+
+// deleteBlock removes target from the tree rooted at root.
+func deleteBlock(root Block, target Block) {
+	del := func(blocks []Block) []Block {
+		return slices.DeleteFunc(blocks, func(b Block) bool { return b == target })
+	}
+	switch v := root.(type) {
+	case *List:
+		v.Blocks = del(v.Blocks)
+	case *Section:
+		v.Blocks = del(v.Blocks)
+	case *Group:
+		v.Blocks = del(v.Blocks)
+	case *Suffixes:
+		v.Blocks = del(v.Blocks)
+	}
+	for _, child := range root.Children() {
+		deleteBlock(child, target)
+	}
+}
+
+// CreateBlock creates a new, empty suffix block maintained by
+// info.Name in the named section of l, and returns it.
+//
+// In the private domains section, the block is inserted at its sorted
+// position. In the ICANN section, whose blocks sort by TLD, it is
+// inserted just before the gTLD region. In other sections, it is
+// appended to the end of the section. The new block's header is
+// machine-editable, and is formatted from info.
+//
+// A suffix block without suffixes is not valid, callers should
+// populate the new block with InsertSuffix.
+func (l *List) CreateBlock(section string, info MaintainerInfo) (*Suffixes, error) {
+	if info.Name == "" {
+		return nil, errors.New("suffix block must have an entity name")
+	}
+	s := findSection(l, section)
+	if s == nil {
+		return nil, fmt.Errorf("list has no section %q", section)
+	}
+	if findSuffixBlock(s, info.Name) != nil {
+		return nil, fmt.Errorf("section %q already has a suffix block for %q", section, info.Name)
+	}
+
+	info.MachineEditable = true
+	block := &Suffixes{Info: info}
+	rewriteSuffixesMetadata(block)
+	idx := len(s.Blocks)
+	switch s.Name {
+	case "ICANN DOMAINS":
+		// The block has no rules yet, so its TLD is unknown. Keep
+		// it out of the gTLD region, Clean will sort it once it
+		// has rules.
+		idx = gTLDRegionStart(s)
+	case "PRIVATE DOMAINS":
+		idx = sortedBlockIndex(s, info.Name)
+	}
+	s.Blocks = slices.Insert(s.Blocks, idx, Block(block))
+	markChanged(l, block)
+	return block, nil
+}
+
+// InsertSuffix adds entry to block, which must be part of l.
+//
+// The entry is a suffix ("example.com") or a wildcard
+// ("*.example.com"). It is inserted at its sorted position within
+// block. InsertSuffix refuses to add entries that duplicate or conflict
+// with existing entries in l.
+func (l *List) InsertSuffix(block *Suffixes, entry string) error {
+	if blockPath(l, block) == nil {
+		return errors.New("suffix block is not part of the list")
+	}
+	b, err := parseSuffixOrWildcard(entry)
+	if err != nil {
+		return err
+	}
+
+	oldBlocks := slices.Clone(block.Blocks)
+	block.Blocks = append(block.Blocks, b)
+	if err := checkNewEntries(l, []Block{b}); err != nil {
+		block.Blocks = oldBlocks
+		return err
+	}
+	sortSuffixes(block, false)
+	markChanged(l, b)
+	return nil
+}
+
+// RemoveSuffix removes the suffix ("example.com") or wildcard
+// ("*.example.com") entry from l. Removing a wildcard also removes its
+// exceptions. If the suffix block containing entry no longer has any
+// suffixes, it is removed as well.
+func (l *List) RemoveSuffix(entry string) (Removal, error) {
+	if strings.HasPrefix(entry, "!") {
+		return Removal{}, fmt.Errorf("%q is a wildcard exception, not a suffix", entry)
+	}
+	removed, err := l.RemoveSuffixes([]string{entry})
+	if err != nil {
+		return Removal{}, err
+	}
+	return removed[0], nil
+}
+
+// InsertException adds the wildcard exception entry
+// ("!www.example.com") to the matching wildcard ("*.example.com") in
+// l.
+func (l *List) InsertException(entry string) error {
+	exc, ok := strings.CutPrefix(entry, "!")
+	if !ok {
+		return fmt.Errorf("invalid exception %q: exceptions must start with \"!\"", entry)
+	}
+	d, err := domain.Parse(exc)
+	if err != nil {
+		return fmt.Errorf("invalid exception %q: %w", entry, err)
+	}
+	if d.NumLabels() < 2 {
+		return fmt.Errorf("invalid exception %q: exceptions must have at least two labels", entry)
+	}
+	label := d.Labels()[0]
+
+	var wildcard *Wildcard
+	for _, w := range BlocksOfType[*Wildcard](l) {
+		if rest, ok := d.CutSuffix(w.Domain); ok && len(rest) == 1 {
+			wildcard = w
+			break
+		}
+	}
+	if wildcard == nil {
+		return fmt.Errorf("cannot add exception %q, no matching wildcard", entry)
+	}
+	if slices.ContainsFunc(wildcard.Exceptions, label.Equal) {
+		return fmt.Errorf("wildcard *.%s already has exception %q", wildcard.Domain, entry)
+	}
+	for _, s := range BlocksOfType[*Suffix](l) {
+		if s.Domain.Equal(d) {
+			return fmt.Errorf("exception %q conflicts with suffix %s at %s", entry, s.Domain, s.LocationString())
+		}
+	}
+
+	wildcard.Exceptions = append(wildcard.Exceptions, label)
+	cleanWildcard(wildcard)
+	markChanged(l, wildcard)
+	return nil
+}
+
+// RemoveException removes the wildcard exception entry
+// ("!www.example.com") from l.
+func (l *List) RemoveException(entry string) error {
+	if !strings.HasPrefix(entry, "!") {
+		return fmt.Errorf("invalid exception %q: exceptions must start with \"!\"", entry)
+	}
+	_, err := l.RemoveSuffixes([]string{entry})
+	return err
+}
+
+// MoveSuffix moves the suffix ("example.com") or wildcard
+// ("*.example.com") entry to the suffix block to, which must be part
+// of l. Wildcards keep their exceptions. If the suffix block that
+// previously contained entry no longer has any suffixes, it is
+// removed.
+func (l *List) MoveSuffix(entry string, to *Suffixes) error {
+	if strings.HasPrefix(entry, "!") {
+		return fmt.Errorf("%q is a wildcard exception, exceptions move with their wildcard", entry)
+	}
+	if blockPath(l, to) == nil {
+		return errors.New("destination suffix block is not part of the list")
+	}
+	loc, err := findEntry(l, entry)
+	if err != nil {
+		return err
+	}
+	if loc.block == to {
+		return nil
+	}
+
+	markChanged(l, loc.block)
+	loc.block.Blocks = slices.DeleteFunc(loc.block.Blocks, func(b Block) bool { return b == loc.child })
+	to.Blocks = append(to.Blocks, loc.child)
+	sortSuffixes(to, false)
+	markChanged(l, loc.child)
+
+	if len(BlocksOfType[*Suffix](loc.block)) == 0 && len(BlocksOfType[*Wildcard](loc.block)) == 0 {
+		deleteBlock(l, loc.block)
+	}
+	return nil
+}
+
+// UpdateMaintainerInfo replaces the maintainer information of block,
+// which must be part of l, and rewrites its header comment to match.
+//
+// If the entity name changes, a block in the private domains section
+// is moved to the new name's sorted position.
+func (l *List) UpdateMaintainerInfo(block *Suffixes, info MaintainerInfo) error {
+	path := blockPath(l, block)
+	if path == nil {
+		return errors.New("suffix block is not part of the list")
+	}
+	if info.Name == "" {
+		return errors.New("suffix block must have an entity name")
+	}
+	info.MachineEditable = true
+	if info.Compare(&block.Info) == 0 {
+		return nil
+	}
+
+	var section *Section
+	for _, b := range path {
+		if s, ok := b.(*Section); ok {
+			section = s
+		}
+	}
+	if section != nil && info.Name != block.Info.Name {
+		if other := findSuffixBlock(section, info.Name); other != nil {
+			return fmt.Errorf("section %q already has a suffix block for %q at %s", section.Name, info.Name, other.LocationString())
+		}
+	}
+
+	block.Info = info
+	rewriteSuffixesMetadata(block)
+	markChanged(l, block)
+
+	if section != nil && section.Name == "PRIVATE DOMAINS" && isOwnSectionGroup(section, block) {
+		section.Blocks = slices.DeleteFunc(section.Blocks, func(b Block) bool { return b == block })
+		section.Blocks = slices.Insert(section.Blocks, sortedBlockIndex(section, info.Name), Block(block))
+	}
+	return nil
+}
+
+// isOwnSectionGroup reports whether block sorts on its own in s,
+// rather than as part of a managed Group.
+func isOwnSectionGroup(s *Section, block *Suffixes) bool {
+	return slices.Contains(s.Blocks, Block(block))
+}
+
+// findSection returns the section of l with the given name, or nil if
+// there is no such section.
+func findSection(l *List, name string) *Section {
+	for _, s := range BlocksOfType[*Section](l) {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// blockPath returns the blocks on the path from root to target,
+// inclusive, or nil if target is not in the tree rooted at root.
+func blockPath(root, target Block) []Block {
+	if root == target {
+		return []Block{root}
+	}
+	for _, child := range root.Children() {
+		if p := blockPath(child, target); p != nil {
+			return append([]Block{root}, p...)
+		}
+	}
+	return nil
+}
+
+// markChanged marks target and all its parents in l as changed, the
+// way SetBaseVersion would for an edited block.
+func markChanged(l *List, target Block) {
+	for _, b := range blockPath(l, target) {
+		b.info().isUnchanged = false
+	}
+}
@@ -0,0 +1,836 @@
+package parser
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAddSuffixes(t *testing.T) {
+	in := byteLines(
+		"// ===BEGIN ICANN DOMAINS===",
+		"",
+		"// com",
+		"com",
+		"",
+		"// ===END ICANN DOMAINS===",
+		"",
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// Bravo : https://bravo.net",
+		"// Submitted by Bravo <psl@bravo.net>",
+		"bravo.net",
+		"*.users.bravo.net",
+		"!www.users.bravo.net",
+		"",
+		"// Delta : https://delta.net",
+		"// Submitted by Delta <psl@delta.net>",
+		"delta.net",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+		"",
+	)
+
+	tests := []struct {
+		name     string
+		info     MaintainerInfo
+		suffixes []string
+		want     []byte
+		wantErr  bool
+	}{
+		{
+			name: "new_block",
+			info: MaintainerInfo{
+				Name:        "Charlie",
+				URLs:        urls("https://charlie.net"),
+				Maintainers: emails("Charlie", "psl@charlie.net"),
+			},
+			suffixes: []string{"*.charlie.net", "charlie.net"},
+			want: byteLines(
+				"// ===BEGIN ICANN DOMAINS===",
+				"",
+				"// com",
+				"com",
+				"",
+				"// ===END ICANN DOMAINS===",
+				"",
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Bravo : https://bravo.net",
+				"// Submitted by Bravo <psl@bravo.net>",
+				"bravo.net",
+				"*.users.bravo.net",
+				"!www.users.bravo.net",
+				"",
+				"// Charlie : https://charlie.net",
+				"// Submitted by Charlie <psl@charlie.net>",
+				"charlie.net",
+				"*.charlie.net",
+				"",
+				"// Delta : https://delta.net",
+				"// Submitted by Delta <psl@delta.net>",
+				"delta.net",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+		},
+		{
+			name: "new_block_at_end",
+			info: MaintainerInfo{
+				Name: "Echo",
+			},
+			suffixes: []string{"echo.net"},
+			want: byteLines(
+				"// ===BEGIN ICANN DOMAINS===",
+				"",
+				"// com",
+				"com",
+				"",
+				"// ===END ICANN DOMAINS===",
+				"",
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Bravo : https://bravo.net",
+				"// Submitted by Bravo <psl@bravo.net>",
+				"bravo.net",
+				"*.users.bravo.net",
+				"!www.users.bravo.net",
+				"",
+				"// Delta : https://delta.net",
+				"// Submitted by Delta <psl@delta.net>",
+				"delta.net",
+				"",
+				"// Echo",
+				"echo.net",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+		},
+		{
+			name: "existing_block",
+			info: MaintainerInfo{
+				Name:        "Bravo",
+				Maintainers: emails("Other Bravo", "PSL@bravo.net", "Second Bravo", "psl2@bravo.net"),
+			},
+			suffixes: []string{"app.bravo.net"},
+			want: byteLines(
+				"// ===BEGIN ICANN DOMAINS===",
+				"",
+				"// com",
+				"com",
+				"",
+				"// ===END ICANN DOMAINS===",
+				"",
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Bravo : https://bravo.net",
+				"// Submitted by Bravo <psl@bravo.net>",
+				"// Submitted by Second Bravo <psl2@bravo.net>",
+				"bravo.net",
+				"app.bravo.net",
+				"*.users.bravo.net",
+				"!www.users.bravo.net",
+				"",
+				"// Delta : https://delta.net",
+				"// Submitted by Delta <psl@delta.net>",
+				"delta.net",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+		},
+		{
+			name:     "duplicate",
+			info:     MaintainerInfo{Name: "Charlie"},
+			suffixes: []string{"charlie.net", "delta.net"},
+			wantErr:  true,
+		},
+		{
+			name:     "duplicate_icann",
+			info:     MaintainerInfo{Name: "Bravo"},
+			suffixes: []string{"com"},
+			wantErr:  true,
+		},
+		{
+			name:     "duplicate_in_input",
+			info:     MaintainerInfo{Name: "Charlie"},
+			suffixes: []string{"charlie.net", "charlie.net"},
+			wantErr:  true,
+		},
+		{
+			name:     "conflicts_with_exception",
+			info:     MaintainerInfo{Name: "Charlie"},
+			suffixes: []string{"www.users.bravo.net"},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid_suffix",
+			info:     MaintainerInfo{Name: "Charlie"},
+			suffixes: []string{"foo..net"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			l, errs := Parse(in)
+			if len(errs) > 0 {
+				t.Fatalf("Parse failed: %v", errs)
+			}
+			_, err := l.AddSuffixes(tc.info, tc.suffixes)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("AddSuffixes succeeded, want error")
+				}
+				// The list must be left untouched.
+				checkDiff(t, "list after failed AddSuffixes", string(l.MarshalPSL()), string(in))
+				return
+			}
+			if err != nil {
+				t.Fatalf("AddSuffixes failed: %v", err)
+			}
+			checkDiff(t, "list after AddSuffixes", string(l.MarshalPSL()), string(tc.want))
+
+			// The new suffixes should already be in the right place.
+			if errs := l.Clean(); len(errs) > 0 {
+				t.Fatalf("Clean failed: %v", errs)
+			}
+			checkDiff(t, "list after AddSuffixes and Clean", string(l.MarshalPSL()), string(tc.want))
+		})
+	}
+}
+
+func TestRemoveSuffixes(t *testing.T) {
+	in := byteLines(
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// Bravo : https://bravo.net",
+		"// Submitted by Bravo <psl@bravo.net>",
+		"bravo.net",
+		"*.users.bravo.net",
+		"!www.users.bravo.net",
+		"!api.users.bravo.net",
+		"",
+		"// Charlie : https://charlie.net",
+		"charlie.net",
+		"",
+		"// Delta : https://delta.net",
+		"// Submitted by Delta <psl@delta.net>",
+		"delta.net",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+		"",
+	)
+
+	tests := []struct {
+		name    string
+		entries []string
+		want    []byte
+		wantErr bool
+	}{
+		{
+			name:    "suffix",
+			entries: []string{"bravo.net"},
+			want: byteLines(
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Bravo : https://bravo.net",
+				"// Submitted by Bravo <psl@bravo.net>",
+				"*.users.bravo.net",
+				"!www.users.bravo.net",
+				"!api.users.bravo.net",
+				"",
+				"// Charlie : https://charlie.net",
+				"charlie.net",
+				"",
+				"// Delta : https://delta.net",
+				"// Submitted by Delta <psl@delta.net>",
+				"delta.net",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+		},
+		{
+			name:    "exception",
+			entries: []string{"!www.users.bravo.net"},
+			want: byteLines(
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Bravo : https://bravo.net",
+				"// Submitted by Bravo <psl@bravo.net>",
+				"bravo.net",
+				"*.users.bravo.net",
+				"!api.users.bravo.net",
+				"",
+				"// Charlie : https://charlie.net",
+				"charlie.net",
+				"",
+				"// Delta : https://delta.net",
+				"// Submitted by Delta <psl@delta.net>",
+				"delta.net",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+		},
+		{
+			name:    "wildcard_and_exceptions",
+			entries: []string{"*.users.bravo.net"},
+			want: byteLines(
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Bravo : https://bravo.net",
+				"// Submitted by Bravo <psl@bravo.net>",
+				"bravo.net",
+				"",
+				"// Charlie : https://charlie.net",
+				"charlie.net",
+				"",
+				"// Delta : https://delta.net",
+				"// Submitted by Delta <psl@delta.net>",
+				"delta.net",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+		},
+		{
+			name:    "empty_block",
+			entries: []string{"charlie.net", "delta.net"},
+			want: byteLines(
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Bravo : https://bravo.net",
+				"// Submitted by Bravo <psl@bravo.net>",
+				"bravo.net",
+				"*.users.bravo.net",
+				"!www.users.bravo.net",
+				"!api.users.bravo.net",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+		},
+		{
+			name:    "missing_suffix",
+			entries: []string{"charlie.net", "echo.net"},
+			wantErr: true,
+		},
+		{
+			name:    "missing_exception",
+			entries: []string{"!ftp.users.bravo.net"},
+			wantErr: true,
+		},
+		{
+			name:    "suffix_is_not_wildcard",
+			entries: []string{"*.charlie.net"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			l, errs := Parse(in)
+			if len(errs) > 0 {
+				t.Fatalf("Parse failed: %v", errs)
+			}
+			removed, err := l.RemoveSuffixes(tc.entries)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("RemoveSuffixes succeeded, want error")
+				}
+				checkDiff(t, "list after failed RemoveSuffixes", string(l.MarshalPSL()), string(in))
+				return
+			}
+			if err != nil {
+				t.Fatalf("RemoveSuffixes failed: %v", err)
+			}
+			if len(removed) != len(tc.entries) {
+				t.Errorf("RemoveSuffixes reported %d removals, want %d", len(removed), len(tc.entries))
+			}
+			checkDiff(t, "list after RemoveSuffixes", string(l.MarshalPSL()), string(tc.want))
+		})
+	}
+}
+
+func TestRemoveEntity(t *testing.T) {
+	in := byteLines(
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// Bravo : https://bravo.net",
+		"bravo.net",
+		"",
+		"// Charlie : https://charlie.net",
+		"charlie.net",
+		"*.charlie.net",
+		"",
+		"// Delta : https://delta.net",
+		"delta.net",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+		"",
+	)
+	want := byteLines(
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// Bravo : https://bravo.net",
+		"bravo.net",
+		"",
+		"// Delta : https://delta.net",
+		"delta.net",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+		"",
+	)
+
+	l, errs := Parse(in)
+	if len(errs) > 0 {
+		t.Fatalf("Parse failed: %v", errs)
+	}
+	if _, err := l.RemoveEntity("Echo"); err == nil {
+		t.Fatal("RemoveEntity of unknown entity succeeded, want error")
+	}
+	block, err := l.RemoveEntity("Charlie")
+	if err != nil {
+		t.Fatalf("RemoveEntity failed: %v", err)
+	}
+	if got := len(BlocksOfType[*Suffix](block)) + len(BlocksOfType[*Wildcard](block)); got != 2 {
+		t.Errorf("removed block has %d entries, want 2", got)
+	}
+	checkDiff(t, "list after RemoveEntity", string(l.MarshalPSL()), string(want))
+}
+
+func TestEditAPI(t *testing.T) {
+	in := byteLines(
+		"// ===BEGIN ICANN DOMAINS===",
+		"",
+		"// com",
+		"com",
+		"",
+		"// ===END ICANN DOMAINS===",
+		"",
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// Bravo : https://bravo.net",
+		"// Submitted by Bravo <psl@bravo.net>",
+		"bravo.net",
+		"*.users.bravo.net",
+		"!www.users.bravo.net",
+		"",
+		"// Delta : https://delta.net",
+		"// Submitted by Delta <psl@delta.net>",
+		"delta.net",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+		"",
+	)
+
+	block := func(l *List, name string) *Suffixes {
+		t.Helper()
+		b := findSuffixBlock(findSection(l, "PRIVATE DOMAINS"), name)
+		if b == nil {
+			t.Fatalf("no block for %q", name)
+		}
+		return b
+	}
+
+	tests := []struct {
+		name string
+		edit func(*List) error
+		want []byte
+		// changed lists the entity names of suffix blocks that must be
+		// marked changed after the edit. All others must be unchanged.
+		changed []string
+		wantErr bool
+	}{
+		{
+			name: "insert_suffix",
+			edit: func(l *List) error {
+				return l.InsertSuffix(block(l, "Bravo"), "app.bravo.net")
+			},
+			want: byteLines(
+				"// ===BEGIN ICANN DOMAINS===",
+				"",
+				"// com",
+				"com",
+				"",
+				"// ===END ICANN DOMAINS===",
+				"",
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Bravo : https://bravo.net",
+				"// Submitted by Bravo <psl@bravo.net>",
+				"bravo.net",
+				"app.bravo.net",
+				"*.users.bravo.net",
+				"!www.users.bravo.net",
+				"",
+				"// Delta : https://delta.net",
+				"// Submitted by Delta <psl@delta.net>",
+				"delta.net",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+			changed: []string{"Bravo"},
+		},
+		{
+			name: "insert_duplicate_suffix",
+			edit: func(l *List) error {
+				return l.InsertSuffix(block(l, "Bravo"), "delta.net")
+			},
+			wantErr: true,
+		},
+		{
+			name: "insert_suffix_conflicting_with_exception",
+			edit: func(l *List) error {
+				return l.InsertSuffix(block(l, "Delta"), "www.users.bravo.net")
+			},
+			wantErr: true,
+		},
+		{
+			name: "remove_suffix",
+			edit: func(l *List) error {
+				_, err := l.RemoveSuffix("delta.net")
+				return err
+			},
+			want: byteLines(
+				"// ===BEGIN ICANN DOMAINS===",
+				"",
+				"// com",
+				"com",
+				"",
+				"// ===END ICANN DOMAINS===",
+				"",
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Bravo : https://bravo.net",
+				"// Submitted by Bravo <psl@bravo.net>",
+				"bravo.net",
+				"*.users.bravo.net",
+				"!www.users.bravo.net",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+		},
+		{
+			name: "remove_suffix_rejects_exception",
+			edit: func(l *List) error {
+				_, err := l.RemoveSuffix("!www.users.bravo.net")
+				return err
+			},
+			wantErr: true,
+		},
+		{
+			name: "insert_exception",
+			edit: func(l *List) error {
+				return l.InsertException("!api.users.bravo.net")
+			},
+			want: byteLines(
+				"// ===BEGIN ICANN DOMAINS===",
+				"",
+				"// com",
+				"com",
+				"",
+				"// ===END ICANN DOMAINS===",
+				"",
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Bravo : https://bravo.net",
+				"// Submitted by Bravo <psl@bravo.net>",
+				"bravo.net",
+				"*.users.bravo.net",
+				"!api.users.bravo.net",
+				"!www.users.bravo.net",
+				"",
+				"// Delta : https://delta.net",
+				"// Submitted by Delta <psl@delta.net>",
+				"delta.net",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+			changed: []string{"Bravo"},
+		},
+		{
+			name: "insert_duplicate_exception",
+			edit: func(l *List) error {
+				return l.InsertException("!www.users.bravo.net")
+			},
+			wantErr: true,
+		},
+		{
+			name: "insert_exception_without_wildcard",
+			edit: func(l *List) error {
+				return l.InsertException("!www.delta.net")
+			},
+			wantErr: true,
+		},
+		{
+			name: "remove_exception",
+			edit: func(l *List) error {
+				return l.RemoveException("!www.users.bravo.net")
+			},
+			want: byteLines(
+				"// ===BEGIN ICANN DOMAINS===",
+				"",
+				"// com",
+				"com",
+				"",
+				"// ===END ICANN DOMAINS===",
+				"",
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Bravo : https://bravo.net",
+				"// Submitted by Bravo <psl@bravo.net>",
+				"bravo.net",
+				"*.users.bravo.net",
+				"",
+				"// Delta : https://delta.net",
+				"// Submitted by Delta <psl@delta.net>",
+				"delta.net",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+			changed: []string{"Bravo"},
+		},
+		{
+			name: "create_block",
+			edit: func(l *List) error {
+				b, err := l.CreateBlock("PRIVATE DOMAINS", MaintainerInfo{
+					Name:        "Charlie",
+					URLs:        urls("https://charlie.net"),
+					Maintainers: emails("Charlie", "psl@charlie.net"),
+				})
+				if err != nil {
+					return err
+				}
+				return l.InsertSuffix(b, "charlie.net")
+			},
+			want: byteLines(
+				"// ===BEGIN ICANN DOMAINS===",
+				"",
+				"// com",
+				"com",
+				"",
+				"// ===END ICANN DOMAINS===",
+				"",
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Bravo : https://bravo.net",
+				"// Submitted by Bravo <psl@bravo.net>",
+				"bravo.net",
+				"*.users.bravo.net",
+				"!www.users.bravo.net",
+				"",
+				"// Charlie : https://charlie.net",
+				"// Submitted by Charlie <psl@charlie.net>",
+				"charlie.net",
+				"",
+				"// Delta : https://delta.net",
+				"// Submitted by Delta <psl@delta.net>",
+				"delta.net",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+			changed: []string{"Charlie"},
+		},
+		{
+			name: "create_duplicate_block",
+			edit: func(l *List) error {
+				_, err := l.CreateBlock("PRIVATE DOMAINS", MaintainerInfo{Name: "Delta"})
+				return err
+			},
+			wantErr: true,
+		},
+		{
+			name: "create_block_unknown_section",
+			edit: func(l *List) error {
+				_, err := l.CreateBlock("OTHER DOMAINS", MaintainerInfo{Name: "Charlie"})
+				return err
+			},
+			wantErr: true,
+		},
+		{
+			name: "move_suffix",
+			edit: func(l *List) error {
+				return l.MoveSuffix("*.users.bravo.net", block(l, "Delta"))
+			},
+			want: byteLines(
+				"// ===BEGIN ICANN DOMAINS===",
+				"",
+				"// com",
+				"com",
+				"",
+				"// ===END ICANN DOMAINS===",
+				"",
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Bravo : https://bravo.net",
+				"// Submitted by Bravo <psl@bravo.net>",
+				"bravo.net",
+				"",
+				"// Delta : https://delta.net",
+				"// Submitted by Delta <psl@delta.net>",
+				"*.users.bravo.net",
+				"!www.users.bravo.net",
+				"delta.net",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+			changed: []string{"Bravo", "Delta"},
+		},
+		{
+			name: "move_last_suffix",
+			edit: func(l *List) error {
+				return l.MoveSuffix("delta.net", block(l, "Bravo"))
+			},
+			want: byteLines(
+				"// ===BEGIN ICANN DOMAINS===",
+				"",
+				"// com",
+				"com",
+				"",
+				"// ===END ICANN DOMAINS===",
+				"",
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Bravo : https://bravo.net",
+				"// Submitted by Bravo <psl@bravo.net>",
+				"bravo.net",
+				"*.users.bravo.net",
+				"!www.users.bravo.net",
+				"delta.net",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+			changed: []string{"Bravo"},
+		},
+		{
+			name: "update_maintainer_info",
+			edit: func(l *List) error {
+				b := block(l, "Bravo")
+				info := b.Info
+				info.Maintainers = emails("Bravo", "psl@bravo.net", "Bravo Ops", "ops@bravo.net")
+				return l.UpdateMaintainerInfo(b, info)
+			},
+			want: byteLines(
+				"// ===BEGIN ICANN DOMAINS===",
+				"",
+				"// com",
+				"com",
+				"",
+				"// ===END ICANN DOMAINS===",
+				"",
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Bravo : https://bravo.net",
+				"// Submitted by Bravo <psl@bravo.net>",
+				"// Submitted by Bravo Ops <ops@bravo.net>",
+				"bravo.net",
+				"*.users.bravo.net",
+				"!www.users.bravo.net",
+				"",
+				"// Delta : https://delta.net",
+				"// Submitted by Delta <psl@delta.net>",
+				"delta.net",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+			changed: []string{"Bravo"},
+		},
+		{
+			name: "rename_entity",
+			edit: func(l *List) error {
+				b := block(l, "Bravo")
+				info := b.Info
+				info.Name = "Echo"
+				return l.UpdateMaintainerInfo(b, info)
+			},
+			want: byteLines(
+				"// ===BEGIN ICANN DOMAINS===",
+				"",
+				"// com",
+				"com",
+				"",
+				"// ===END ICANN DOMAINS===",
+				"",
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Delta : https://delta.net",
+				"// Submitted by Delta <psl@delta.net>",
+				"delta.net",
+				"",
+				"// Echo : https://bravo.net",
+				"// Submitted by Bravo <psl@bravo.net>",
+				"bravo.net",
+				"*.users.bravo.net",
+				"!www.users.bravo.net",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+			changed: []string{"Echo"},
+		},
+		{
+			name: "rename_to_existing_entity",
+			edit: func(l *List) error {
+				b := block(l, "Bravo")
+				info := b.Info
+				info.Name = "Delta"
+				return l.UpdateMaintainerInfo(b, info)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			base, errs := Parse(in)
+			if len(errs) > 0 {
+				t.Fatalf("Parse failed: %v", errs)
+			}
+			l, _ := Parse(in)
+			l.SetBaseVersion(base, false)
+
+			err := tc.edit(l)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("edit succeeded, want error")
+				}
+				checkDiff(t, "list after failed edit", string(l.MarshalPSL()), string(in))
+				if l.Changed() {
+					t.Error("list marked changed after failed edit")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("edit failed: %v", err)
+			}
+			checkDiff(t, "list after edit", string(l.MarshalPSL()), string(tc.want))
+
+			if !l.Changed() {
+				t.Error("list not marked changed after edit")
+			}
+			for _, b := range BlocksOfType[*Suffixes](l) {
+				if got, want := b.Changed(), slices.Contains(tc.changed, b.Info.Name); got != want {
+					t.Errorf("block %q changed=%v, want %v", b.Info.Name, got, want)
+				}
+			}
+
+			// Edits should leave nothing for Clean to do.
+			if errs := l.Clean(); len(errs) > 0 {
+				t.Fatalf("Clean failed: %v", errs)
+			}
+			checkDiff(t, "list after edit and Clean", string(l.MarshalPSL()), string(tc.want))
+		})
+	}
+}
@@ -1,9 +1,82 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+
+	"github.com/publicsuffix/list/tools/internal/domain"
 )
 
+// ErrorSource returns the source location that err is about, or false
+// if err does not refer to a specific part of the source.
+func ErrorSource(err error) (SourceRange, bool) {
+	var ret SourceRange
+	switch v := err.(type) {
+	case SuppressedError:
+		return ErrorSource(v.Err)
+	case interface{ SrcRange() SourceRange }:
+		ret = v.SrcRange()
+	case ErrUnclosedSection:
+		ret = v.Section.SourceRange
+	case ErrMissingEntityName:
+		ret = v.Suffixes.SourceRange
+	case ErrMissingEntityEmail:
+		ret = v.Suffixes.SourceRange
+	case ErrInsecureEntityURL:
+		ret = v.Suffixes.SourceRange
+	case ErrEntityURLHasCredentials:
+		ret = v.Suffixes.SourceRange
+	case ErrInvalidEntityURL:
+		ret = v.Suffixes.SourceRange
+	case ErrUnrelatedEntityURL:
+		ret = v.Suffixes.SourceRange
+	case ErrInvalidEntityEmail:
+		ret = v.Suffixes.SourceRange
+	case ErrPlaceholderEntityContact:
+		ret = v.Suffixes.SourceRange
+	case ErrUnstructuredHeader:
+		ret = v.Suffixes.SourceRange
+	default:
+		return SourceRange{}, false
+	}
+	return ret, ret.NumLines() > 0
+}
+
+// A Code is the stable identifier of a kind of error, for example
+// "PSL0028 missing-txt-record". See Catalog for the documentation of
+// each code.
+type Code struct {
+	// ID is the numeric code, for example "PSL0028".
+	ID string
+	// Name is a short human-readable name, for example
+	// "missing-txt-record".
+	Name string
+}
+
+func (c Code) String() string {
+	return c.ID + " " + c.Name
+}
+
+// A CodedError is an error with a stable Code. All the error types
+// in this package are CodedErrors.
+type CodedError interface {
+	error
+	Code() Code
+}
+
+// ErrorCode returns the code of err, or false if err doesn't have
+// one.
+func ErrorCode(err error) (Code, bool) {
+	var ce CodedError
+	if errors.As(err, &ce) {
+		return ce.Code(), true
+	}
+	return Code{}, false
+}
+
 // ErrInvalidEncoding reports that the input is encoded with
 // something other than UTF-8.
 type ErrInvalidEncoding struct {
@@ -14,6 +87,8 @@ func (e ErrInvalidEncoding) Error() string {
 	return fmt.Sprintf("invalid character encoding %s", e.Encoding)
 }
 
+func (e ErrInvalidEncoding) Code() Code { return CodeInvalidEncoding }
+
 // ErrInvalidUnicode reports that a line contains characters that are
 // not valid Unicode.
 type ErrInvalidUnicode struct {
@@ -24,6 +99,8 @@ func (e ErrInvalidUnicode) Error() string {
 	return fmt.Sprintf("%s: invalid Unicode character(s)", e.SourceRange.LocationString())
 }
 
+func (e ErrInvalidUnicode) Code() Code { return CodeInvalidUnicode }
+
 // ErrSectionInSuffixBlock reports that a comment within a suffix
 // block contains a section delimiter.
 type ErrSectionInSuffixBlock struct {
@@ -34,6 +111,8 @@ func (e ErrSectionInSuffixBlock) Error() string {
 	return fmt.Sprintf("%s: section delimiter not allowed in suffix block comment", e.SourceRange.LocationString())
 }
 
+func (e ErrSectionInSuffixBlock) Code() Code { return CodeSectionInSuffixBlock }
+
 // ErrUnclosedSection reports that a file section was not closed
 // properly before EOF.
 type ErrUnclosedSection struct {
@@ -44,6 +123,8 @@ func (e ErrUnclosedSection) Error() string {
 	return fmt.Sprintf("%s: section %q is missing its closing marker", e.Section.SourceRange.LocationString(), e.Section.Name)
 }
 
+func (e ErrUnclosedSection) Code() Code { return CodeUnclosedSection }
+
 // ErrNestedSection reports that a file section is being started while
 // already within a section.
 type ErrNestedSection struct {
@@ -56,6 +137,8 @@ func (e ErrNestedSection) Error() string {
 	return fmt.Sprintf("%s: section %q is nested inside section %q (%s)", e.SourceRange.LocationString(), e.Name, e.Section.Name, e.Section.SourceRange.LocationString())
 }
 
+func (e ErrNestedSection) Code() Code { return CodeNestedSection }
+
 // ErrUnstartedSection reports that section end marker was found
 // without a corresponding start.
 type ErrUnstartedSection struct {
@@ -67,6 +150,8 @@ func (e ErrUnstartedSection) Error() string {
 	return fmt.Sprintf("%s: end marker for non-existent section %q", e.SourceRange.LocationString(), e.Name)
 }
 
+func (e ErrUnstartedSection) Code() Code { return CodeUnstartedSection }
+
 // ErrMismatchedSection reports that a file section was started
 // under one name but ended under another.
 type ErrMismatchedSection struct {
@@ -79,6 +164,8 @@ func (e ErrMismatchedSection) Error() string {
 	return fmt.Sprintf("%s: section %q (%s) closed with wrong name %q", e.SourceRange.LocationString(), e.Section.Name, e.Section.SourceRange.LocationString(), e.EndName)
 }
 
+func (e ErrMismatchedSection) Code() Code { return CodeMismatchedSection }
+
 // ErrUnknownSectionMarker reports that a line looks like a file section
 // marker (e.g. "===BEGIN ICANN DOMAINS==="), but is not one of the
 // recognized kinds of marker.
@@ -90,6 +177,8 @@ func (e ErrUnknownSectionMarker) Error() string {
 	return fmt.Sprintf("%s: unknown kind of section marker", e.SourceRange.LocationString())
 }
 
+func (e ErrUnknownSectionMarker) Code() Code { return CodeUnknownSectionMarker }
+
 // MissingEntityName reports that a block of suffixes does not have a
 // parseable owner name in its header comment.
 type ErrMissingEntityName struct {
@@ -100,6 +189,8 @@ func (e ErrMissingEntityName) Error() string {
 	return fmt.Sprintf("%s: suffix block has no owner name", e.Suffixes.SourceRange.LocationString())
 }
 
+func (e ErrMissingEntityName) Code() Code { return CodeMissingEntityName }
+
 // ErrMissingEntityEmail reports that a block of suffixes does not have a
 // parseable contact email address in its header comment.
 type ErrMissingEntityEmail struct {
@@ -110,6 +201,104 @@ func (e ErrMissingEntityEmail) Error() string {
 	return fmt.Sprintf("%s: suffix block has no contact email", e.Suffixes.SourceRange.LocationString())
 }
 
+func (e ErrMissingEntityEmail) Code() Code { return CodeMissingEntityEmail }
+
+// ErrInsecureEntityURL reports that a block of suffixes has a URL
+// in its header comment that doesn't use HTTPS.
+type ErrInsecureEntityURL struct {
+	Suffixes *Suffixes
+	URL      *url.URL
+}
+
+func (e ErrInsecureEntityURL) Error() string {
+	return fmt.Sprintf("%s: suffix block URL %s does not use https", e.Suffixes.SourceRange.LocationString(), e.URL)
+}
+
+func (e ErrInsecureEntityURL) Code() Code { return CodeInsecureEntityURL }
+
+// ErrEntityURLHasCredentials reports that a block of suffixes has a
+// URL in its header comment that contains login credentials.
+type ErrEntityURLHasCredentials struct {
+	Suffixes *Suffixes
+	URL      *url.URL
+}
+
+func (e ErrEntityURLHasCredentials) Error() string {
+	return fmt.Sprintf("%s: suffix block URL %s contains credentials", e.Suffixes.SourceRange.LocationString(), e.URL.Redacted())
+}
+
+func (e ErrEntityURLHasCredentials) Code() Code { return CodeEntityURLHasCredentials }
+
+// ErrInvalidEntityURL reports that a block of suffixes has a URL in
+// its header comment whose host is not a valid domain name.
+type ErrInvalidEntityURL struct {
+	Suffixes *Suffixes
+	URL      *url.URL
+	Err      error
+}
+
+func (e ErrInvalidEntityURL) Error() string {
+	return fmt.Sprintf("%s: suffix block URL %s has an invalid host: %v", e.Suffixes.SourceRange.LocationString(), e.URL, e.Err)
+}
+
+func (e ErrInvalidEntityURL) Code() Code { return CodeInvalidEntityURL }
+
+// ErrUnrelatedEntityURL reports that a block of suffixes has a URL
+// in its header comment that appears unrelated to the block's
+// suffixes.
+type ErrUnrelatedEntityURL struct {
+	Suffixes *Suffixes
+	URL      *url.URL
+}
+
+func (e ErrUnrelatedEntityURL) Error() string {
+	return fmt.Sprintf("%s: suffix block URL %s is not related to any of the block's suffixes", e.Suffixes.SourceRange.LocationString(), e.URL)
+}
+
+func (e ErrUnrelatedEntityURL) Code() Code { return CodeUnrelatedEntityURL }
+
+// ErrInvalidEntityEmail reports that a block of suffixes has a
+// contact email address whose domain is not valid.
+type ErrInvalidEntityEmail struct {
+	Suffixes *Suffixes
+	Email    *mail.Address
+	Err      error
+}
+
+func (e ErrInvalidEntityEmail) Error() string {
+	return fmt.Sprintf("%s: suffix block contact email %q is invalid: %v", e.Suffixes.SourceRange.LocationString(), e.Email.Address, e.Err)
+}
+
+func (e ErrInvalidEntityEmail) Code() Code { return CodeInvalidEntityEmail }
+
+// ErrPlaceholderEntityContact reports that a block of suffixes has a
+// contact URL or email address that uses a domain reserved for
+// documentation, such as example.com.
+type ErrPlaceholderEntityContact struct {
+	Suffixes *Suffixes
+	Contact  string
+}
+
+func (e ErrPlaceholderEntityContact) Error() string {
+	return fmt.Sprintf("%s: suffix block contact %q is a placeholder", e.Suffixes.SourceRange.LocationString(), e.Contact)
+}
+
+func (e ErrPlaceholderEntityContact) Code() Code { return CodePlaceholderEntityContact }
+
+// ErrUnstructuredHeader reports that the header comment of a block
+// of suffixes cannot be automatically migrated to the structured
+// header format.
+type ErrUnstructuredHeader struct {
+	Suffixes *Suffixes
+	Reason   string
+}
+
+func (e ErrUnstructuredHeader) Error() string {
+	return fmt.Sprintf("%s: suffix block header needs manual migration: %s", e.Suffixes.SourceRange.LocationString(), e.Reason)
+}
+
+func (e ErrUnstructuredHeader) Code() Code { return CodeUnstructuredHeader }
+
 // ErrInvalidSuffix reports that a suffix suffix is not a valid PSL
 // entry.
 type ErrInvalidSuffix struct {
@@ -122,6 +311,8 @@ func (e ErrInvalidSuffix) Error() string {
 	return fmt.Sprintf("%s: invalid suffix %q: %v", e.SourceRange.LocationString(), e.Suffix, e.Err)
 }
 
+func (e ErrInvalidSuffix) Code() Code { return CodeInvalidSuffix }
+
 type ErrCommentPreventsSuffixSort struct {
 	SourceRange
 }
@@ -130,6 +321,8 @@ func (e ErrCommentPreventsSuffixSort) Error() string {
 	return fmt.Sprintf("%s: comment prevents full sorting of suffixes", e.SourceRange.LocationString())
 }
 
+func (e ErrCommentPreventsSuffixSort) Code() Code { return CodeCommentPreventsSuffixSort }
+
 type ErrCommentPreventsSectionSort struct {
 	SourceRange
 }
@@ -138,6 +331,8 @@ func (e ErrCommentPreventsSectionSort) Error() string {
 	return fmt.Sprintf("%s: comment prevents full sorting of PSL section", e.SourceRange.LocationString())
 }
 
+func (e ErrCommentPreventsSectionSort) Code() Code { return CodeCommentPreventsSectionSort }
+
 type ErrDuplicateSection struct {
 	*Section
 	FirstDefinition *Section
@@ -147,6 +342,8 @@ func (e ErrDuplicateSection) Error() string {
 	return fmt.Sprintf("%s: duplicate section %q, first definition at %s", e.LocationString(), e.Name, e.FirstDefinition.LocationString())
 }
 
+func (e ErrDuplicateSection) Code() Code { return CodeDuplicateSection }
+
 type ErrUnknownSection struct {
 	*Section
 }
@@ -155,6 +352,8 @@ func (e ErrUnknownSection) Error() string {
 	return fmt.Sprintf("%s: unknown section %q, allowed sections are 'ICANN DOMAINS' and 'PRIVATE DOMAINS'", e.LocationString(), e.Name)
 }
 
+func (e ErrUnknownSection) Code() Code { return CodeUnknownSection }
+
 type ErrMissingSection struct {
 	Name string
 }
@@ -163,6 +362,8 @@ func (e ErrMissingSection) Error() string {
 	return fmt.Sprintf("missing required section %q", e.Name)
 }
 
+func (e ErrMissingSection) Code() Code { return CodeMissingSection }
+
 type ErrDuplicateSuffix struct {
 	Name            string
 	Block                 // Suffix or Wildcard
@@ -173,6 +374,8 @@ func (e ErrDuplicateSuffix) Error() string {
 	return fmt.Sprintf("%s: duplicate suffix definition for %q, first definition at %s", e.SrcRange().LocationString(), e.Name, e.FirstDefinition.SrcRange().LocationString())
 }
 
+func (e ErrDuplicateSuffix) Code() Code { return CodeDuplicateSuffix }
+
 type ErrConflictingSuffixAndException struct {
 	*Suffix
 	Wildcard *Wildcard
@@ -182,6 +385,58 @@ func (e ErrConflictingSuffixAndException) Error() string {
 	return fmt.Sprintf("%s: suffix %s conflicts with exception in wildcard at %s", e.LocationString(), e.Domain, e.Wildcard.LocationString())
 }
 
+func (e ErrConflictingSuffixAndException) Code() Code { return CodeConflictingSuffixAndException }
+
+// ErrConfusableSuffix reports that a suffix is visually confusable
+// with another suffix in the list.
+type ErrConfusableSuffix struct {
+	Block            // Suffix or Wildcard
+	Other      Block // Suffix or Wildcard
+	Codepoints []rune
+}
+
+func (e ErrConfusableSuffix) Error() string {
+	return fmt.Sprintf("%s: suffix %s is confusable with %s at %s (codepoints %s)", e.SrcRange().LocationString(), suffixName(e.Block), suffixName(e.Other), e.Other.SrcRange().LocationString(), codepointList(e.Codepoints))
+}
+
+func (e ErrConfusableSuffix) Code() Code { return CodeConfusableSuffix }
+
+// ErrMixedScriptSuffix reports that a suffix has a label that mixes
+// Unicode scripts in an unsafe way.
+type ErrMixedScriptSuffix struct {
+	Block      // Suffix or Wildcard
+	Label      domain.Label
+	Scripts    []string
+	Codepoints []rune
+}
+
+func (e ErrMixedScriptSuffix) Error() string {
+	return fmt.Sprintf("%s: suffix %s has label %q that mixes scripts %s (codepoints %s)", e.SrcRange().LocationString(), suffixName(e.Block), e.Label, strings.Join(e.Scripts, ", "), codepointList(e.Codepoints))
+}
+
+func (e ErrMixedScriptSuffix) Code() Code { return CodeMixedScriptSuffix }
+
+// suffixName returns the PSL text form of a Suffix or Wildcard block.
+func suffixName(b Block) string {
+	switch v := b.(type) {
+	case *Suffix:
+		return v.Domain.String()
+	case *Wildcard:
+		return "*." + v.Domain.String()
+	default:
+		panic(fmt.Sprintf("unexpected block type %T", b))
+	}
+}
+
+// codepointList formats rs as a list of Unicode codepoints.
+func codepointList(rs []rune) string {
+	var ret []string
+	for _, r := range rs {
+		ret = append(ret, fmt.Sprintf("%U %q", r, r))
+	}
+	return strings.Join(ret, ", ")
+}
+
 type ErrMissingTXTRecord struct {
 	Block
 }
@@ -199,6 +454,8 @@ func (e ErrMissingTXTRecord) Error() string {
 	return fmt.Sprintf("%s: suffix %s has no TXT record", e.SrcRange().LocationString(), name)
 }
 
+func (e ErrMissingTXTRecord) Code() Code { return CodeMissingTXTRecord }
+
 type ErrTXTRecordMismatch struct {
 	Block
 	PR int
@@ -215,6 +472,8 @@ func (e ErrTXTRecordMismatch) Error() string {
 	}
 }
 
+func (e ErrTXTRecordMismatch) Code() Code { return CodeTXTRecordMismatch }
+
 type ErrTXTCheckFailure struct {
 	Block
 	Err error
@@ -232,3 +491,147 @@ func (e ErrTXTCheckFailure) Error() string {
 	}
 	return fmt.Sprintf("%s: error checking suffix %s: %v", e.SrcRange().LocationString(), name, e.Err)
 }
+
+func (e ErrTXTCheckFailure) Code() Code { return CodeTXTCheckFailure }
+
+// ErrInvalidSuppression reports that a psl:allow annotation is
+// malformed.
+type ErrInvalidSuppression struct {
+	SourceRange
+	Text string
+	Err  error
+}
+
+func (e ErrInvalidSuppression) Error() string {
+	return fmt.Sprintf("%s: invalid annotation %q: %v", e.SourceRange.LocationString(), e.Text, e.Err)
+}
+
+func (e ErrInvalidSuppression) Code() Code { return CodeInvalidSuppression }
+
+// ErrNewSuppression reports that a change adds a psl:allow
+// annotation, which needs a maintainer's approval.
+type ErrNewSuppression struct {
+	Suppression
+}
+
+func (e ErrNewSuppression) Error() string {
+	return fmt.Sprintf("%s: new annotation %q needs approval from a PSL maintainer", e.SourceRange.LocationString(), e.Text)
+}
+
+func (e ErrNewSuppression) Code() Code { return CodeNewSuppression }
+
+// SuppressedError is an error that a psl:allow annotation in the
+// suffix block it refers to suppresses.
+type SuppressedError struct {
+	Err         error
+	Suppression Suppression
+}
+
+func (e SuppressedError) Error() string {
+	return fmt.Sprintf("%v (suppressed at %s: %s)", e.Err, e.Suppression.LocationString(), e.Suppression.Reason)
+}
+
+func (e SuppressedError) Unwrap() error { return e.Err }
+
+// ErrInvalidGroupMarker reports that a comment starts with a
+// malformed group marker.
+type ErrInvalidGroupMarker struct {
+	SourceRange
+	Text string
+	Err  error
+}
+
+func (e ErrInvalidGroupMarker) Error() string {
+	return fmt.Sprintf("%s: invalid group marker %q: %v", e.SourceRange.LocationString(), e.Text, e.Err)
+}
+
+func (e ErrInvalidGroupMarker) Code() Code { return CodeInvalidGroupMarker }
+
+// ErrNestedGroup reports that a group is being started while already
+// within a group.
+type ErrNestedGroup struct {
+	SourceRange
+	Owner string
+	Group *Group
+}
+
+func (e ErrNestedGroup) Error() string {
+	return fmt.Sprintf("%s: group %q is nested inside group %q (%s)", e.SourceRange.LocationString(), e.Owner, e.Group.Owner, e.Group.SourceRange.LocationString())
+}
+
+func (e ErrNestedGroup) Code() Code { return CodeNestedGroup }
+
+// ErrUnclosedGroup reports that a group was not closed before the end
+// of its section.
+type ErrUnclosedGroup struct {
+	*Group
+}
+
+func (e ErrUnclosedGroup) Error() string {
+	return fmt.Sprintf("%s: group %q is missing its end marker", e.LocationString(), e.Owner)
+}
+
+func (e ErrUnclosedGroup) Code() Code { return CodeUnclosedGroup }
+
+// ErrUnstartedGroup reports that a group end marker was found without
+// a corresponding begin marker.
+type ErrUnstartedGroup struct {
+	SourceRange
+	Owner string
+}
+
+func (e ErrUnstartedGroup) Error() string {
+	return fmt.Sprintf("%s: end marker for non-existent group %q", e.SourceRange.LocationString(), e.Owner)
+}
+
+func (e ErrUnstartedGroup) Code() Code { return CodeUnstartedGroup }
+
+// ErrMismatchedGroup reports that a group was started with one owner
+// but ended with another.
+type ErrMismatchedGroup struct {
+	SourceRange
+	EndOwner string
+	Group    *Group
+}
+
+func (e ErrMismatchedGroup) Error() string {
+	return fmt.Sprintf("%s: group %q (%s) closed with wrong owner %q", e.SourceRange.LocationString(), e.Group.Owner, e.Group.Blocks[0].SrcRange().LocationString(), e.EndOwner)
+}
+
+func (e ErrMismatchedGroup) Code() Code { return CodeMismatchedGroup }
+
+// ErrMisplacedGroupMarker reports that a group marker is somewhere
+// other than the first line of a standalone comment in a section.
+type ErrMisplacedGroupMarker struct {
+	SourceRange
+}
+
+func (e ErrMisplacedGroupMarker) Error() string {
+	return fmt.Sprintf("%s: group marker must be the first line of a comment between suffix blocks", e.SourceRange.LocationString())
+}
+
+func (e ErrMisplacedGroupMarker) Code() Code { return CodeMisplacedGroupMarker }
+
+// ErrEmptyGroup reports that a group contains no suffix blocks.
+type ErrEmptyGroup struct {
+	*Group
+}
+
+func (e ErrEmptyGroup) Error() string {
+	return fmt.Sprintf("%s: group %q has no suffix blocks", e.LocationString(), e.Owner)
+}
+
+func (e ErrEmptyGroup) Code() Code { return CodeEmptyGroup }
+
+// ErrDuplicateGroup reports that a section has several groups with
+// the same owner.
+type ErrDuplicateGroup struct {
+	*Group
+	FirstDefinition *Group
+}
+
+func (e ErrDuplicateGroup) Error() string {
+	return fmt.Sprintf("%s: duplicate group %q, first definition at %s", e.LocationString(), e.Owner, e.FirstDefinition.LocationString())
+}
+
+func (e ErrDuplicateGroup) Code() Code { return CodeDuplicateGroup }
@@ -19,6 +19,13 @@ func exemptFromContactInfo(entity string) bool {
 	return slices.Contains(missingEmail, entity)
 }
 
+// exemptFromContactValidation reports whether the block owned by
+// entity is exempt from validation of its contact URLs and email
+// addresses.
+func exemptFromContactValidation(entity string) bool {
+	return slices.Contains(invalidContactInfo, entity)
+}
+
 // exemptFromSorting reports whether the block owned by entity is
 // exempt from the sorting requirement that normally applies in the
 // private domains section.
@@ -35,6 +42,12 @@ func exemptFromTXT(domain domain.Name) bool {
 	return false
 }
 
+// exemptFromConfusables reports whether the given domain name is
+// exempt from the confusable and mixed-script checks.
+func exemptFromConfusables(domain domain.Name) bool {
+	return confusableOK.Has(domain.String())
+}
+
 // adjustTXTPR returns a PR number to use instead of prNum for
 // checking, or prNum unchanged if no adjustment is needed.
 func adjustTXTPR(prNum int) int {
@@ -76,6 +89,26 @@ var missingEmail = []string{
 // allowed to be in the wrong sort order.
 var incorrectSort = []string{}
 
+// confusableOK are the domains that are exempt from the confusable
+// and mixed-script checks.
+var confusableOK = mapset.New(
+	// .bg registers every single-character second-level domain.
+	"1.bg",
+	"l.bg",
+
+	// IDN ccTLDs that are delegated in several spellings, with
+	// Arabic and Persian variants of the same letters.
+	"ایران.ir",
+	"ايران.ir",
+	"السعودية",
+	"السعودیة",
+	"السعودیۃ",
+	"ايران",
+	"ایران",
+	"پاكستان",
+	"پاکستان",
+)
+
 // missingTXT are the domains that are exempt from the _psl TXT record
 // requirement.
 var missingTXT = mapset.New(
@@ -820,3 +853,72 @@ var txtAcceptPRs = map[string]int{
 	// around as a "valid" entry with an exception.
 	"priv.at": 1851,
 }
+
+// invalidContactInfo are source code blocks in the private domains
+// section whose contact URLs or email addresses fail validation, but
+// which predate the validation. Most of these have a plain http://
+// website.
+var invalidContactInfo = []string{
+	"alboto.ca",
+	"Alces Software Ltd",
+	"Asociación Amigos de la Informática \"Euskalamiga\"",
+	"ASUSTOR Inc.",
+	"BinaryLane",
+	"Bitbucket",
+	"Blatech",
+	"CDN77.com",
+	"Cloud DNS Ltd",
+	"co.ca",
+	"COSIMO GmbH",
+	"Craynic, s.r.o.",
+	"Dansk.net",
+	"Definima",
+	"DreamHost",
+	"DuckDNS",
+	"dy.fi",
+	"DynDNS.com",
+	"Evennode",
+	"Fancy Bits, LLC",
+	"Fastly Inc.",
+	"Freebox",
+	"GignoSystemJapan",
+	"GoIP DNS Services",
+	"I-O DATA DEVICE, INC.",
+	"i-registry s.r.o.",
+	"Ici la Lune",
+	"iDOT Services Limited",
+	"info.cx",
+	"Interlegis",
+	"JS.ORG",
+	"KaasHosting",
+	"KnightPoint Systems, LLC",
+	"LiquidNet Ltd",
+	"Michau Enterprises Limited",
+	"Microsoft Corporation",
+	"minion.systems",
+	"OpenCraft GmbH",
+	"OwnProvider GmbH",
+	"OX",
+	"Peplink | Pepwave",
+	"Positive Codes Technology Company",
+	"priv.at",
+	"Qutheory LLC",
+	"Radix FZC",
+	"Rochester Institute of Technology",
+	"Rusnames Limited",
+	"Scry Security",
+	"Service Online LLC",
+	"Shopblocks",
+	"SinaAppEngine",
+	"Studenten Net Twente",
+	"Sub 6 Limited",
+	"TAIFUN Software AG",
+	"TownNews.com",
+	"TuxFamily",
+	"UDR Limited",
+	"University of Bielsko-Biala regional domain",
+	"US REGISTRY LLC",
+	"VeryPositive SIA",
+	"XnBay Technology",
+	"ZaNiC",
+}
@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/publicsuffix/list/tools/internal/domain"
+)
+
+// An Explanation traces how PublicSuffix and RegisteredDomain arrive
+// at their result for a domain name.
+type Explanation struct {
+	// Name is the explained domain name.
+	Name domain.Name
+	// Rules are the rules that match Name, in the order they appear
+	// in the list, with the outcome of each. A wildcard whose
+	// exception matches Name is listed before the exception.
+	Rules []ExplainedRule
+	// ImplicitRule is whether no rule matched Name, so that the
+	// public suffix comes from the PSL algorithm's implicit "*" rule.
+	ImplicitRule bool
+	// PublicSuffix is the public suffix of Name.
+	PublicSuffix domain.Name
+	// RegisteredDomain is the registrable domain of Name, or the zero
+	// value if Name is itself a public suffix.
+	RegisteredDomain domain.Name
+}
+
+// IsPublicSuffix reports whether the explained name is itself a public
+// suffix.
+func (e *Explanation) IsPublicSuffix() bool {
+	return e.RegisteredDomain.NumLabels() == 0
+}
+
+// An ExplainedRule is a rule that matches a domain name, and whether
+// it determines the name's public suffix.
+type ExplainedRule struct {
+	RuleMatch
+	// Prevailing is whether the rule determines the public suffix.
+	// Several rules can prevail if they produce the same public
+	// suffix.
+	Prevailing bool
+	// Reason is a human-readable explanation of why the rule
+	// prevails, or why it doesn't.
+	Reason string
+}
+
+// Explain returns an explanation of the public suffix and registrable
+// domain of n, with every matching rule and the reason it prevails or
+// loses.
+//
+// The PSL algorithm picks a matching wildcard exception over all
+// other rules. Otherwise, it picks the rule that produces the longest
+// public suffix. If no rule matches, the implicit "*" rule makes the
+// TLD of n its public suffix.
+func (l *List) Explain(n domain.Name) *Explanation {
+	ret := &Explanation{
+		Name:         n,
+		PublicSuffix: l.PublicSuffix(n),
+	}
+	ret.RegisteredDomain, _ = l.RegisteredDomain(n)
+
+	matches := l.MatchingRules(n)
+	ret.ImplicitRule = len(matches) == 0
+
+	// Find a rule that determines the result, to compare the others
+	// against. Its public suffix is ret.PublicSuffix by definition.
+	hasException := slices.ContainsFunc(matches, func(m RuleMatch) bool { return m.IsException })
+	winner := slices.IndexFunc(matches, func(m RuleMatch) bool {
+		return m.IsException == hasException && m.PublicSuffix.Equal(ret.PublicSuffix)
+	})
+
+	for _, m := range matches {
+		if m.IsException {
+			// MatchingRules only reports the exception, but the
+			// wildcard itself also matches n and loses.
+			w := m.Rule.(*Wildcard)
+			rest, _ := n.CutSuffix(w.Domain)
+			wm := m
+			wm.Text = "*." + w.Domain.String()
+			wm.IsException = false
+			wm.PublicSuffix = w.Domain.MustAddPrefix(rest[len(rest)-1])
+			ret.Rules = append(ret.Rules, ExplainedRule{
+				RuleMatch: wm,
+				Reason:    fmt.Sprintf("the wildcard exception %s takes priority over all other rules", m.Text),
+			})
+		}
+
+		r := ExplainedRule{RuleMatch: m}
+		switch {
+		case m.IsException == hasException && m.PublicSuffix.Equal(ret.PublicSuffix):
+			r.Prevailing = true
+			if m.IsException {
+				r.Reason = "wildcard exceptions take priority over all other rules"
+			} else {
+				r.Reason = "longest matching public suffix"
+			}
+		case hasException && !m.IsException:
+			r.Reason = fmt.Sprintf("the wildcard exception %s takes priority over all other rules", matches[winner].Text)
+		default:
+			r.Reason = fmt.Sprintf("public suffix %s is shorter than %s from %s", m.PublicSuffix, ret.PublicSuffix, matches[winner].Text)
+		}
+		ret.Rules = append(ret.Rules, r)
+	}
+	return ret
+}
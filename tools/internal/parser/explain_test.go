@@ -0,0 +1,107 @@
+package parser
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	l, errs := Parse(byteLines(
+		"// ===BEGIN ICANN DOMAINS===",
+		"",
+		"// ck",
+		"*.ck",
+		"!www.ck",
+		"",
+		"// com",
+		"com",
+		"",
+		"// ===END ICANN DOMAINS===",
+		"",
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// Bravo : https://bravo.com",
+		"bravo.com",
+		"*.users.bravo.com",
+		"",
+		"// Foo : https://foo.www.ck",
+		"foo.www.ck",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+	))
+	if len(errs) > 0 {
+		t.Fatalf("Parse failed: %v", errs)
+	}
+
+	tests := []struct {
+		host             string
+		wantRules        []string
+		wantImplicit     bool
+		wantSuffix       string
+		wantRegistered   string
+		wantPublicSuffix bool
+	}{
+		{
+			host: "a.b.users.bravo.com",
+			wantRules: []string{
+				"com (line 8) lost: public suffix com is shorter than b.users.bravo.com from *.users.bravo.com",
+				"bravo.com (line 15) lost: public suffix bravo.com is shorter than b.users.bravo.com from *.users.bravo.com",
+				"*.users.bravo.com (line 16) prevails: longest matching public suffix",
+			},
+			wantSuffix:     "b.users.bravo.com",
+			wantRegistered: "a.b.users.bravo.com",
+		},
+		{
+			host: "a.foo.www.ck",
+			wantRules: []string{
+				"*.ck (line 4) lost: the wildcard exception !www.ck takes priority over all other rules",
+				"!www.ck (line 4) prevails: wildcard exceptions take priority over all other rules",
+				"foo.www.ck (line 19) lost: the wildcard exception !www.ck takes priority over all other rules",
+			},
+			wantSuffix:     "ck",
+			wantRegistered: "www.ck",
+		},
+		{
+			host: "bravo.com",
+			wantRules: []string{
+				"com (line 8) lost: public suffix com is shorter than bravo.com from bravo.com",
+				"bravo.com (line 15) prevails: longest matching public suffix",
+			},
+			wantSuffix:       "bravo.com",
+			wantPublicSuffix: true,
+		},
+		{
+			host:           "www.example.org",
+			wantImplicit:   true,
+			wantSuffix:     "org",
+			wantRegistered: "example.org",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.host, func(t *testing.T) {
+			e := l.Explain(mustParseDomain(tc.host))
+			var gotRules []string
+			for _, r := range e.Rules {
+				verdict := "lost"
+				if r.Prevailing {
+					verdict = "prevails"
+				}
+				gotRules = append(gotRules, fmt.Sprintf("%s (%s) %s: %s", r.Text, r.Rule.SrcRange().LocationString(), verdict, r.Reason))
+			}
+			checkDiff(t, "explained rules", gotRules, tc.wantRules)
+			if e.ImplicitRule != tc.wantImplicit {
+				t.Errorf("ImplicitRule = %v, want %v", e.ImplicitRule, tc.wantImplicit)
+			}
+			if got := e.PublicSuffix.String(); got != tc.wantSuffix {
+				t.Errorf("PublicSuffix = %q, want %q", got, tc.wantSuffix)
+			}
+			if got := e.RegisteredDomain.String(); got != tc.wantRegistered {
+				t.Errorf("RegisteredDomain = %q, want %q", got, tc.wantRegistered)
+			}
+			if got := e.IsPublicSuffix(); got != tc.wantPublicSuffix {
+				t.Errorf("IsPublicSuffix() = %v, want %v", got, tc.wantPublicSuffix)
+			}
+		})
+	}
+}
@@ -6,12 +6,14 @@ import (
 	"net/mail"
 	"net/url"
 	"slices"
+	"time"
 
 	"github.com/publicsuffix/list/tools/internal/domain"
 )
 
 // A Block is a parsed chunk of a PSL file. Each block is one of the
-// concrete types Comment, Section, Suffixes, Suffix, or Wildcard.
+// concrete types Comment, Section, Group, Suffixes, Suffix, or
+// Wildcard.
 type Block interface {
 	// SrcRange returns the block's SourceRange.
 	SrcRange() SourceRange
@@ -54,6 +56,10 @@ type blockInfo struct {
 	// all blocks. A different base of comparison can be set with
 	// List.Diff.
 	isUnchanged bool
+
+	// overlay is the name of the Overlay that added the block to its
+	// List, or empty if the block is part of the List's own source.
+	overlay string
 }
 
 func (b blockInfo) SrcRange() SourceRange {
@@ -75,6 +81,10 @@ type List struct {
 	// Blocks are the top-level elements of the list, in the order
 	// they appear.
 	Blocks []Block
+
+	// src is the original source text of the list, if it was parsed
+	// with ParseLossless.
+	src *rawSource
 }
 
 func (l *List) Children() []Block { return l.Blocks }
@@ -171,6 +181,88 @@ func (l *List) RegisteredDomain(d domain.Name) (domain.Name, bool) {
 	return suf.MustAddPrefix(next[len(next)-1]), true
 }
 
+// RuleMatch is a rule of a List that matches a domain name.
+type RuleMatch struct {
+	// Rule is the matching *Suffix or *Wildcard.
+	Rule Block
+	// Text is the matching rule in PSL syntax, for example
+	// "foo.com", "*.foo.com" or "!bar.foo.com".
+	Text string
+	// IsException is whether the domain name matches one of the
+	// exceptions of a wildcard Rule.
+	IsException bool
+	// PublicSuffix is the public suffix of the domain name according
+	// to Rule taken in isolation.
+	PublicSuffix domain.Name
+	// Section and Suffixes are the section and suffix block that
+	// contain Rule, or nil if Rule is outside of any section or
+	// suffix block.
+	Section  *Section
+	Suffixes *Suffixes
+	// Overlay is the name of the Overlay that added Rule to the
+	// list, or empty if Rule comes from the list itself.
+	Overlay string
+}
+
+// MatchingRules returns all the rules of l that match n, in the order
+// they appear in l.
+//
+// Of the returned rules, PublicSuffix picks a matching wildcard
+// exception if there is one, and the rule with the longest public
+// suffix otherwise. If no rules match, PublicSuffix uses the implicit
+// "*" rule, which MatchingRules does not return.
+func (l *List) MatchingRules(n domain.Name) []RuleMatch {
+	var (
+		ret      []RuleMatch
+		section  *Section
+		suffixes *Suffixes
+	)
+	var rec func(Block)
+	rec = func(b Block) {
+		switch v := b.(type) {
+		case *Section:
+			section = v
+			defer func() { section = nil }()
+		case *Suffixes:
+			suffixes = v
+			defer func() { suffixes = nil }()
+		case *Suffix:
+			if suf, ok := v.PublicSuffix(n); ok {
+				ret = append(ret, RuleMatch{
+					Rule:         v,
+					Text:         v.Domain.String(),
+					PublicSuffix: suf,
+					Section:      section,
+					Suffixes:     suffixes,
+					Overlay:      v.overlay,
+				})
+			}
+		case *Wildcard:
+			if suf, isException, ok := v.PublicSuffix(n); ok {
+				text := "*." + v.Domain.String()
+				if isException {
+					rest, _ := n.CutSuffix(v.Domain)
+					text = "!" + rest[len(rest)-1].String() + "." + v.Domain.String()
+				}
+				ret = append(ret, RuleMatch{
+					Rule:         v,
+					Text:         text,
+					IsException:  isException,
+					PublicSuffix: suf,
+					Section:      section,
+					Suffixes:     suffixes,
+					Overlay:      v.overlay,
+				})
+			}
+		}
+		for _, child := range b.Children() {
+			rec(child)
+		}
+	}
+	rec(l)
+	return ret
+}
+
 // Comment is a comment block, consisting of one or more contiguous
 // lines of commented text.
 type Comment struct {
@@ -196,6 +288,38 @@ type Section struct {
 
 func (s *Section) Children() []Block { return s.Blocks }
 
+// Group is a managed group: a run of blocks in a section that belong
+// to a single owner, who maintains them as a unit. Groups are
+// delimited by marker comments:
+//
+//	// psl:begin-group owner="Example" sort-key="Example"
+//	// Example Ltd : https://example.com
+//
+//	// Example Cloud
+//	// Submitted by Example Ltd <psl@example.com>
+//	cloud.example.com
+//
+//	// psl:end-group owner="Example"
+//
+// When sorting a section, a group is treated as a single unit that
+// sorts under SortKey. The blocks within a group are left in the
+// order the owner chose.
+type Group struct {
+	blockInfo
+
+	// Owner is the name of the group's owner.
+	Owner string
+	// SortKey is the name that the group sorts under, which is Owner
+	// unless the begin marker specifies a sort key.
+	SortKey string
+	// Blocks are the child blocks of the group. The first and last
+	// blocks are the Comments that contain the group's begin and end
+	// markers.
+	Blocks []Block
+}
+
+func (g *Group) Children() []Block { return g.Blocks }
+
 // Suffixes is a list of PSL domain suffixes with optional additional
 // metadata.
 //
@@ -252,11 +376,26 @@ type MaintainerInfo struct {
 	// information.
 	Maintainers []*mail.Address
 
+	// Requested is the date on which the suffix block was requested,
+	// or the zero time if the header doesn't record it.
+	Requested time.Time
+
+	// PR is the number of the GitHub pull request that added or last
+	// changed the suffix block, or 0 if the header doesn't record it.
+	PR int
+
+	// Notes are free-form remarks about the suffix block, taken from
+	// the header's "Note:" lines.
+	Notes []string
+
 	// Other is some unstructured additional notes. They may contain
 	// anything, including some of the above information that wasn't
 	// in a known parseable form.
 	Other []string
 
+	// Suppressions are the psl:allow annotations in the header.
+	Suppressions []Suppression
+
 	// MachineEditable is whether this information can be
 	// machine-edited and written back out without loss of
 	// information. The exact formatting of the information may
@@ -287,10 +426,29 @@ func (m *MaintainerInfo) Compare(n *MaintainerInfo) int {
 		}
 	}
 
+	if r := m.Requested.Compare(n.Requested); r != 0 {
+		return r
+	}
+	if r := cmp.Compare(m.PR, n.PR); r != 0 {
+		return r
+	}
+	if r := slices.Compare(m.Notes, n.Notes); r != 0 {
+		return r
+	}
+
 	if r := slices.Compare(m.Other, n.Other); r != 0 {
 		return r
 	}
 
+	if r := cmp.Compare(len(m.Suppressions), len(n.Suppressions)); r != 0 {
+		return r
+	}
+	for i := range m.Suppressions {
+		if r := cmp.Compare(m.Suppressions[i].Text, n.Suppressions[i].Text); r != 0 {
+			return r
+		}
+	}
+
 	if m.MachineEditable == n.MachineEditable {
 		return 0
 	} else if !m.MachineEditable {
@@ -302,7 +460,7 @@ func (m *MaintainerInfo) Compare(n *MaintainerInfo) int {
 
 // HasInfo reports whether m has any maintainer information at all.
 func (m MaintainerInfo) HasInfo() bool {
-	return m.Name != "" || len(m.URLs) > 0 || len(m.Maintainers) > 0 || len(m.Other) > 0
+	return m.Name != "" || len(m.URLs) > 0 || len(m.Maintainers) > 0 || !m.Requested.IsZero() || m.PR != 0 || len(m.Notes) > 0 || len(m.Other) > 0 || len(m.Suppressions) > 0
 }
 
 // Suffix is one public suffix, represented in the standard domain
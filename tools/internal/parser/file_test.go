@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/publicsuffix/list/tools/internal/domain"
@@ -86,6 +87,57 @@ func TestPublicSuffix(t *testing.T) {
 	}
 }
 
+func TestMatchingRules(t *testing.T) {
+	block := suffixes(1, 5, info("Example", nil, nil, nil, true),
+		suffix(1, "com"),
+		suffix(2, "example.com"),
+		wildcard(3, 4, "users.example.com", "www"),
+	)
+	sec := section(1, 5, "PRIVATE DOMAINS", block)
+	lst := list(sec)
+
+	type match struct {
+		Text         string
+		IsException  bool
+		PublicSuffix string
+		Line         int
+	}
+	tests := []struct {
+		in   string
+		want []match
+	}{
+		{"other.net", nil},
+		{"com", []match{
+			{"com", false, "com", 1},
+		}},
+		{"foo.example.com", []match{
+			{"com", false, "com", 1},
+			{"example.com", false, "example.com", 2},
+		}},
+		{"foo.users.example.com", []match{
+			{"com", false, "com", 1},
+			{"example.com", false, "example.com", 2},
+			{"*.users.example.com", false, "foo.users.example.com", 3},
+		}},
+		{"a.www.users.example.com", []match{
+			{"com", false, "com", 1},
+			{"example.com", false, "example.com", 2},
+			{"!www.users.example.com", true, "users.example.com", 3},
+		}},
+	}
+
+	for _, tc := range tests {
+		var got []match
+		for _, m := range lst.MatchingRules(mustParseDomain(tc.in)) {
+			if m.Section != sec || m.Suffixes != block {
+				t.Errorf("MatchingRules(%q) rule %q has wrong parent blocks", tc.in, m.Text)
+			}
+			got = append(got, match{m.Text, m.IsException, m.PublicSuffix.String(), m.Rule.SrcRange().FirstLine})
+		}
+		checkDiff(t, fmt.Sprintf("MatchingRules(%q)", tc.in), got, tc.want)
+	}
+}
+
 func mustParseDomain(s string) domain.Name {
 	d, err := domain.Parse(s)
 	if err != nil {
@@ -93,3 +145,11 @@ func mustParseDomain(s string) domain.Name {
 	}
 	return d
 }
+
+func mustParseLabel(s string) domain.Label {
+	l, err := domain.ParseLabel(s)
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
@@ -0,0 +1,239 @@
+package parser
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/publicsuffix/list/tools/internal/domain"
+)
+
+// A Filter selects a subset of a List. Each non-empty field narrows
+// the selection, the zero Filter selects the entire list.
+type Filter struct {
+	// Sections are the names of the sections to keep, for example
+	// "ICANN DOMAINS".
+	Sections []string
+	// TLDs are the top-level domains whose rules to keep.
+	TLDs []domain.Label
+	// Entities are the entity names of the suffix blocks to keep.
+	Entities []string
+	// ExcludeEntities are the entity names of suffix blocks to drop.
+	ExcludeEntities []string
+	// WildcardsOnly is whether to keep only wildcard rules.
+	WildcardsOnly bool
+}
+
+// selectsRules reports whether f keeps only some of the rules of a
+// suffix block.
+func (f Filter) selectsRules() bool {
+	return len(f.TLDs) > 0 || f.WildcardsOnly
+}
+
+// selectsBlocks reports whether f keeps only some of the suffix
+// blocks of a section, other than by excluding entities.
+func (f Filter) selectsBlocks() bool {
+	return f.selectsRules() || len(f.Entities) > 0
+}
+
+// keepSection reports whether f selects section s.
+func (f Filter) keepSection(s *Section) bool {
+	return len(f.Sections) == 0 || slices.Contains(f.Sections, s.Name)
+}
+
+// keepEntity reports whether f selects the suffix block of the named
+// entity. Entity names are matched case-insensitively.
+func (f Filter) keepEntity(name string) bool {
+	if f.excludesEntity(name) {
+		return false
+	}
+	return len(f.Entities) == 0 || f.selectsEntity(name)
+}
+
+// selectsEntity reports whether name is one of f.Entities.
+func (f Filter) selectsEntity(name string) bool {
+	return slices.ContainsFunc(f.Entities, func(n string) bool { return strings.EqualFold(n, name) })
+}
+
+// excludesEntity reports whether name is one of f.ExcludeEntities.
+func (f Filter) excludesEntity(name string) bool {
+	return slices.ContainsFunc(f.ExcludeEntities, func(n string) bool { return strings.EqualFold(n, name) })
+}
+
+// keepRule reports whether f selects the suffix or wildcard rule for
+// d.
+func (f Filter) keepRule(d domain.Name, isWildcard bool) bool {
+	if f.WildcardsOnly && !isWildcard {
+		return false
+	}
+	if len(f.TLDs) == 0 {
+		return true
+	}
+	labels := d.Labels()
+	return slices.ContainsFunc(f.TLDs, labels[len(labels)-1].Equal)
+}
+
+// Filter returns a new List with the parts of l selected by f.
+//
+// Top-level comments, such as the license header, are always kept,
+// as are the markers of the selected sections. Suffix blocks are kept
+// with all their header comments if any of their rules are selected.
+//
+// Comments between suffix blocks often describe a group of blocks,
+// and are dropped if f selects specific blocks by entity, TLD or
+// kind of rule. Comments between the rules of a suffix block are kept
+// if any of the rules that follow them (up to the next comment) are
+// kept, and comments at the end of a suffix block are kept unless f
+// selects specific rules.
+//
+// The returned List shares Comment, Suffix and Wildcard blocks with
+// l, and must not be edited while l is in use.
+func (l *List) Filter(f Filter) *List {
+	ret := &List{blockInfo: l.blockInfo}
+	for _, b := range l.Blocks {
+		switch v := b.(type) {
+		case *Section:
+			if f.keepSection(v) {
+				ret.Blocks = append(ret.Blocks, f.filterSection(v))
+			}
+		case *Suffixes:
+			if s := f.filterSuffixes(v); s != nil {
+				ret.Blocks = append(ret.Blocks, s)
+			}
+		default:
+			ret.Blocks = append(ret.Blocks, b)
+		}
+	}
+	return ret
+}
+
+func (f Filter) filterSection(s *Section) *Section {
+	ret := &Section{blockInfo: s.blockInfo, Name: s.Name}
+	for _, b := range s.Blocks {
+		switch v := b.(type) {
+		case *Group:
+			if g := f.filterGroup(v); g != nil {
+				ret.Blocks = append(ret.Blocks, g)
+			}
+		case *Suffixes:
+			if s := f.filterSuffixes(v); s != nil {
+				ret.Blocks = append(ret.Blocks, s)
+			}
+		default:
+			if !f.selectsBlocks() {
+				ret.Blocks = append(ret.Blocks, b)
+			}
+		}
+	}
+	return ret
+}
+
+// filterGroup returns the parts of g selected by f, or nil if none of
+// its suffix blocks are selected. The group's begin and end markers
+// are kept, so that the result is still a well-formed group.
+//
+// The group's owner is matched like an entity name: selecting it
+// selects all of the group's suffix blocks, and excluding it drops the
+// whole group.
+func (f Filter) filterGroup(g *Group) *Group {
+	if f.excludesEntity(g.Owner) {
+		return nil
+	}
+	if f.selectsEntity(g.Owner) {
+		f.Entities = nil
+	}
+
+	ret := &Group{blockInfo: g.blockInfo, Owner: g.Owner, SortKey: g.SortKey}
+	found := false
+	for i, b := range g.Blocks {
+		switch v := b.(type) {
+		case *Suffixes:
+			if s := f.filterSuffixes(v); s != nil {
+				ret.Blocks = append(ret.Blocks, s)
+				found = true
+			}
+		default:
+			if i == 0 || i == len(g.Blocks)-1 || !f.selectsBlocks() {
+				ret.Blocks = append(ret.Blocks, b)
+			}
+		}
+	}
+	if !found {
+		return nil
+	}
+	return ret
+}
+
+// filterSuffixes returns the parts of s selected by f, or nil if none
+// of its rules are selected.
+func (f Filter) filterSuffixes(s *Suffixes) *Suffixes {
+	if !f.keepEntity(s.Info.Name) {
+		return nil
+	}
+
+	// The comments at the start of the block are its header, and
+	// are always kept.
+	header := 0
+	for header < len(s.Blocks) {
+		if _, ok := s.Blocks[header].(*Comment); !ok {
+			break
+		}
+		header++
+	}
+	rules := filterCommentGroups(s.Blocks[header:], !f.selectsRules(), func(b Block) Block {
+		switch v := b.(type) {
+		case *Suffix:
+			if f.keepRule(v.Domain, false) {
+				return v
+			}
+		case *Wildcard:
+			if f.keepRule(v.Domain, true) {
+				return v
+			}
+		}
+		return nil
+	})
+	if len(rules) == 0 {
+		return nil
+	}
+
+	ret := &Suffixes{
+		blockInfo: s.blockInfo,
+		Info:      s.Info,
+	}
+	ret.Blocks = append(slices.Clone(s.Blocks[:header]), rules...)
+	return ret
+}
+
+// filterCommentGroups returns the blocks for which keep returns a
+// non-nil replacement, along with the comments that precede them.
+//
+// blocks is split into groups, each made of one or more comments and
+// the blocks that follow them. The comments of a group are kept if
+// any of its other blocks are kept. Comments at the end of blocks,
+// which have no following blocks, are kept if keepTrailing is true.
+func filterCommentGroups(blocks []Block, keepTrailing bool, keep func(Block) Block) []Block {
+	var (
+		ret      []Block
+		comments []Block // pending comments of the current group
+		inGroup  bool    // whether the current group has non-comments
+	)
+	for _, b := range blocks {
+		if _, ok := b.(*Comment); ok {
+			if inGroup {
+				comments, inGroup = nil, false
+			}
+			comments = append(comments, b)
+			continue
+		}
+		inGroup = true
+		if kept := keep(b); kept != nil {
+			ret = append(ret, comments...)
+			ret = append(ret, kept)
+			comments = nil
+		}
+	}
+	if !inGroup && keepTrailing {
+		ret = append(ret, comments...)
+	}
+	return ret
+}
@@ -0,0 +1,296 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/publicsuffix/list/tools/internal/domain"
+)
+
+func TestFilter(t *testing.T) {
+	in := byteLines(
+		"// License header",
+		"",
+		"// ===BEGIN ICANN DOMAINS===",
+		"",
+		"// ck : https://www.iana.org/domains/root/db/ck.html",
+		"*.ck",
+		"!www.ck",
+		"",
+		"// jp : https://www.iana.org/domains/root/db/jp.html",
+		"jp",
+		"co.jp",
+		"// jp geographic type names",
+		"*.kawasaki.jp",
+		"aichi.jp",
+		"",
+		"// newGTLDs",
+		"",
+		"// aaa : https://www.iana.org/domains/root/db/aaa.html",
+		"aaa",
+		"",
+		"// ===END ICANN DOMAINS===",
+		"",
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// Bravo : https://bravo.net",
+		"// Submitted by Bravo <psl@bravo.net>",
+		"bravo.net",
+		"*.users.bravo.net",
+		"",
+		"// Charlie : https://charlie.jp",
+		"// Submitted by Charlie <psl@charlie.jp>",
+		"charlie.jp",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+		"",
+	)
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []byte
+	}{
+		{
+			name:   "everything",
+			filter: Filter{},
+			want:   in,
+		},
+		{
+			name:   "section",
+			filter: Filter{Sections: []string{"PRIVATE DOMAINS"}},
+			want: byteLines(
+				"// License header",
+				"",
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Bravo : https://bravo.net",
+				"// Submitted by Bravo <psl@bravo.net>",
+				"bravo.net",
+				"*.users.bravo.net",
+				"",
+				"// Charlie : https://charlie.jp",
+				"// Submitted by Charlie <psl@charlie.jp>",
+				"charlie.jp",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+		},
+		{
+			name:   "tld",
+			filter: Filter{TLDs: []domain.Label{mustParseLabel("jp")}},
+			want: byteLines(
+				"// License header",
+				"",
+				"// ===BEGIN ICANN DOMAINS===",
+				"",
+				"// jp : https://www.iana.org/domains/root/db/jp.html",
+				"jp",
+				"co.jp",
+				"// jp geographic type names",
+				"*.kawasaki.jp",
+				"aichi.jp",
+				"",
+				"// ===END ICANN DOMAINS===",
+				"",
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Charlie : https://charlie.jp",
+				"// Submitted by Charlie <psl@charlie.jp>",
+				"charlie.jp",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+		},
+		{
+			name:   "wildcards",
+			filter: Filter{WildcardsOnly: true},
+			want: byteLines(
+				"// License header",
+				"",
+				"// ===BEGIN ICANN DOMAINS===",
+				"",
+				"// ck : https://www.iana.org/domains/root/db/ck.html",
+				"*.ck",
+				"!www.ck",
+				"",
+				"// jp : https://www.iana.org/domains/root/db/jp.html",
+				"// jp geographic type names",
+				"*.kawasaki.jp",
+				"",
+				"// ===END ICANN DOMAINS===",
+				"",
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Bravo : https://bravo.net",
+				"// Submitted by Bravo <psl@bravo.net>",
+				"*.users.bravo.net",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+		},
+		{
+			name:   "entity",
+			filter: Filter{Entities: []string{"aaa", "bravo"}},
+			want: byteLines(
+				"// License header",
+				"",
+				"// ===BEGIN ICANN DOMAINS===",
+				"",
+				"// aaa : https://www.iana.org/domains/root/db/aaa.html",
+				"aaa",
+				"",
+				"// ===END ICANN DOMAINS===",
+				"",
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Bravo : https://bravo.net",
+				"// Submitted by Bravo <psl@bravo.net>",
+				"bravo.net",
+				"*.users.bravo.net",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+		},
+		{
+			name: "exclude_entity",
+			filter: Filter{
+				Sections:        []string{"ICANN DOMAINS"},
+				ExcludeEntities: []string{"jp", "aaa"},
+			},
+			want: byteLines(
+				"// License header",
+				"",
+				"// ===BEGIN ICANN DOMAINS===",
+				"",
+				"// ck : https://www.iana.org/domains/root/db/ck.html",
+				"*.ck",
+				"!www.ck",
+				"",
+				"// newGTLDs",
+				"",
+				"// ===END ICANN DOMAINS===",
+				"",
+			),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			l, errs := Parse(in)
+			if len(errs) > 0 {
+				t.Fatalf("Parse failed: %v", errs)
+			}
+			got := l.Filter(tc.filter)
+			checkDiff(t, "filtered list", string(got.MarshalPSL()), string(tc.want))
+			// The filtered list must still be a valid PSL file.
+			if _, errs := Parse(got.MarshalPSL()); len(errs) > 0 {
+				t.Errorf("filtered list doesn't parse: %v", errs)
+			}
+			checkDiff(t, "original list after Filter", string(l.MarshalPSL()), string(in))
+		})
+	}
+}
+
+func TestFilterGroups(t *testing.T) {
+	in := byteLines(
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		`// psl:begin-group owner="Example Corp" sort-key="Example"`,
+		"// Several Example Corp brands follow.",
+		"",
+		"// Xray : https://xray.com",
+		"// Submitted by Xray <psl@xray.com>",
+		"xray.com",
+		"",
+		"// Yankee : https://yankee.com",
+		"// Submitted by Yankee <psl@yankee.com>",
+		"yankee.com",
+		"",
+		`// psl:end-group owner="Example Corp"`,
+		"",
+		"// Zulu : https://zulu.com",
+		"// Submitted by Zulu <psl@zulu.com>",
+		"zulu.com",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+		"",
+	)
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []byte
+	}{
+		{
+			name:   "owner",
+			filter: Filter{Entities: []string{"example corp"}},
+			want: byteLines(
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				`// psl:begin-group owner="Example Corp" sort-key="Example"`,
+				"// Several Example Corp brands follow.",
+				"",
+				"// Xray : https://xray.com",
+				"// Submitted by Xray <psl@xray.com>",
+				"xray.com",
+				"",
+				"// Yankee : https://yankee.com",
+				"// Submitted by Yankee <psl@yankee.com>",
+				"yankee.com",
+				"",
+				`// psl:end-group owner="Example Corp"`,
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+		},
+		{
+			name:   "member",
+			filter: Filter{Entities: []string{"Yankee"}},
+			want: byteLines(
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				`// psl:begin-group owner="Example Corp" sort-key="Example"`,
+				"// Several Example Corp brands follow.",
+				"",
+				"// Yankee : https://yankee.com",
+				"// Submitted by Yankee <psl@yankee.com>",
+				"yankee.com",
+				"",
+				`// psl:end-group owner="Example Corp"`,
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+		},
+		{
+			name:   "exclude_owner",
+			filter: Filter{ExcludeEntities: []string{"Example Corp"}},
+			want: byteLines(
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Zulu : https://zulu.com",
+				"// Submitted by Zulu <psl@zulu.com>",
+				"zulu.com",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			l, errs := Parse(in)
+			if len(errs) > 0 {
+				t.Fatalf("Parse failed: %v", errs)
+			}
+			got := l.Filter(tc.filter)
+			checkDiff(t, "filtered list", string(got.MarshalPSL()), string(tc.want))
+		})
+	}
+}
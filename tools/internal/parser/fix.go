@@ -0,0 +1,317 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/publicsuffix/list/tools/internal/domain"
+)
+
+// Fix describes a mechanical correction made by FixSource or
+// List.ApplyFixes.
+type Fix struct {
+	// SourceRange is the location of the fixed problem in the input.
+	// It is the zero SourceRange for fixes that apply to the whole
+	// file.
+	SourceRange
+	// Description says what was changed.
+	Description string
+}
+
+func (f Fix) String() string {
+	if f.SourceRange.NumLines() == 0 {
+		return f.Description
+	}
+	return fmt.Sprintf("%s: %s", f.SourceRange.LocationString(), f.Description)
+}
+
+// FixSource returns bs with its character encoding and misspelled
+// section markers corrected, along with a description of each
+// correction.
+//
+// These problems are fixed before parsing because the parser can't
+// represent them in a List: bs is converted to UTF-8 without a byte
+// order mark, and unknown section markers are dropped from the parse
+// tree.
+//
+// FixSource only fixes problems that have a single obvious fix. UTF-16
+// input without a byte order mark is only converted if the result is
+// valid Unicode, and a misspelled section marker is only corrected if
+// it's a near miss for exactly one of the standard markers, and that
+// marker doesn't already appear elsewhere in the file.
+func FixSource(bs []byte) ([]byte, []Fix) {
+	var fixes []Fix
+
+	bs, fix := fixEncoding(bs)
+	if fix != nil {
+		fixes = append(fixes, *fix)
+	}
+
+	lines := strings.Split(string(bs), "\n")
+	markerFixes := fixSectionMarkers(lines)
+	if len(markerFixes) > 0 {
+		fixes = append(fixes, markerFixes...)
+		bs = []byte(strings.Join(lines, "\n"))
+	}
+
+	return bs, fixes
+}
+
+// fixEncoding returns bs converted to UTF-8 without a byte order
+// mark, and a description of the conversion. If bs is already in
+// canonical form, or can't be safely converted, fixEncoding returns bs
+// unchanged and a nil Fix.
+func fixEncoding(bs []byte) ([]byte, *Fix) {
+	var (
+		enc     = utf8Transform
+		desc    string
+		guessed bool
+	)
+	switch {
+	case bytes.HasPrefix(bs, []byte(bomUTF8)):
+		desc = "removed UTF-8 byte order mark"
+	case bytes.HasPrefix(bs, []byte(bomUTF16BE)):
+		enc, desc = utf16BigEndianTransform, "converted file from UTF-16BE to UTF-8"
+	case bytes.HasPrefix(bs, []byte(bomUTF16LE)):
+		enc, desc = utf16LittleEndianTransform, "converted file from UTF-16LE to UTF-8"
+	default:
+		switch guessUTFVariant(bs) {
+		case utf16BigEndianTransform:
+			enc, desc = utf16BigEndianTransform, "converted file from UTF-16BE (guessed) to UTF-8"
+		case utf16LittleEndianTransform:
+			enc, desc = utf16LittleEndianTransform, "converted file from UTF-16LE (guessed) to UTF-8"
+		default:
+			return bs, nil
+		}
+		guessed = true
+	}
+
+	ret, err := enc.NewDecoder().Bytes(bs)
+	if err != nil {
+		return bs, nil
+	}
+	if guessed && (!utf8.Valid(ret) || bytes.ContainsRune(ret, utf8.RuneError) || bytes.ContainsRune(ret, 0)) {
+		// The guess was probably wrong, leave it to a human.
+		return bs, nil
+	}
+	return ret, &Fix{Description: desc}
+}
+
+// standardSectionMarkers are the section marker lines of a
+// well-formed PSL file.
+var standardSectionMarkers = []string{
+	"// ===BEGIN ICANN DOMAINS===",
+	"// ===END ICANN DOMAINS===",
+	"// ===BEGIN PRIVATE DOMAINS===",
+	"// ===END PRIVATE DOMAINS===",
+}
+
+// maxMarkerTypos is the maximum edit distance between a misspelled
+// section marker and the standard marker it gets corrected to.
+const maxMarkerTypos = 3
+
+// fixSectionMarkers corrects misspelled section markers in lines, and
+// returns a description of each correction.
+func fixSectionMarkers(lines []string) []Fix {
+	// Only correct lines to markers that are missing from the file,
+	// and only if a single line is a candidate for that marker.
+	present := map[string]bool{}
+	for _, line := range lines {
+		present[strings.TrimSpace(line)] = true
+	}
+	candidates := map[string][]int{} // marker -> line numbers
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if slices.Contains(standardSectionMarkers, line) {
+			continue
+		}
+		if !strings.HasPrefix(line, "//") || !strings.Contains(line, "==") {
+			continue
+		}
+		if marker, ok := closestSectionMarker(line); ok && !present[marker] {
+			candidates[marker] = append(candidates[marker], i)
+		}
+	}
+
+	var ret []Fix
+	for _, marker := range standardSectionMarkers {
+		lns := candidates[marker]
+		if len(lns) != 1 {
+			continue
+		}
+		i := lns[0]
+		old := strings.TrimSpace(lines[i])
+		lines[i] = strings.Replace(lines[i], old, marker, 1)
+		ret = append(ret, Fix{
+			SourceRange: SourceRange{FirstLine: i, LastLine: i + 1},
+			Description: fmt.Sprintf("corrected section marker %q to %q", old, marker),
+		})
+	}
+	slices.SortFunc(ret, func(a, b Fix) int { return a.FirstLine - b.FirstLine })
+	return ret
+}
+
+// closestSectionMarker returns the standard section marker that line
+// is a misspelling of, if there is exactly one close match.
+func closestSectionMarker(line string) (string, bool) {
+	norm := func(s string) string {
+		s = strings.ToUpper(s)
+		return strings.Map(func(r rune) rune {
+			if r == '/' || r == '=' || r == ' ' || r == '\t' {
+				return -1
+			}
+			return r
+		}, s)
+	}
+
+	got := norm(line)
+	best, bestDist, tie := "", maxMarkerTypos+1, false
+	for _, marker := range standardSectionMarkers {
+		d := editDistance(got, norm(marker))
+		switch {
+		case d < bestDist:
+			best, bestDist, tie = marker, d, false
+		case d == bestDist:
+			tie = true
+		}
+	}
+	if best == "" || tie {
+		return "", false
+	}
+	return best, true
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	cur := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		cur[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(br)]
+}
+
+// ApplyFixes makes safe, mechanical corrections to l for problems
+// that Clean doesn't fix, and returns a description of each
+// correction.
+//
+// ApplyFixes removes suffixes and wildcards that are repeated within
+// a single suffix block. Duplicates in different suffix blocks are
+// left alone, because there's no way to tell which block should keep
+// the suffix.
+//
+// If l was parsed with ParseLossless, ApplyFixes also rewrites
+// suffixes that were not written in canonical form, such as uppercase
+// or punycode suffixes, which MarshalPSL would otherwise reproduce
+// verbatim.
+func (l *List) ApplyFixes() []Fix {
+	var ret []Fix
+	for _, block := range BlocksOfType[*Suffixes](l) {
+		ret = append(ret, l.fixSpelling(block)...)
+		ret = append(ret, l.fixDuplicates(block)...)
+	}
+	return ret
+}
+
+// fixSpelling forces suffixes in block that aren't in canonical form
+// in the source text to be rewritten.
+func (l *List) fixSpelling(block *Suffixes) []Fix {
+	if l.src == nil {
+		return nil
+	}
+
+	var ret []Fix
+	for _, child := range block.Blocks {
+		var want string
+		switch v := child.(type) {
+		case *Suffix:
+			want = v.Domain.String()
+		case *Wildcard:
+			want = "*." + v.Domain.String()
+		default:
+			continue
+		}
+		if !l.src.hasSource(child) {
+			continue
+		}
+		src := child.SrcRange()
+		got := l.src.line(src.FirstLine)
+		if got == want {
+			continue
+		}
+		ret = append(ret, Fix{
+			SourceRange: src,
+			Description: fmt.Sprintf("rewrote suffix %q as %q", got, want),
+		})
+		l.src.forget(l, child)
+		markChanged(l, child)
+	}
+	return ret
+}
+
+// fixDuplicates removes suffixes and wildcards that appear more than
+// once in block. Exceptions of duplicate wildcards are merged into
+// the first copy.
+func (l *List) fixDuplicates(block *Suffixes) []Fix {
+	var (
+		ret       []Fix
+		suffixes  = map[string]*Suffix{}
+		wildcards = map[string]*Wildcard{}
+		dups      []Block
+	)
+	for _, child := range block.Blocks {
+		switch v := child.(type) {
+		case *Suffix:
+			name := v.Domain.String()
+			first, ok := suffixes[name]
+			if !ok {
+				suffixes[name] = v
+				continue
+			}
+			dups = append(dups, v)
+			ret = append(ret, Fix{
+				SourceRange: v.SourceRange,
+				Description: fmt.Sprintf("removed duplicate suffix %q, already listed at %s", name, first.LocationString()),
+			})
+		case *Wildcard:
+			name := v.Domain.String()
+			first, ok := wildcards[name]
+			if !ok {
+				wildcards[name] = v
+				continue
+			}
+			for _, exc := range v.Exceptions {
+				if !slices.ContainsFunc(first.Exceptions, exc.Equal) {
+					first.Exceptions = append(first.Exceptions, exc)
+				}
+			}
+			slices.SortFunc(first.Exceptions, domain.Label.Compare)
+			markChanged(l, first)
+			dups = append(dups, v)
+			ret = append(ret, Fix{
+				SourceRange: v.SourceRange,
+				Description: fmt.Sprintf("removed duplicate wildcard \"*.%s\", already listed at %s", name, first.LocationString()),
+			})
+		}
+	}
+	for _, dup := range dups {
+		markChanged(l, dup)
+		deleteBlock(block, dup)
+	}
+	return ret
+}
@@ -0,0 +1,176 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestFixSource(t *testing.T) {
+	list := lines(
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// Bravo",
+		"bravo.net",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+		"",
+	)
+
+	tests := []struct {
+		name      string
+		in        []byte
+		want      string
+		wantFixes []string
+	}{
+		{
+			name: "canonical",
+			in:   []byte(list),
+			want: list,
+		},
+		{
+			name:      "utf8_bom",
+			in:        utf8WithBOM(list),
+			want:      list,
+			wantFixes: []string{"removed UTF-8 byte order mark"},
+		},
+		{
+			name:      "utf16le_bom",
+			in:        utf16LittleWithBOM(list),
+			want:      list,
+			wantFixes: []string{"converted file from UTF-16LE to UTF-8"},
+		},
+		{
+			name:      "utf16be_guessed",
+			in:        utf16Big(list),
+			want:      list,
+			wantFixes: []string{"converted file from UTF-16BE (guessed) to UTF-8"},
+		},
+		{
+			name: "misspelled_markers",
+			in: []byte(lines(
+				"// ===BEGN PRIVATE DOMAINS===",
+				"",
+				"// Bravo",
+				"bravo.net",
+				"",
+				"  // ==END PRIVATE DOMAINS==",
+				"",
+			)),
+			want: lines(
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Bravo",
+				"bravo.net",
+				"",
+				"  // ===END PRIVATE DOMAINS===",
+				"",
+			),
+			wantFixes: []string{
+				`line 1: corrected section marker "// ===BEGN PRIVATE DOMAINS===" to "// ===BEGIN PRIVATE DOMAINS==="`,
+				`line 6: corrected section marker "// ==END PRIVATE DOMAINS==" to "// ===END PRIVATE DOMAINS==="`,
+			},
+		},
+		{
+			name: "ambiguous_markers",
+			in: []byte(lines(
+				// Two candidates for the same missing marker.
+				"// ===BEGN PRIVATE DOMAINS===",
+				"// ===BEGIN PRIVATE DOMAIN===",
+				// The marker already exists.
+				"// ===END PRIVATE DOMAIN===",
+				"// ===END PRIVATE DOMAINS===",
+				// Too far from any standard marker.
+				"// ===BEGIN AMAZON DOMAINS===",
+			)),
+			want: lines(
+				"// ===BEGN PRIVATE DOMAINS===",
+				"// ===BEGIN PRIVATE DOMAIN===",
+				"// ===END PRIVATE DOMAIN===",
+				"// ===END PRIVATE DOMAINS===",
+				"// ===BEGIN AMAZON DOMAINS===",
+			),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, fixes := FixSource(tc.in)
+			checkDiff(t, "FixSource output", string(got), tc.want)
+			var gotFixes []string
+			for _, f := range fixes {
+				gotFixes = append(gotFixes, f.String())
+			}
+			checkDiff(t, "FixSource fixes", gotFixes, tc.wantFixes)
+		})
+	}
+}
+
+func TestApplyFixes(t *testing.T) {
+	in := lines(
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// Bravo : https://bravo.net",
+		"  Bravo.NET",
+		"*.users.bravo.net",
+		"!www.users.bravo.net",
+		"bravo.net",
+		"*.users.bravo.net",
+		"!api.users.bravo.net",
+		"",
+		"// Charlie : https://charlie.net",
+		"charlie.net",
+		"xn--mnchen-3ya.charlie.net",
+		"",
+		"// Delta : https://delta.net",
+		"delta.net",
+		"charlie.net",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+		"",
+	)
+	want := lines(
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// Bravo : https://bravo.net",
+		"bravo.net",
+		"*.users.bravo.net",
+		"!api.users.bravo.net",
+		"!www.users.bravo.net",
+		"",
+		"// Charlie : https://charlie.net",
+		"charlie.net",
+		"münchen.charlie.net",
+		"",
+		"// Delta : https://delta.net",
+		"delta.net",
+		"charlie.net",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+		"",
+	)
+	wantFixes := []string{
+		`line 4:3-11: rewrote suffix "Bravo.NET" as "bravo.net"`,
+		`line 7:1-9: removed duplicate suffix "bravo.net", already listed at line 4:3-11`,
+		`line 8:1-17: removed duplicate wildcard "*.users.bravo.net", already listed at line 5:1-17`,
+		`line 13:1-26: rewrote suffix "xn--mnchen-3ya.charlie.net" as "münchen.charlie.net"`,
+	}
+
+	l, errs := ParseLossless([]byte(in))
+	for _, err := range errs {
+		t.Fatalf("parse error: %v", err)
+	}
+	l.SetBaseVersion(l, false)
+	var gotFixes []string
+	for _, f := range l.ApplyFixes() {
+		gotFixes = append(gotFixes, f.String())
+	}
+	checkDiff(t, "ApplyFixes fixes", gotFixes, wantFixes)
+	checkDiff(t, "fixed list", string(l.MarshalPSL()), want)
+
+	var changed []string
+	for _, b := range BlocksOfType[*Suffixes](l) {
+		if b.Changed() {
+			changed = append(changed, b.Info.Name)
+		}
+	}
+	checkDiff(t, "changed blocks", changed, []string{"Bravo", "Charlie"})
+}
@@ -0,0 +1,244 @@
+package parser
+
+import (
+	"bytes"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/publicsuffix/list/tools/internal/domain"
+)
+
+// addListSeeds adds seed inputs for fuzz targets taken from the real
+// PSL. The list is too large to be a useful fuzz input, so it's cut
+// into chunks of a few suffix blocks, each wrapped in the section
+// markers of the section it comes from. Chunks are kept small, because
+// the fuzzer spends a long time minimizing large inputs.
+func addListSeeds(f *testing.F, add func(seed []byte)) {
+	f.Helper()
+	bs, err := os.ReadFile("../../../public_suffix_list.dat")
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	const chunkLines = 20
+	var (
+		section string
+		chunk   []string
+	)
+	flush := func() {
+		if section != "" && len(chunk) > 0 {
+			seed := lines(
+				"// ===BEGIN "+section+"===",
+				"",
+				strings.Join(chunk, "\n"),
+				"",
+				"// ===END "+section+"===",
+				"",
+			)
+			add([]byte(seed))
+		}
+		chunk = nil
+	}
+	for _, line := range strings.Split(string(bs), "\n") {
+		switch {
+		case strings.HasPrefix(line, sectionStartPrefix):
+			flush()
+			section = strings.TrimSuffix(strings.TrimPrefix(line, sectionStartPrefix), "===")
+		case strings.HasPrefix(line, sectionEndPrefix):
+			flush()
+			section = ""
+		case line == "" && len(chunk) >= chunkLines:
+			flush()
+		default:
+			chunk = append(chunk, line)
+		}
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	addListSeeds(f, func(seed []byte) { f.Add(seed) })
+
+	f.Fuzz(func(t *testing.T, in []byte) {
+		psl, _ := Parse(in)
+		psl.MarshalPSL()
+		psl.MarshalDebug()
+
+		lossless, _ := ParseLossless(in)
+		if isUTF8(in) {
+			if got := lossless.MarshalPSL(); !bytes.Equal(got, in) {
+				t.Fatalf("lossless round trip changed input:\ngot:  %q\nwant: %q", got, in)
+			}
+		}
+	})
+}
+
+func FuzzClean(f *testing.F) {
+	addListSeeds(f, func(seed []byte) { f.Add(seed) })
+
+	f.Fuzz(func(t *testing.T, in []byte) {
+		psl, errs := Parse(in)
+		if len(errs) > 0 {
+			// Formatting only has to be stable for valid inputs.
+			return
+		}
+		wantRules := rules(psl)
+		hosts := testHostnames(psl)
+		wantSuffixes := publicSuffixes(psl, hosts)
+
+		psl.Clean()
+		if got := rules(psl); !slices.Equal(got, wantRules) {
+			t.Fatalf("Clean changed the list's rules:\ngot:  %q\nwant: %q", got, wantRules)
+		}
+		if got := publicSuffixes(psl, hosts); !slices.Equal(got, wantSuffixes) {
+			t.Fatalf("Clean changed public suffixes:\ngot:  %q\nwant: %q", got, wantSuffixes)
+		}
+
+		formatted := psl.MarshalPSL()
+		psl2, errs := Parse(formatted)
+		if len(errs) > 0 {
+			t.Fatalf("formatted output doesn't parse: %v\n%s", errs, formatted)
+		}
+		if got := rules(psl2); !slices.Equal(got, wantRules) {
+			t.Fatalf("formatting changed the list's rules:\ngot:  %q\nwant: %q", got, wantRules)
+		}
+		psl2.Clean()
+		if reformatted := psl2.MarshalPSL(); !bytes.Equal(reformatted, formatted) {
+			t.Fatalf("formatting is not idempotent:\nfirst:  %q\nsecond: %q", formatted, reformatted)
+		}
+	})
+}
+
+func FuzzSetBaseVersion(f *testing.F) {
+	var seeds [][]byte
+	addListSeeds(f, func(seed []byte) { seeds = append(seeds, seed) })
+	for i := range seeds {
+		f.Add(seeds[i], seeds[i], true)
+		if i > 0 {
+			f.Add(seeds[i-1], seeds[i], false)
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, before, after []byte, wholeSuffixBlocks bool) {
+		base, _ := Parse(before)
+		psl, _ := Parse(after)
+		psl.SetBaseVersion(base, wholeSuffixBlocks)
+
+		// A list compared against itself has no changes, unless it
+		// has blocks with duplicate identities, which SetBaseVersion
+		// always marks changed.
+		ids := differ{oldCnt: map[string]int{}, keys: map[Block]string{}}
+		ids.scanOld(psl, "")
+		for _, n := range ids.oldCnt {
+			if n > 1 {
+				return
+			}
+		}
+		same, _ := Parse(after)
+		psl.SetBaseVersion(same, wholeSuffixBlocks)
+		for _, b := range allBlocks(psl) {
+			if b.Changed() {
+				t.Fatalf("block at %s changed compared to an identical list", b.SrcRange().LocationString())
+			}
+		}
+	})
+}
+
+// allBlocks returns all the blocks of l, excluding l itself.
+func allBlocks(l *List) []Block {
+	var ret []Block
+	var rec func(Block)
+	rec = func(b Block) {
+		for _, child := range b.Children() {
+			ret = append(ret, child)
+			rec(child)
+		}
+	}
+	rec(l)
+	return ret
+}
+
+// rules returns the sorted set of PSL rules in l, in the PSL's text
+// format.
+func rules(l *List) []string {
+	var ret []string
+	for _, s := range BlocksOfType[*Suffix](l) {
+		ret = append(ret, s.Domain.String())
+	}
+	for _, w := range BlocksOfType[*Wildcard](l) {
+		ret = append(ret, "*."+w.Domain.String())
+		for _, exc := range w.Exceptions {
+			ret = append(ret, "!"+exc.String()+"."+w.Domain.String())
+		}
+	}
+	slices.Sort(ret)
+	return slices.Compact(ret)
+}
+
+// maxTestHostnames is the maximum number of hostnames that
+// testHostnames returns. Some suffix blocks of the real PSL contain
+// thousands of suffixes, and checking all of them makes fuzzing
+// crawl.
+const maxTestHostnames = 300
+
+// testHostnames returns hostnames that exercise the rules of l: each
+// suffix itself, and names a few labels below it. For large lists,
+// only an evenly spaced sample of the hostnames is returned.
+func testHostnames(l *List) []domain.Name {
+	fuzz, err := domain.ParseLabel("fuzz")
+	if err != nil {
+		panic(err)
+	}
+
+	// Building a Name runs IDNA validation, which is slow, so decide
+	// which hostnames to check before building them.
+	type hostname struct {
+		parent   domain.Name
+		prefixes []domain.Label
+	}
+	var hosts []hostname
+	add := func(d domain.Name, prefixes ...domain.Label) {
+		hosts = append(hosts, hostname{d, prefixes})
+	}
+	for _, s := range BlocksOfType[*Suffix](l) {
+		add(s.Domain)
+		add(s.Domain, fuzz)
+		add(s.Domain, fuzz, fuzz)
+	}
+	for _, w := range BlocksOfType[*Wildcard](l) {
+		add(w.Domain)
+		add(w.Domain, fuzz)
+		add(w.Domain, fuzz, fuzz)
+		for _, exc := range w.Exceptions {
+			add(w.Domain, exc)
+			add(w.Domain, fuzz, exc)
+		}
+	}
+	if len(hosts) > maxTestHostnames {
+		sample := make([]hostname, 0, maxTestHostnames)
+		for i := range maxTestHostnames {
+			sample = append(sample, hosts[i*len(hosts)/maxTestHostnames])
+		}
+		hosts = sample
+	}
+
+	ret := make([]domain.Name, 0, len(hosts))
+	for _, h := range hosts {
+		if n, err := h.parent.AddPrefix(h.prefixes...); err == nil {
+			ret = append(ret, n)
+		}
+	}
+	return ret
+}
+
+// publicSuffixes returns the public suffix of each of hosts according
+// to l.
+func publicSuffixes(l *List, hosts []domain.Name) []string {
+	ret := make([]string, 0, len(hosts))
+	for _, h := range hosts {
+		ret = append(ret, l.PublicSuffix(h).String())
+		l.RegisteredDomain(h)
+	}
+	return ret
+}
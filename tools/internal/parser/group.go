@@ -0,0 +1,136 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Group markers are the first line of a standalone comment in a
+// section. Attribute values are double-quoted strings:
+//
+//	psl:begin-group owner="<name>" [sort-key="<name>"]
+//	psl:end-group owner="<name>"
+//
+// The rest of a marker comment is free-form text, for example a
+// description of the group.
+const (
+	groupBeginPrefix = "psl:begin-group"
+	groupEndPrefix   = "psl:end-group"
+)
+
+// groupMarker is a parsed group marker.
+type groupMarker struct {
+	begin   bool
+	owner   string
+	sortKey string
+}
+
+// isGroupMarker reports whether line is a group marker, possibly a
+// malformed one.
+func isGroupMarker(line string) bool {
+	for _, prefix := range []string{groupBeginPrefix, groupEndPrefix} {
+		if rest, ok := strings.CutPrefix(line, prefix); ok && (rest == "" || rest[0] == ' ') {
+			return true
+		}
+	}
+	return false
+}
+
+// parseGroupMarker parses line as a group marker. It returns ok=false
+// if line is not a group marker, and an error if it is a malformed
+// one.
+func parseGroupMarker(line string) (m groupMarker, ok bool, err error) {
+	if !isGroupMarker(line) {
+		return groupMarker{}, false, nil
+	}
+	rest, begin := strings.CutPrefix(line, groupBeginPrefix)
+	if !begin {
+		rest = strings.TrimPrefix(line, groupEndPrefix)
+	}
+	attrs, err := parseMarkerAttrs(rest)
+	if err != nil {
+		return groupMarker{}, true, err
+	}
+
+	m = groupMarker{begin: begin}
+	for k, v := range attrs {
+		switch {
+		case k == "owner":
+			m.owner = v
+		case k == "sort-key" && begin:
+			m.sortKey = v
+		default:
+			return groupMarker{}, true, fmt.Errorf("unknown attribute %q", k)
+		}
+	}
+	if strings.TrimSpace(m.owner) == "" {
+		return groupMarker{}, true, errors.New(`missing owner="..."`)
+	}
+	if m.sortKey == "" {
+		m.sortKey = m.owner
+	}
+	return m, true, nil
+}
+
+// parseMarkerAttrs parses a space-separated list of key="value"
+// attributes.
+func parseMarkerAttrs(s string) (map[string]string, error) {
+	ret := map[string]string{}
+	for s = strings.TrimSpace(s); s != ""; s = strings.TrimSpace(s) {
+		key, rest, ok := strings.Cut(s, "=")
+		if !ok || key == "" || strings.ContainsAny(key, ` "`) {
+			return nil, fmt.Errorf(`unexpected %q, want key="value"`, s)
+		}
+		quoted, err := strconv.QuotedPrefix(rest)
+		if err != nil || !strings.HasPrefix(quoted, `"`) {
+			return nil, fmt.Errorf("value of %s must be a double-quoted string", key)
+		}
+		if _, dup := ret[key]; dup {
+			return nil, fmt.Errorf("repeated attribute %q", key)
+		}
+		ret[key], _ = strconv.Unquote(quoted)
+		s = rest[len(quoted):]
+	}
+	return ret, nil
+}
+
+// validateGroups checks that group markers only appear where they
+// delimit a group, and that groups are not empty or repeated within a
+// section.
+func validateGroups(l *List) (errs []error) {
+	var rec func(b, parent Block)
+	rec = func(b, parent Block) {
+		if c, ok := b.(*Comment); ok {
+			_, standalone := parent.(*Section)
+			if _, ok := parent.(*Group); ok {
+				standalone = true
+			}
+			for i, line := range c.Text {
+				if isGroupMarker(line) && (i > 0 || !standalone) {
+					errs = append(errs, ErrMisplacedGroupMarker{commentLineRange(c, i)})
+				}
+			}
+		}
+		for _, child := range b.Children() {
+			rec(child, b)
+		}
+	}
+	rec(l, nil)
+
+	for _, s := range BlocksOfType[*Section](l) {
+		seen := map[string]*Group{}
+		for _, g := range BlocksOfType[*Group](s) {
+			if len(BlocksOfType[*Suffixes](g)) == 0 {
+				errs = append(errs, ErrEmptyGroup{g})
+			}
+			if first := seen[g.Owner]; first != nil {
+				errs = append(errs, ErrDuplicateGroup{g, first})
+			} else {
+				seen[g.Owner] = g
+			}
+		}
+	}
+	return errs
+}
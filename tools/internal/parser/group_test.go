@@ -0,0 +1,226 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestGroups(t *testing.T) {
+	in := byteLines(
+		"// ===BEGIN ICANN DOMAINS===",
+		"",
+		"// com",
+		"com",
+		"",
+		"// ===END ICANN DOMAINS===",
+		"",
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// Zulu : https://zulu.com",
+		"zulu.com",
+		"",
+		`// psl:begin-group owner="Example Corp" sort-key="Bravo"`,
+		"// Several Example Corp brands follow.",
+		"",
+		"// Yankee : https://yankee.com",
+		"yankee.com",
+		"",
+		"// Xray : https://xray.com",
+		"xray.com",
+		"",
+		`// psl:end-group owner="Example Corp"`,
+		"",
+		"// Alpha : https://alpha.com",
+		"alpha.com",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+	)
+	l, errs := Parse(in)
+	if len(errs) > 0 {
+		t.Fatalf("Parse failed: %v", errs)
+	}
+
+	groups := BlocksOfType[*Group](l)
+	if len(groups) != 1 {
+		t.Fatalf("got %d groups, want 1", len(groups))
+	}
+	g := groups[0]
+	if g.Owner != "Example Corp" || g.SortKey != "Bravo" {
+		t.Errorf("group has owner=%q sortkey=%q, want Example Corp and Bravo", g.Owner, g.SortKey)
+	}
+	if got, want := g.LocationString(), "lines 13-22"; got != want {
+		t.Errorf("group location is %q, want %q", got, want)
+	}
+	if got := len(BlocksOfType[*Suffixes](g)); got != 2 {
+		t.Errorf("group has %d suffix blocks, want 2", got)
+	}
+
+	if got := string(l.MarshalPSL()); got != string(in)+"\n" {
+		checkDiff(t, "round trip", got, string(in)+"\n")
+	}
+
+	// The group sorts as one unit by its sort key, and its contents
+	// keep their order.
+	if errs := l.Clean(); len(errs) > 0 {
+		t.Fatalf("Clean failed: %v", errs)
+	}
+	checkDiff(t, "cleaned list", string(l.MarshalPSL()), string(byteLines(
+		"// ===BEGIN ICANN DOMAINS===",
+		"",
+		"// com",
+		"com",
+		"",
+		"// ===END ICANN DOMAINS===",
+		"",
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// Alpha : https://alpha.com",
+		"alpha.com",
+		"",
+		`// psl:begin-group owner="Example Corp" sort-key="Bravo"`,
+		"// Several Example Corp brands follow.",
+		"",
+		"// Yankee : https://yankee.com",
+		"yankee.com",
+		"",
+		"// Xray : https://xray.com",
+		"xray.com",
+		"",
+		`// psl:end-group owner="Example Corp"`,
+		"",
+		"// Zulu : https://zulu.com",
+		"zulu.com",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+		"",
+	)))
+}
+
+func TestGroupErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      []string
+		wantErr []string
+	}{
+		{
+			name: "invalid_marker",
+			in: []string{
+				`// psl:begin-group sort-key="Alpha"`,
+				"",
+				"// Alpha : https://alpha.com",
+				"alpha.com",
+			},
+			wantErr: []string{
+				`line 3: invalid group marker "psl:begin-group sort-key=\"Alpha\"": missing owner="..."`,
+			},
+		},
+		{
+			name: "unclosed",
+			in: []string{
+				`// psl:begin-group owner="Alpha"`,
+				"",
+				"// Alpha : https://alpha.com",
+				"alpha.com",
+			},
+			wantErr: []string{
+				`lines 3-6: group "Alpha" is missing its end marker`,
+			},
+		},
+		{
+			name: "unstarted",
+			in: []string{
+				"// Alpha : https://alpha.com",
+				"alpha.com",
+				"",
+				`// psl:end-group owner="Alpha"`,
+			},
+			wantErr: []string{
+				`line 6: end marker for non-existent group "Alpha"`,
+			},
+		},
+		{
+			name: "mismatched",
+			in: []string{
+				`// psl:begin-group owner="Alpha"`,
+				"",
+				"// Alpha : https://alpha.com",
+				"alpha.com",
+				"",
+				`// psl:end-group owner="Bravo"`,
+			},
+			wantErr: []string{
+				`line 8: group "Alpha" (line 3) closed with wrong owner "Bravo"`,
+			},
+		},
+		{
+			name: "nested",
+			in: []string{
+				`// psl:begin-group owner="Alpha"`,
+				"",
+				`// psl:begin-group owner="Bravo"`,
+				"",
+				"// Bravo : https://bravo.com",
+				"bravo.com",
+				"",
+				`// psl:end-group owner="Bravo"`,
+				"",
+				`// psl:end-group owner="Alpha"`,
+			},
+			wantErr: []string{
+				`line 5: group "Bravo" is nested inside group "Alpha" (lines 3-12)`,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lines := []any{"// ===BEGIN PRIVATE DOMAINS===", ""}
+			for _, ln := range tc.in {
+				lines = append(lines, ln)
+			}
+			lines = append(lines, "", "// ===END PRIVATE DOMAINS===")
+			_, errs := Parse(byteLines(lines...))
+			checkDiff(t, "parse errors", errorStrings(errs), tc.wantErr)
+		})
+	}
+}
+
+func TestValidateGroups(t *testing.T) {
+	in := byteLines(
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		`// psl:begin-group owner="Alpha"`,
+		"",
+		`// psl:end-group owner="Alpha"`,
+		"",
+		`// psl:begin-group owner="Bravo"`,
+		"",
+		"// Bravo : https://bravo.com",
+		"bravo.com",
+		"",
+		`// psl:end-group owner="Bravo"`,
+		"",
+		`// psl:begin-group owner="Bravo"`,
+		"",
+		"// Bravo Labs : https://bravolabs.com",
+		"bravolabs.com",
+		"",
+		`// psl:end-group owner="Bravo"`,
+		"",
+		"// Charlie : https://charlie.com",
+		`// psl:begin-group owner="Charlie"`,
+		"charlie.com",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+	)
+	l, errs := Parse(in)
+	if len(errs) > 0 {
+		t.Fatalf("Parse failed: %v", errs)
+	}
+
+	got := validateGroups(l)
+	checkDiff(t, "validation errors", errorStrings(got), []string{
+		"line 22: group marker must be the first line of a comment between suffix blocks",
+		`lines 3-5: group "Alpha" has no suffix blocks`,
+		`lines 14-19: duplicate group "Bravo", first definition at lines 7-12`,
+	})
+}
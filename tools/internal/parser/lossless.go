@@ -0,0 +1,228 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// rawSource is the original source text of a List parsed with
+// ParseLossless, and the information needed to reproduce it when
+// marshaling.
+//
+// Blocks don't track their own modifications, so rawSource remembers
+// what each block looked like when it was parsed. When marshaling, a
+// block whose canonical serialization is unchanged since parsing is
+// pristine, and its original source lines are written out verbatim
+// instead.
+type rawSource struct {
+	// lines are the raw lines of the source, split on "\n". Any other
+	// line ending characters are still present.
+	lines []string
+	// newline is the line ending used by the source, for writing
+	// new or edited lines.
+	newline string
+	// canonical is the canonical PSL serialization of each block at
+	// parse time.
+	canonical map[Block]string
+}
+
+// isUTF8 reports whether bs is (probably) UTF-8 encoded, rather than
+// one of the other encodings that normalizeToUTF8Lines understands.
+func isUTF8(bs []byte) bool {
+	if bytes.HasPrefix(bs, []byte(bomUTF16BE)) || bytes.HasPrefix(bs, []byte(bomUTF16LE)) {
+		return false
+	}
+	return guessUTFVariant(bs) == utf8Transform
+}
+
+func newRawSource(l *List, bs []byte) *rawSource {
+	ret := &rawSource{
+		lines:     strings.Split(string(bs), "\n"),
+		newline:   "\n",
+		canonical: map[Block]string{},
+	}
+	if len(ret.lines) > 1 && strings.HasSuffix(ret.lines[0], "\r") {
+		ret.newline = "\r\n"
+	}
+	var rec func(Block)
+	rec = func(b Block) {
+		ret.canonical[b] = canonicalPSL(b)
+		for _, child := range b.Children() {
+			rec(child)
+		}
+	}
+	rec(l)
+	return ret
+}
+
+// canonicalPSL returns the standard PSL serialization of b.
+func canonicalPSL(b Block) string {
+	var ret strings.Builder
+	writeBlockPSL(&ret, b)
+	return ret.String()
+}
+
+// hasSource reports whether b was parsed from the source, and still
+// has a valid location in it.
+func (s *rawSource) hasSource(b Block) bool {
+	if _, ok := s.canonical[b]; !ok {
+		return false
+	}
+	src := b.SrcRange()
+	return src.NumLines() > 0 && src.LastLine <= len(s.lines)
+}
+
+// pristine reports whether b's source text can be reused verbatim.
+func (s *rawSource) pristine(b Block) bool {
+	if !s.hasSource(b) {
+		return false
+	}
+	return s.canonical[b] == canonicalPSL(b)
+}
+
+// forget makes target and its parents in l no longer pristine, so
+// that MarshalPSL rewrites them in canonical form even if they haven't
+// changed since parsing.
+func (s *rawSource) forget(l *List, target Block) {
+	for _, b := range blockPath(l, target) {
+		if _, ok := s.canonical[b]; ok {
+			s.canonical[b] = ""
+		}
+	}
+}
+
+// line returns the ith source line, without leading and trailing
+// whitespace.
+func (s *rawSource) line(i int) string {
+	return strings.TrimSpace(s.lines[i])
+}
+
+// writeText writes text, which is in canonical PSL format, using the
+// source's line endings.
+func (s *rawSource) writeText(w io.Writer, text string) {
+	if s.newline != "\n" {
+		text = strings.ReplaceAll(text, "\n", s.newline)
+	}
+	io.WriteString(w, text)
+}
+
+// writeLines writes source lines [first:last) to w, each followed by
+// a newline.
+func (s *rawSource) writeLines(w io.Writer, first, last int) {
+	for _, line := range s.lines[first:last] {
+		io.WriteString(w, line)
+		io.WriteString(w, "\n")
+	}
+}
+
+// writeGap writes the blank source lines [first:last) that separate
+// two blocks. If the source is not available or doesn't consist only
+// of blank lines, writeGap writes def instead.
+func (s *rawSource) writeGap(w io.Writer, ok bool, first, last int, def string) {
+	if !ok || first > last || last > len(s.lines) {
+		s.writeText(w, def)
+		return
+	}
+	for i := first; i < last; i++ {
+		if s.line(i) != "" {
+			s.writeText(w, def)
+			return
+		}
+	}
+	s.writeLines(w, first, last)
+}
+
+// writeMarker writes the section marker line want, reusing the source
+// text of line i if it has the same content.
+func (s *rawSource) writeMarker(w io.Writer, ok bool, i int, want string) {
+	if ok && i >= 0 && i < len(s.lines) && s.line(i) == want {
+		s.writeLines(w, i, i+1)
+		return
+	}
+	s.writeText(w, want+"\n")
+}
+
+// writeList writes l, reusing as much of the original source text as
+// possible.
+func (s *rawSource) writeList(w io.Writer, l *List) {
+	// Lists don't have a SourceRange, so they need a special
+	// pristine check.
+	if s.canonical[l] == canonicalPSL(l) {
+		io.WriteString(w, strings.Join(s.lines, "\n"))
+		return
+	}
+
+	var prev Block
+	for _, child := range l.Blocks {
+		switch {
+		case prev == nil:
+			s.writeGap(w, s.hasSource(child), 0, child.SrcRange().FirstLine, "")
+		default:
+			ok := s.hasSource(prev) && s.hasSource(child)
+			s.writeGap(w, ok, prev.SrcRange().LastLine, child.SrcRange().FirstLine, "\n")
+		}
+		s.writeBlock(w, child)
+		prev = child
+	}
+
+	// Trailing blank lines. The last element of lines is the
+	// (usually empty) text after the final newline, which must not
+	// get a newline of its own.
+	if prev != nil && s.hasSource(prev) {
+		last := len(s.lines) - 1
+		s.writeGap(w, true, prev.SrcRange().LastLine, last, "")
+		if last >= prev.SrcRange().LastLine && s.line(last) == "" {
+			io.WriteString(w, s.lines[last])
+		}
+	}
+}
+
+// writeBlock writes b, reusing as much of the original source text as
+// possible.
+func (s *rawSource) writeBlock(w io.Writer, b Block) {
+	if s.pristine(b) {
+		src := b.SrcRange()
+		s.writeLines(w, src.FirstLine, src.LastLine)
+		if wc, ok := b.(*Wildcard); ok {
+			// Exceptions are on separate lines that are not part of
+			// the wildcard's SourceRange.
+			for _, exc := range wc.Exceptions {
+				s.writeText(w, fmt.Sprintf("!%s.%s\n", exc, wc.Domain))
+			}
+		}
+		return
+	}
+
+	switch v := b.(type) {
+	case *Section:
+		ok := s.hasSource(v)
+		start, end := v.FirstLine, v.LastLine-1
+		s.writeMarker(w, ok, start, fmt.Sprintf("// ===BEGIN %s===", v.Name))
+		prevOK, prevEnd := ok, start+1
+		for _, child := range v.Blocks {
+			s.writeGap(w, prevOK && s.hasSource(child), prevEnd, child.SrcRange().FirstLine, "\n")
+			s.writeBlock(w, child)
+			prevOK, prevEnd = s.hasSource(child), child.SrcRange().LastLine
+		}
+		s.writeGap(w, ok && prevOK, prevEnd, end, "\n")
+		s.writeMarker(w, ok, end, fmt.Sprintf("// ===END %s===", v.Name))
+	case *Group:
+		var prev Block
+		for _, child := range v.Blocks {
+			if prev != nil {
+				ok := s.hasSource(prev) && s.hasSource(child)
+				s.writeGap(w, ok, prev.SrcRange().LastLine, child.SrcRange().FirstLine, "\n")
+			}
+			s.writeBlock(w, child)
+			prev = child
+		}
+	case *Suffixes:
+		for _, child := range v.Blocks {
+			s.writeBlock(w, child)
+		}
+	default:
+		s.writeText(w, canonicalPSL(b))
+	}
+}
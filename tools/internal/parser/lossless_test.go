@@ -0,0 +1,245 @@
+package parser
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseLosslessRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{
+			name: "canonical",
+			in: lines(
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Bravo",
+				"bravo.net",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+		},
+		{
+			name: "messy_whitespace",
+			in: lines(
+				"  // License  ",
+				"",
+				"",
+				"// ===BEGIN PRIVATE DOMAINS===\t",
+				"",
+				"",
+				"\t// Bravo",
+				"  bravo.net  ",
+				"*.users.bravo.net",
+				"  !www.users.bravo.net",
+				"   ",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+				"",
+			),
+		},
+		{
+			name: "crlf",
+			in:   "// ===BEGIN PRIVATE DOMAINS===\r\n\r\n// Bravo\r\nbravo.net\r\n\r\n// ===END PRIVATE DOMAINS===\r\n",
+		},
+		{
+			name: "no_trailing_newline",
+			in:   "// Bravo\nbravo.net",
+		},
+		{
+			name: "bom",
+			in:   bomUTF8 + "// Bravo\nbravo.net\n",
+		},
+		{
+			name: "parse_errors",
+			in: lines(
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// ===BOGUS===",
+				"bravo..net",
+				"!www.charlie.net",
+				"",
+			),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			l, _ := ParseLossless([]byte(tc.in))
+			checkDiff(t, "lossless round trip", string(l.MarshalPSL()), tc.in)
+		})
+	}
+}
+
+func TestParseLosslessRealList(t *testing.T) {
+	bs, err := os.ReadFile("../../../public_suffix_list.dat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, errs := ParseLossless(bs)
+	if len(errs) > 0 {
+		t.Fatalf("ParseLossless failed: %v", errs)
+	}
+	if got := l.MarshalPSL(); string(got) != string(bs) {
+		t.Fatal("lossless round trip of the real list is not byte-identical")
+	}
+}
+
+func TestLosslessEdits(t *testing.T) {
+	in := lines(
+		"// License  ",
+		"",
+		"",
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// Delta : https://delta.net",
+		"  delta.net  ",
+		"",
+		"",
+		"// Bravo : https://bravo.net",
+		"bravo.net",
+		"*.users.bravo.net",
+		"!www.users.bravo.net",
+		"",
+		"// Charlie : https://charlie.net",
+		"charlie.net\t",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+		"",
+	)
+
+	tests := []struct {
+		name string
+		edit func(*List)
+		want string
+	}{
+		{
+			name: "clean",
+			edit: func(l *List) { l.Clean() },
+			want: lines(
+				"// License  ",
+				"",
+				"",
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Bravo : https://bravo.net",
+				"bravo.net",
+				"*.users.bravo.net",
+				"!www.users.bravo.net",
+				"",
+				"// Charlie : https://charlie.net",
+				"charlie.net\t",
+				"",
+				"// Delta : https://delta.net",
+				"  delta.net  ",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+		},
+		{
+			name: "insert_suffix",
+			edit: func(l *List) {
+				b := findSuffixBlock(findSection(l, "PRIVATE DOMAINS"), "Charlie")
+				if err := l.InsertSuffix(b, "app.charlie.net"); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: lines(
+				"// License  ",
+				"",
+				"",
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Delta : https://delta.net",
+				"  delta.net  ",
+				"",
+				"",
+				"// Bravo : https://bravo.net",
+				"bravo.net",
+				"*.users.bravo.net",
+				"!www.users.bravo.net",
+				"",
+				"// Charlie : https://charlie.net",
+				"charlie.net\t",
+				"app.charlie.net",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+		},
+		{
+			name: "remove_block",
+			edit: func(l *List) {
+				if _, err := l.RemoveEntity("Bravo"); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: lines(
+				"// License  ",
+				"",
+				"",
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Delta : https://delta.net",
+				"  delta.net  ",
+				"",
+				"// Charlie : https://charlie.net",
+				"charlie.net\t",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			l, errs := ParseLossless([]byte(in))
+			if len(errs) > 0 {
+				t.Fatalf("ParseLossless failed: %v", errs)
+			}
+			tc.edit(l)
+			checkDiff(t, "list after edit", string(l.MarshalPSL()), tc.want)
+		})
+	}
+}
+
+func TestParseLosslessColumns(t *testing.T) {
+	in := lines(
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// Bravo",
+		"  bravo..net",
+		"\t*.charlie.net  ",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+	)
+	l, errs := ParseLossless([]byte(in))
+
+	got := errorStrings(errs)
+	want := []string{
+		`line 4:3-12: invalid suffix "bravo..net": label 2 is empty`,
+	}
+	checkDiff(t, "parse errors", got, want)
+
+	wc := BlocksOfType[*Wildcard](l)
+	if len(wc) != 1 {
+		t.Fatalf("got %d wildcards, want 1", len(wc))
+	}
+	if got, want := wc[0].LocationString(), "line 5:2-14"; got != want {
+		t.Errorf("wildcard location is %q, want %q", got, want)
+	}
+	if got, want := BlocksOfType[*Section](l)[0].LocationString(), "lines 1:1-7:28"; got != want {
+		t.Errorf("section location is %q, want %q", got, want)
+	}
+}
+
+// lines joins its arguments with newlines.
+func lines(ls ...string) string {
+	return strings.Join(ls, "\n")
+}
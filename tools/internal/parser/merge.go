@@ -0,0 +1,508 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/publicsuffix/list/tools/internal/domain"
+)
+
+// MergeResult is the result of a three-way merge of PSL files.
+type MergeResult struct {
+	// List is the merged list. Where there are conflicts, List
+	// contains the version of the conflicting blocks from our side,
+	// except for changes that only their side made.
+	List *List
+	// Conflicts are the changes that could not be merged.
+	Conflicts []MergeConflict
+}
+
+// MergeConflict is a conflict between changes made on both sides of a
+// three-way merge.
+type MergeConflict struct {
+	// Reason describes the conflict.
+	Reason string
+	// Ours and Theirs are the conflicting blocks from each side of
+	// the merge. One of them is nil if that side has nothing at the
+	// position of the conflict, for example because it deleted a
+	// block that the other side edited.
+	//
+	// Ours is part of the merged list if it's not nil. Otherwise,
+	// Theirs is part of the merged list.
+	Ours, Theirs Block
+}
+
+// Merge merges the changes made from base to theirs into ours, and
+// returns the merged list.
+//
+// The merge works on the structure of the lists rather than on lines
+// of text: suffix blocks are matched by entity name, and suffixes and
+// wildcards by domain. Changes to different suffix blocks always
+// merge, as do additions and removals of suffixes in the same suffix
+// block. Only true semantic conflicts are reported, such as
+// maintainer information edited differently on both sides, or a
+// suffix moved to different suffix blocks on each side.
+//
+// Merge reuses and modifies the blocks of ours and theirs. The merged
+// list is not cleaned, callers usually want to call Clean on it to
+// put new blocks and suffixes in their sorted positions.
+func Merge(base, ours, theirs *List) *MergeResult {
+	m := &merger{}
+	// Merging edits ours and theirs, so record where suffixes were
+	// before that.
+	baseOwners, oursOwners, theirsOwners := entryOwners(base), entryOwners(ours), entryOwners(theirs)
+	ours.Blocks = m.mergeSeq(base.Blocks, ours.Blocks, theirs.Blocks)
+	m.checkMoves(ours, baseOwners, oursOwners, theirsOwners)
+	return &MergeResult{
+		List:      ours,
+		Conflicts: m.conflicts,
+	}
+}
+
+// MarshalPSL returns the merged list serialized to standard PSL text
+// format, with git-style conflict markers around conflicting blocks.
+func (r *MergeResult) MarshalPSL() []byte {
+	at := map[Block]*MergeConflict{}
+	for i, c := range r.Conflicts {
+		if c.Ours != nil {
+			at[c.Ours] = &r.Conflicts[i]
+		} else {
+			at[c.Theirs] = &r.Conflicts[i]
+		}
+	}
+	var ret bytes.Buffer
+	writeMergedPSL(&ret, r.List, at)
+	return ret.Bytes()
+}
+
+// writeMergedPSL is like writeBlockPSL, but writes the conflicts in at
+// with conflict markers.
+func writeMergedPSL(w io.Writer, b Block, at map[Block]*MergeConflict) {
+	if c := at[b]; c != nil {
+		io.WriteString(w, "<<<<<<< ours\n")
+		if c.Ours != nil {
+			writeBlockPSL(w, c.Ours)
+		}
+		io.WriteString(w, "=======\n")
+		if c.Theirs != nil {
+			writeBlockPSL(w, c.Theirs)
+		}
+		io.WriteString(w, ">>>>>>> theirs\n")
+		return
+	}
+
+	switch v := b.(type) {
+	case *List:
+		for i, child := range v.Blocks {
+			if i > 0 {
+				io.WriteString(w, "\n")
+			}
+			writeMergedPSL(w, child, at)
+		}
+	case *Section:
+		fmt.Fprintf(w, "// ===BEGIN %s===\n", v.Name)
+		for _, child := range v.Blocks {
+			io.WriteString(w, "\n")
+			writeMergedPSL(w, child, at)
+		}
+		fmt.Fprintf(w, "\n// ===END %s===\n", v.Name)
+	case *Group:
+		for i, child := range v.Blocks {
+			if i > 0 {
+				io.WriteString(w, "\n")
+			}
+			writeMergedPSL(w, child, at)
+		}
+	case *Suffixes:
+		for _, child := range v.Blocks {
+			writeMergedPSL(w, child, at)
+		}
+	default:
+		writeBlockPSL(w, b)
+	}
+}
+
+// merger is the state of a three-way merge.
+type merger struct {
+	conflicts []MergeConflict
+}
+
+func (m *merger) conflict(ours, theirs Block, reason string, args ...any) {
+	m.conflicts = append(m.conflicts, MergeConflict{
+		Reason: fmt.Sprintf(reason, args...),
+		Ours:   ours,
+		Theirs: theirs,
+	})
+}
+
+// mergeSeq merges the changes from base to theirs into ours, where
+// all three are the children of corresponding blocks.
+//
+// Blocks are matched across versions by mergeKey. The result follows
+// the order of ours, with blocks added by theirs inserted after the
+// block that precedes them in theirs.
+func (m *merger) mergeSeq(base, ours, theirs []Block) []Block {
+	var (
+		_, baseByKey            = keyBlocks(base)
+		oursKeys, oursByKey     = keyBlocks(ours)
+		theirsKeys, theirsByKey = keyBlocks(theirs)
+		ret                     []Block
+		retKeys                 = map[string]bool{}
+	)
+
+	for _, k := range oursKeys {
+		o := oursByKey[k]
+		b, inBase := baseByKey[k]
+		t, inTheirs := theirsByKey[k]
+		switch {
+		case inTheirs:
+			if !inBase {
+				b = nil
+			}
+			ret = append(ret, m.mergeBlock(b, o, t))
+		case !inBase:
+			// Added by us.
+			ret = append(ret, o)
+		case blockText(o) == blockText(b):
+			// Deleted by them, unchanged by us.
+			continue
+		default:
+			ret = append(ret, o)
+			m.conflict(o, nil, "%s was changed in ours and deleted in theirs", describeBlock(o))
+		}
+		retKeys[k] = true
+	}
+
+	for i, tk := range theirsKeys {
+		if retKeys[tk] {
+			continue
+		}
+		t := theirsByKey[tk]
+		if b, inBase := baseByKey[tk]; inBase {
+			if _, inOurs := oursByKey[tk]; !inOurs {
+				if blockText(t) == blockText(b) {
+					// Deleted by us, unchanged by them.
+					continue
+				}
+				m.conflict(nil, t, "%s was deleted in ours and changed in theirs", describeBlock(t))
+			}
+		}
+
+		// Added by them, insert after the nearest preceding block
+		// that's also in the result.
+		idx := 0
+		for j := i - 1; j >= 0; j-- {
+			if !retKeys[theirsKeys[j]] {
+				continue
+			}
+			prev := theirsByKey[theirsKeys[j]]
+			if pos := slices.IndexFunc(ret, func(b Block) bool { return mergeKeyEqual(b, prev) }); pos >= 0 {
+				idx = pos + 1
+				break
+			}
+		}
+		ret = slices.Insert(ret, idx, t)
+		retKeys[tk] = true
+	}
+
+	return ret
+}
+
+// mergeBlock merges the changes from base to theirs into ours, for
+// blocks with the same mergeKey. base is nil if both sides added the
+// block.
+func (m *merger) mergeBlock(base, ours, theirs Block) Block {
+	oursText, theirsText := blockText(ours), blockText(theirs)
+	switch {
+	case oursText == theirsText:
+		return ours
+	case base != nil && blockText(base) == oursText:
+		return theirs
+	case base != nil && blockText(base) == theirsText:
+		return ours
+	}
+
+	switch o := ours.(type) {
+	case *List:
+		o.Blocks = m.mergeSeq(children(base), o.Blocks, theirs.(*List).Blocks)
+	case *Section:
+		o.Blocks = m.mergeSeq(children(base), o.Blocks, theirs.(*Section).Blocks)
+	case *Group:
+		o.Blocks = m.mergeSeq(children(base), o.Blocks, theirs.(*Group).Blocks)
+	case *Suffixes:
+		b, _ := base.(*Suffixes)
+		return m.mergeSuffixes(b, o, theirs.(*Suffixes))
+	}
+	return ours
+}
+
+// mergeSuffixes merges the changes from base to theirs into ours, for
+// corresponding suffix blocks. base is nil if both sides added the
+// block.
+//
+// One side's version of the block is used as a template, and the
+// other side's suffix changes are applied to it. The template is the
+// side that edited the block's comments, if any.
+func (m *merger) mergeSuffixes(base, ours, theirs *Suffixes) *Suffixes {
+	if base == nil {
+		base = &Suffixes{}
+	}
+	baseComments, oursComments, theirsComments := commentText(base), commentText(ours), commentText(theirs)
+
+	template, other := ours, theirs
+	if oursComments == baseComments && theirsComments != baseComments {
+		template, other = theirs, ours
+	}
+	applySuffixChanges(template, base, other)
+
+	if oursComments != baseComments && theirsComments != baseComments && oursComments != theirsComments {
+		oursHeader, ok1 := firstComment(ours)
+		theirsHeader, ok2 := firstComment(theirs)
+		if ok1 && ok2 && commentText(withoutFirst(ours)) == commentText(withoutFirst(theirs)) {
+			m.conflict(oursHeader, theirsHeader, "maintainer information of suffix block %q was changed in both ours and theirs", ours.Info.Name)
+		} else {
+			m.conflict(template, theirs, "comments of suffix block %q were changed in both ours and theirs", ours.Info.Name)
+		}
+	}
+	return template
+}
+
+// applySuffixChanges applies the suffix and wildcard changes made from
+// base to other onto template.
+func applySuffixChanges(template, base, other *Suffixes) {
+	baseEntries, otherEntries := entriesByKey(base), entriesByKey(other)
+
+	// Removals.
+	template.Blocks = slices.DeleteFunc(template.Blocks, func(b Block) bool {
+		k, ok := entryKey(b)
+		if !ok {
+			return false
+		}
+		_, inBase := baseEntries[k]
+		_, inOther := otherEntries[k]
+		return inBase && !inOther
+	})
+
+	// Exception changes.
+	for _, w := range BlocksOfType[*Wildcard](template) {
+		k, _ := entryKey(w)
+		ow, ok := otherEntries[k].(*Wildcard)
+		if !ok {
+			continue
+		}
+		var baseExc []domain.Label
+		if bw, ok := baseEntries[k].(*Wildcard); ok {
+			baseExc = bw.Exceptions
+		}
+		for _, exc := range ow.Exceptions {
+			if !slices.ContainsFunc(baseExc, exc.Equal) && !slices.ContainsFunc(w.Exceptions, exc.Equal) {
+				w.Exceptions = append(w.Exceptions, exc)
+			}
+		}
+		w.Exceptions = slices.DeleteFunc(w.Exceptions, func(exc domain.Label) bool {
+			return slices.ContainsFunc(baseExc, exc.Equal) && !slices.ContainsFunc(ow.Exceptions, exc.Equal)
+		})
+	}
+
+	// Additions, placed after the entry that precedes them in other.
+	templateEntries := entriesByKey(template)
+	var prevKey string
+	for _, b := range other.Blocks {
+		k, ok := entryKey(b)
+		if !ok {
+			continue
+		}
+		_, inBase := baseEntries[k]
+		_, inTemplate := templateEntries[k]
+		if !inBase && !inTemplate {
+			idx := 0
+			if _, ok := firstComment(template); ok {
+				idx = 1
+			}
+			if prev, ok := templateEntries[prevKey]; ok {
+				idx = slices.Index(template.Blocks, prev) + 1
+			}
+			template.Blocks = slices.Insert(template.Blocks, idx, b)
+			templateEntries[k] = b
+		}
+		if _, ok := templateEntries[k]; ok {
+			prevKey = k
+		}
+	}
+}
+
+// checkMoves reports suffixes and wildcards of the merged list that
+// ours and theirs moved or added to different suffix blocks, given the
+// owners of suffixes in each version as returned by entryOwners.
+func (m *merger) checkMoves(merged *List, baseOwners, oursOwners, theirsOwners map[string]string) {
+	for _, block := range BlocksOfType[*Suffixes](merged) {
+		for _, child := range block.Blocks {
+			k, ok := entryKey(child)
+			if !ok {
+				continue
+			}
+			baseOwner, oursOwner, theirsOwner := baseOwners[k], oursOwners[k], theirsOwners[k]
+			if oursOwner == "" || theirsOwner == "" || oursOwner == theirsOwner || oursOwner == baseOwner || theirsOwner == baseOwner {
+				continue
+			}
+
+			var reason string
+			if baseOwner == "" {
+				reason = fmt.Sprintf("%q was added to %q in ours and to %q in theirs", k, oursOwner, theirsOwner)
+			} else {
+				reason = fmt.Sprintf("%q was moved from %q to %q in ours and to %q in theirs", k, baseOwner, oursOwner, theirsOwner)
+			}
+			switch block.Info.Name {
+			case oursOwner:
+				m.conflicts = append(m.conflicts, MergeConflict{Reason: reason, Ours: child})
+			case theirsOwner:
+				m.conflicts = append(m.conflicts, MergeConflict{Reason: reason, Theirs: child})
+			}
+		}
+	}
+}
+
+// entryOwners returns the entity name of the suffix block that
+// contains each suffix and wildcard of l, by entryKey.
+func entryOwners(l *List) map[string]string {
+	ret := map[string]string{}
+	for _, block := range BlocksOfType[*Suffixes](l) {
+		for _, child := range block.Blocks {
+			if k, ok := entryKey(child); ok {
+				if _, seen := ret[k]; !seen {
+					ret[k] = block.Info.Name
+				}
+			}
+		}
+	}
+	return ret
+}
+
+// mergeKey returns the identity of b for matching blocks across
+// versions of a list.
+func mergeKey(b Block) string {
+	switch v := b.(type) {
+	case *Section:
+		return "section:" + v.Name
+	case *Group:
+		return "group:" + v.Owner
+	case *Suffixes:
+		return "block:" + v.Info.Name
+	case *Comment:
+		return "comment:" + strings.Join(v.Text, "\n")
+	default:
+		if k, ok := entryKey(b); ok {
+			return "entry:" + k
+		}
+		panic("unknown ast node")
+	}
+}
+
+func mergeKeyEqual(a, b Block) bool {
+	return mergeKey(a) == mergeKey(b)
+}
+
+// keyBlocks returns the mergeKeys of blocks, in order, and blocks by
+// key. Repeated keys get a counter appended to keep them distinct.
+func keyBlocks(blocks []Block) ([]string, map[string]Block) {
+	var (
+		keys  = make([]string, 0, len(blocks))
+		byKey = map[string]Block{}
+	)
+	for _, b := range blocks {
+		k := uniqueKey(byKey, mergeKey(b))
+		keys = append(keys, k)
+		byKey[k] = b
+	}
+	return keys, byKey
+}
+
+func uniqueKey(existing map[string]Block, k string) string {
+	ret := k
+	for i := 2; ; i++ {
+		if _, ok := existing[ret]; !ok {
+			return ret
+		}
+		ret = fmt.Sprintf("%s#%d", k, i)
+	}
+}
+
+// entryKey returns the PSL text of a suffix or wildcard, not including
+// wildcard exceptions.
+func entryKey(b Block) (string, bool) {
+	switch v := b.(type) {
+	case *Suffix:
+		return v.Domain.String(), true
+	case *Wildcard:
+		return "*." + v.Domain.String(), true
+	}
+	return "", false
+}
+
+func entriesByKey(s *Suffixes) map[string]Block {
+	ret := map[string]Block{}
+	for _, b := range s.Blocks {
+		if k, ok := entryKey(b); ok {
+			ret[k] = b
+		}
+	}
+	return ret
+}
+
+// blockText returns b in PSL format, for comparing versions of a
+// block.
+func blockText(b Block) string {
+	var ret strings.Builder
+	writeBlockPSL(&ret, b)
+	return ret.String()
+}
+
+// commentText returns the text of the comments in s, for comparing
+// versions of a suffix block.
+func commentText(s *Suffixes) string {
+	var ret strings.Builder
+	for _, c := range BlocksOfType[*Comment](s) {
+		writeBlockPSL(&ret, c)
+		ret.WriteString("\x00")
+	}
+	return ret.String()
+}
+
+func firstComment(s *Suffixes) (*Comment, bool) {
+	if len(s.Blocks) == 0 {
+		return nil, false
+	}
+	c, ok := s.Blocks[0].(*Comment)
+	return c, ok
+}
+
+// withoutFirst returns a shallow copy of s without its first child.
+func withoutFirst(s *Suffixes) *Suffixes {
+	return &Suffixes{Blocks: s.Blocks[1:]}
+}
+
+func children(b Block) []Block {
+	if b == nil {
+		return nil
+	}
+	return b.Children()
+}
+
+func describeBlock(b Block) string {
+	switch v := b.(type) {
+	case *Section:
+		return fmt.Sprintf("section %q", v.Name)
+	case *Group:
+		return fmt.Sprintf("group %q", v.Owner)
+	case *Suffixes:
+		return fmt.Sprintf("suffix block %q", v.Info.Name)
+	case *Comment:
+		return fmt.Sprintf("comment %q", v.Text[0])
+	default:
+		k, _ := entryKey(b)
+		return fmt.Sprintf("%q", k)
+	}
+}
@@ -0,0 +1,244 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	// textLines is like lines, but terminates the last line too.
+	textLines := func(ls ...string) string {
+		return lines(ls...) + "\n"
+	}
+
+	// Most tests edit the private section of this list.
+	base := func(private ...string) string {
+		return textLines(
+			"// ===BEGIN ICANN DOMAINS===",
+			"",
+			"// com : https://www.iana.org/domains/root/db/com.html",
+			"com",
+			"",
+			"// ===END ICANN DOMAINS===",
+			"",
+			"// ===BEGIN PRIVATE DOMAINS===",
+			"",
+		) + strings.Join(private, "\n") + textLines(
+			"",
+			"// ===END PRIVATE DOMAINS===",
+		)
+	}
+	alpha := textLines(
+		"// Alpha : https://alpha.com",
+		"// Submitted by Alpha <admin@alpha.com>",
+		"alpha.com",
+		"*.users.alpha.com",
+		"!www.users.alpha.com",
+	)
+	charlie := textLines(
+		"// Charlie : https://charlie.com",
+		"// Submitted by Charlie <admin@charlie.com>",
+		"charlie.com",
+	)
+	echo := textLines(
+		"// Echo : https://echo.com",
+		"// Submitted by Echo <admin@echo.com>",
+		"echo.com",
+	)
+	block := func(name string, suffixes ...string) string {
+		return textLines(
+			"// "+name+" : https://"+strings.ToLower(name)+".com",
+			"// Submitted by "+name+" <admin@"+strings.ToLower(name)+".com>",
+		) + textLines(suffixes...)
+	}
+
+	tests := []struct {
+		name          string
+		base          string
+		ours          string
+		theirs        string
+		want          string
+		wantConflicts []string
+	}{
+		{
+			name:   "new_blocks_at_same_position",
+			base:   base(alpha, charlie),
+			ours:   base(alpha, block("Bravo", "bravo.com"), charlie),
+			theirs: base(alpha, block("Baker", "baker.com"), charlie),
+			want:   base(alpha, block("Baker", "baker.com"), block("Bravo", "bravo.com"), charlie),
+		},
+		{
+			name:   "new_block_at_end",
+			base:   base(alpha, charlie),
+			ours:   base(alpha, block("Bravo", "bravo.com"), charlie),
+			theirs: base(alpha, charlie, echo),
+			want:   base(alpha, block("Bravo", "bravo.com"), charlie, echo),
+		},
+		{
+			name:   "suffixes_added_and_removed_in_same_block",
+			base:   base(block("Bravo", "bravo.com", "a.bravo.com", "c.bravo.com")),
+			ours:   base(block("Bravo", "bravo.com", "a.bravo.com", "b.bravo.com", "c.bravo.com")),
+			theirs: base(block("Bravo", "bravo.com", "c.bravo.com", "d.bravo.com")),
+			want:   base(block("Bravo", "bravo.com", "b.bravo.com", "c.bravo.com", "d.bravo.com")),
+		},
+		{
+			name:   "same_change_on_both_sides",
+			base:   base(alpha),
+			ours:   base(alpha, charlie),
+			theirs: base(alpha, charlie),
+			want:   base(alpha, charlie),
+		},
+		{
+			name: "wildcard_exceptions",
+			base: base(alpha),
+			ours: base(textLines(
+				"// Alpha : https://alpha.com",
+				"// Submitted by Alpha <admin@alpha.com>",
+				"alpha.com",
+				"*.users.alpha.com",
+				"!api.users.alpha.com",
+				"!www.users.alpha.com",
+			)),
+			theirs: base(textLines(
+				"// Alpha : https://alpha.com",
+				"// Submitted by Alpha <admin@alpha.com>",
+				"alpha.com",
+				"*.users.alpha.com",
+			)),
+			want: base(textLines(
+				"// Alpha : https://alpha.com",
+				"// Submitted by Alpha <admin@alpha.com>",
+				"alpha.com",
+				"*.users.alpha.com",
+				"!api.users.alpha.com",
+			)),
+		},
+		{
+			name: "header_edit_and_suffix_addition",
+			base: base(charlie),
+			ours: base(textLines(
+				"// Charlie : https://charlie.com",
+				"// Submitted by Charlie Jr <charlie@charlie.com>",
+				"charlie.com",
+			)),
+			theirs: base(block("Charlie", "charlie.com", "cdn.charlie.com")),
+			want: base(textLines(
+				"// Charlie : https://charlie.com",
+				"// Submitted by Charlie Jr <charlie@charlie.com>",
+				"charlie.com",
+				"cdn.charlie.com",
+			)),
+		},
+		{
+			name: "header_edited_on_both_sides",
+			base: base(charlie),
+			ours: base(textLines(
+				"// Charlie : https://charlie.com",
+				"// Submitted by Charlie Jr <charlie@charlie.com>",
+				"charlie.com",
+				"a.charlie.com",
+			)),
+			theirs: base(textLines(
+				"// Charlie : https://charlie.org",
+				"// Submitted by Charlie <admin@charlie.com>",
+				"charlie.com",
+				"b.charlie.com",
+			)),
+			want: base(textLines(
+				"<<<<<<< ours",
+				"// Charlie : https://charlie.com",
+				"// Submitted by Charlie Jr <charlie@charlie.com>",
+				"=======",
+				"// Charlie : https://charlie.org",
+				"// Submitted by Charlie <admin@charlie.com>",
+				">>>>>>> theirs",
+				"charlie.com",
+				"a.charlie.com",
+				"b.charlie.com",
+			)),
+			wantConflicts: []string{
+				`maintainer information of suffix block "Charlie" was changed in both ours and theirs`,
+			},
+		},
+		{
+			name:   "suffix_moved_to_different_blocks",
+			base:   base(alpha, charlie, echo),
+			ours:   base(alpha, block("Charlie", "charlie.com", "shared.alpha.com"), echo),
+			theirs: base(alpha, charlie, block("Echo", "echo.com", "shared.alpha.com")),
+			want: base(alpha, textLines(
+				"// Charlie : https://charlie.com",
+				"// Submitted by Charlie <admin@charlie.com>",
+				"<<<<<<< ours",
+				"shared.alpha.com",
+				"=======",
+				">>>>>>> theirs",
+				"charlie.com",
+			), textLines(
+				"// Echo : https://echo.com",
+				"// Submitted by Echo <admin@echo.com>",
+				"<<<<<<< ours",
+				"=======",
+				"shared.alpha.com",
+				">>>>>>> theirs",
+				"echo.com",
+			)),
+			wantConflicts: []string{
+				`"shared.alpha.com" was added to "Charlie" in ours and to "Echo" in theirs`,
+				`"shared.alpha.com" was added to "Charlie" in ours and to "Echo" in theirs`,
+			},
+		},
+		{
+			name:   "suffix_moved_on_one_side",
+			base:   base(block("Alpha", "alpha.com", "moved.alpha.com"), charlie),
+			ours:   base(block("Alpha", "alpha.com"), block("Charlie", "charlie.com", "moved.alpha.com")),
+			theirs: base(block("Alpha", "alpha.com", "moved.alpha.com", "new.alpha.com"), charlie),
+			want:   base(block("Alpha", "alpha.com", "new.alpha.com"), block("Charlie", "moved.alpha.com", "charlie.com")),
+		},
+		{
+			name:   "block_deleted_and_changed",
+			base:   base(alpha, charlie),
+			ours:   base(alpha),
+			theirs: base(alpha, block("Charlie", "charlie.com", "new.charlie.com")),
+			want: base(alpha, textLines(
+				"<<<<<<< ours",
+				"=======",
+				"// Charlie : https://charlie.com",
+				"// Submitted by Charlie <admin@charlie.com>",
+				"charlie.com",
+				"new.charlie.com",
+				">>>>>>> theirs",
+			)),
+			wantConflicts: []string{
+				`suffix block "Charlie" was deleted in ours and changed in theirs`,
+			},
+		},
+		{
+			name:   "block_deleted_and_unchanged",
+			base:   base(alpha, charlie),
+			ours:   base(alpha, block("Charlie", "charlie.com", "new.charlie.com")),
+			theirs: base(charlie),
+			want:   base(block("Charlie", "charlie.com", "new.charlie.com")),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			parse := func(s string) *List {
+				l, errs := Parse([]byte(s))
+				for _, err := range errs {
+					t.Fatalf("parse error: %v", err)
+				}
+				return l
+			}
+			res := Merge(parse(tc.base), parse(tc.ours), parse(tc.theirs))
+			res.List.Clean()
+
+			var gotConflicts []string
+			for _, c := range res.Conflicts {
+				gotConflicts = append(gotConflicts, c.Reason)
+			}
+			checkDiff(t, "Merge conflicts", gotConflicts, tc.wantConflicts)
+			checkDiff(t, "Merge output", string(res.MarshalPSL()), tc.want)
+		})
+	}
+}
@@ -1,9 +1,52 @@
 package parser
 
 import (
+	"fmt"
+	"maps"
 	"net/mail"
 	"net/url"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
+)
+
+// Suffix block headers have a structured format that is both
+// readable by humans and exactly parseable by machines. A structured
+// header consists of the following lines, in order:
+//
+//	<entity name>[ : <url>]          exactly one, required (1)
+//	<url>                            zero or more
+//	Submitted by <name> <<email>>    zero or more
+//	Requested: <YYYY-MM-DD>          optional
+//	PR: #<number>                    optional
+//	Note: <text>                     zero or more
+//	psl:allow <check> reason="..."   zero or more (2)
+//
+// (1) If the entity name itself contains " : ", the URL must go on a
+// separate line instead.
+//
+// (2) See suppress.go.
+//
+// For example:
+//
+//	// Example Ltd : https://example.com
+//	// Submitted by Jane Doe <jane@example.com>
+//	// Requested: 2024-06-01
+//	// PR: #1234
+//	// Note: Also covers example.net, see below.
+//
+// Headers that don't follow this format are still accepted, and
+// extractMaintainerInfo extracts what information it can from them
+// heuristically. MigrateHeaders rewrites such headers into the
+// structured format, where that is possible without losing
+// information.
+
+// Field prefixes for the optional lines of a structured header.
+const (
+	requestedPrefix = "Requested: "
+	prPrefix        = "PR: #"
+	notePrefix      = "Note: "
 )
 
 // extractMaintainerInfo extracts structured maintainer metadata from
@@ -13,11 +56,31 @@ func extractMaintainerInfo(comment *Comment) MaintainerInfo {
 		return MaintainerInfo{MachineEditable: true}
 	}
 
+	ret, ok := parseStructuredHeader(comment.Text)
+	if !ok {
+		ret = parseFreeformHeader(comment.Text)
+	}
+
+	// The header parsers don't know where lines are, fill in the
+	// location of annotations afterwards.
+	n := 0
+	for i, line := range comment.Text {
+		if isSuppression(line) && n < len(ret.Suppressions) {
+			ret.Suppressions[n].SourceRange = commentLineRange(comment, i)
+			n++
+		}
+	}
+	return ret
+}
+
+// parseFreeformHeader extracts as much maintainer metadata as it can
+// from lines, which are a suffix block header in any of the many
+// formats that have historically been used in the PSL.
+func parseFreeformHeader(lines []string) MaintainerInfo {
 	var (
 		ret = MaintainerInfo{
 			MachineEditable: true,
 		}
-		lines             = comment.Text
 		firstUnusableLine = -1
 	)
 
@@ -25,7 +88,7 @@ func extractMaintainerInfo(comment *Comment) MaintainerInfo {
 	// form. Handle that first, then scan through the rest of the
 	// comment to find any further stuff.
 	name, siteURL, email, ok := splitNameish(lines[0])
-	if ok {
+	if ok && !isSuppression(lines[0]) {
 		ret.Name = name
 		if siteURL != nil {
 			ret.URLs = append(ret.URLs, siteURL)
@@ -37,14 +100,21 @@ func extractMaintainerInfo(comment *Comment) MaintainerInfo {
 	}
 
 	// Aside from the special first line, remaining lines could be
-	// maintainer emails in a few formats, or URLs, or something
-	// else. We accumulate everything we can parse, but also keep
-	// track of whether the information is laid out such that we could
-	// write the information back out without data loss (although not
-	// necessarily in the exact same format).
+	// maintainer emails in a few formats, or URLs, or structured
+	// header fields, or something else. We accumulate everything we
+	// can parse, but also keep track of whether the information is
+	// laid out such that we could write the information back out
+	// without data loss (although not necessarily in the exact same
+	// format).
 	for i, line := range lines {
 		lineUsed := false
-		if emails := getSubmitters(line); len(emails) > 0 {
+		if isSuppression(line) {
+			sup, _ := parseSuppression(line)
+			ret.Suppressions = append(ret.Suppressions, sup)
+			lineUsed = true
+		} else if parseHeaderField(line, &ret) {
+			lineUsed = true
+		} else if emails := getSubmitters(line); len(emails) > 0 {
 			ret.Maintainers = append(ret.Maintainers, emails...)
 			lineUsed = true
 		} else if email, err := mail.ParseAddress(line); err == nil {
@@ -73,6 +143,299 @@ func extractMaintainerInfo(comment *Comment) MaintainerInfo {
 	return ret
 }
 
+// parseStructuredHeader parses lines as a structured suffix block
+// header. It returns ok=false if lines don't conform exactly to the
+// structured header format.
+func parseStructuredHeader(lines []string) (ret MaintainerInfo, ok bool) {
+	if len(lines) == 0 {
+		return MaintainerInfo{}, false
+	}
+	orig := lines
+	ret.MachineEditable = true
+
+	// Entity name, with an optional URL. Some entity names contain
+	// " : " themselves, so only the last one can separate a URL.
+	ret.Name = lines[0]
+	if idx := strings.LastIndex(lines[0], " : "); idx >= 0 {
+		if u := getURL(lines[0][idx+3:]); u != nil {
+			ret.Name = lines[0][:idx]
+			ret.URLs = append(ret.URLs, u)
+		}
+	}
+	if !isUnambiguousName(ret.Name) {
+		return MaintainerInfo{}, false
+	}
+	lines = lines[1:]
+
+	// Additional URLs.
+	for len(lines) > 0 {
+		u := getURL(lines[0])
+		if u == nil {
+			break
+		}
+		ret.URLs = append(ret.URLs, u)
+		lines = lines[1:]
+	}
+
+	// Maintainers, strictly one per line in the canonical form.
+	for len(lines) > 0 {
+		if !strings.HasPrefix(lines[0], "Submitted by ") {
+			break
+		}
+		addrs := getSubmitters(lines[0])
+		if len(addrs) != 1 {
+			return MaintainerInfo{}, false
+		}
+		ret.Maintainers = append(ret.Maintainers, addrs[0])
+		lines = lines[1:]
+	}
+
+	// Optional fields. Only notes may be repeated.
+	if len(lines) > 0 && strings.HasPrefix(lines[0], requestedPrefix) {
+		if !parseHeaderField(lines[0], &ret) {
+			return MaintainerInfo{}, false
+		}
+		lines = lines[1:]
+	}
+	if len(lines) > 0 && strings.HasPrefix(lines[0], prPrefix) {
+		if !parseHeaderField(lines[0], &ret) {
+			return MaintainerInfo{}, false
+		}
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.HasPrefix(lines[0], notePrefix) {
+		if !parseHeaderField(lines[0], &ret) {
+			return MaintainerInfo{}, false
+		}
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && isSuppression(lines[0]) {
+		sup, _ := parseSuppression(lines[0])
+		ret.Suppressions = append(ret.Suppressions, sup)
+		lines = lines[1:]
+	}
+	if len(lines) > 0 {
+		return MaintainerInfo{}, false
+	}
+
+	// Lines can be in the right order and still not be structured,
+	// for example if a URL or email isn't written in its canonical
+	// form. Only accept input that we would write back out exactly.
+	if !slices.Equal(formatMaintainerInfo(ret), orig) {
+		return MaintainerInfo{}, false
+	}
+
+	return ret, true
+}
+
+// isUnambiguousName reports whether name can only be read as an
+// entity name, and not as any other kind of header line.
+func isUnambiguousName(name string) bool {
+	if name == "" || getURL(name) != nil || len(getSubmitters(name)) > 0 || parseHeaderField(name, &MaintainerInfo{}) {
+		return false
+	}
+	if _, err := mail.ParseAddress(name); err == nil {
+		return false
+	}
+	if _, _, _, ok := splitNameish(name); ok {
+		return false
+	}
+	return true
+}
+
+// parseHeaderField tries to parse line as one of the optional
+// "<field>: <value>" lines of a structured header, and if successful
+// records the field's value in inf.
+//
+// Returns whether line was a valid header field.
+func parseHeaderField(line string, inf *MaintainerInfo) bool {
+	if v, ok := strings.CutPrefix(line, requestedPrefix); ok {
+		t, err := time.Parse(time.DateOnly, v)
+		if err != nil || !inf.Requested.IsZero() {
+			return false
+		}
+		inf.Requested = t
+		return true
+	} else if v, ok := strings.CutPrefix(line, prPrefix); ok {
+		pr, err := strconv.Atoi(v)
+		if err != nil || pr <= 0 || strconv.Itoa(pr) != v || inf.PR != 0 {
+			return false
+		}
+		inf.PR = pr
+		return true
+	} else if v, ok := strings.CutPrefix(line, notePrefix); ok {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			return false
+		}
+		inf.Notes = append(inf.Notes, v)
+		return true
+	}
+	return false
+}
+
+// formatMaintainerInfo returns the lines of a suffix block header
+// comment that represents inf.
+//
+// The output is a structured header, unless inf has no name or
+// contains Other lines.
+func formatMaintainerInfo(inf MaintainerInfo) []string {
+	var ret []string
+
+	urls := inf.URLs
+	if inf.Name != "" && len(urls) > 0 && !strings.Contains(inf.Name, " : ") {
+		// First line that looks like "<name> : <url>". Names that
+		// contain " : " themselves would be ambiguous in this form,
+		// so their URLs go on separate lines.
+		ret = append(ret, fmt.Sprintf("%s : %s", inf.Name, urls[0]))
+		urls = urls[1:]
+	} else if inf.Name != "" {
+		// First line that looks like "<name>"
+		ret = append(ret, inf.Name)
+	}
+
+	for _, u := range urls {
+		ret = append(ret, u.String())
+	}
+	for _, m := range inf.Maintainers {
+		// We could use m.String(), but doing that quotes the name,
+		// and ends up inserting a lot of escape chars for non-ascii
+		// names. The mail package can parse unquoted names just fine,
+		// so we prefer the form that is more human readable.
+		emailStr := strings.TrimSpace(fmt.Sprintf("%s <%s>", m.Name, m.Address))
+		ret = append(ret, strings.TrimSpace(fmt.Sprintf("Submitted by %s", emailStr)))
+	}
+	if !inf.Requested.IsZero() {
+		ret = append(ret, requestedPrefix+inf.Requested.Format(time.DateOnly))
+	}
+	if inf.PR != 0 {
+		ret = append(ret, prPrefix+strconv.Itoa(inf.PR))
+	}
+	for _, n := range inf.Notes {
+		ret = append(ret, notePrefix+n)
+	}
+	for _, s := range inf.Suppressions {
+		ret = append(ret, s.Text)
+	}
+	ret = append(ret, inf.Other...)
+	return ret
+}
+
+// MigrateHeaders rewrites the header comment of every suffix block in
+// the private domains section of l into the structured header
+// format, where this can be done without losing information. Headers
+// that are already structured are left untouched. ICANN suffix
+// blocks are not migrated, their headers follow the conventions of
+// the ICANN section instead.
+//
+// Unrecognized header lines that don't look like they contain
+// contact information are kept as a single note, since they are
+// usually one paragraph of prose wrapped over several lines.
+// MigrateHeaders returns an ErrUnstructuredHeader for each suffix
+// block that needs human attention, and leaves those blocks
+// unchanged.
+func (l *List) MigrateHeaders() []error {
+	var errs []error
+	for _, section := range BlocksOfType[*Section](l) {
+		if section.Name != "PRIVATE DOMAINS" {
+			continue
+		}
+		for _, s := range BlocksOfType[*Suffixes](section) {
+			if err := migrateHeader(s); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errs
+}
+
+// migrateHeader rewrites the header comment of s into the structured
+// header format, or returns an error explaining why it cannot.
+func migrateHeader(s *Suffixes) error {
+	var header *Comment
+	if len(s.Blocks) > 0 {
+		header, _ = s.Blocks[0].(*Comment)
+	}
+	if header == nil {
+		return ErrUnstructuredHeader{s, "block has no header comment"}
+	}
+	if _, ok := parseStructuredHeader(header.Text); ok {
+		return nil
+	}
+
+	inf := s.Info
+	if !inf.MachineEditable {
+		return ErrUnstructuredHeader{s, "header mixes recognized and unrecognized lines"}
+	}
+	if inf.Name == "" {
+		return ErrUnstructuredHeader{s, "header has no entity name"}
+	}
+	want := headerWords(header.Text)
+	for _, line := range inf.Other {
+		if looksLikeContactInfo(line) {
+			return ErrUnstructuredHeader{s, fmt.Sprintf("cannot parse header line %q", line)}
+		}
+	}
+	if len(inf.Other) > 0 {
+		inf.Notes = append(inf.Notes, strings.Join(inf.Other, " "))
+		want["note"]++
+	}
+	inf.Other = nil
+
+	// Make sure the new header says exactly what we think it
+	// says. Formatting can introduce ambiguity, for example if the
+	// entity name looks like some other kind of header line.
+	text := formatMaintainerInfo(inf)
+	got, ok := parseStructuredHeader(text)
+	if !ok || got.Compare(&inf) != 0 {
+		return ErrUnstructuredHeader{s, "header information cannot be written in the structured format"}
+	}
+
+	// The free-form parser sometimes has to guess, and its guesses
+	// can drop text or put it in the wrong field. The new header can
+	// move words around and change punctuation, but must otherwise
+	// say the same thing as the old one.
+	if !maps.Equal(headerWords(text), want) {
+		return ErrUnstructuredHeader{s, "header information cannot be migrated without changing its meaning"}
+	}
+
+	// As in rewriteSuffixesMetadata, clear out the source range so
+	// that nothing points at the old header's location.
+	header.SourceRange = SourceRange{}
+	header.Text = text
+	s.Info = got
+	return nil
+}
+
+// headerWords returns the words of a header comment, ignoring case
+// and punctuation that differs between header formats.
+func headerWords(lines []string) map[string]int {
+	ret := map[string]int{}
+	for _, line := range lines {
+		line = headerPunctuation.Replace(strings.ToLower(line))
+		for _, w := range strings.Fields(line) {
+			ret[w]++
+		}
+	}
+	return ret
+}
+
+var headerPunctuation = strings.NewReplacer(
+	":", " ",
+	"：", " ", // fullwidth colon
+	"(", " ",
+	")", " ",
+	"<", " ",
+	">", " ",
+)
+
+// looksLikeContactInfo reports whether line, which is not
+// recognized by the header parser, seems to contain a URL or email
+// address anyway.
+func looksLikeContactInfo(line string) bool {
+	return strings.Contains(line, "://") || strings.Contains(line, "@") || strings.HasPrefix(strings.ToLower(line), submittedBy)
+}
+
 // submittedBy is the conventional text that precedes email contact
 // information in a PSL file. Most PSL entries say "Submitted by", but
 // there are 4 entries that are lowercase, and so we do a
@@ -4,6 +4,7 @@ import (
 	"net/mail"
 	"net/url"
 	"testing"
+	"time"
 )
 
 func TestMetadata(t *testing.T) {
@@ -241,6 +242,67 @@ func TestMetadata(t *testing.T) {
 				MachineEditable: true,
 			},
 		},
+
+		{
+			name: "structured_all_fields",
+			in: comment(0,
+				"DuckCo : https://example.com",
+				"https://example.org",
+				"Submitted by Duck <duck@example.com>",
+				"Submitted by Goat <goat@example.com>",
+				"Requested: 2024-06-01",
+				"PR: #1234",
+				"Note: Duck is in charge",
+				"Note: Goat is not",
+			),
+			want: MaintainerInfo{
+				Name:        "DuckCo",
+				URLs:        urls("https://example.com", "https://example.org"),
+				Maintainers: emails("Duck", "duck@example.com", "Goat", "goat@example.com"),
+				Requested:   time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+				PR:          1234,
+				Notes: []string{
+					"Duck is in charge",
+					"Goat is not",
+				},
+				MachineEditable: true,
+			},
+		},
+
+		{
+			name: "structured_name_with_colon",
+			in: comment(0,
+				"duck : DuckCo Registry Services",
+				"https://example.com",
+			),
+			want: MaintainerInfo{
+				Name:            "duck : DuckCo Registry Services",
+				URLs:            urls("https://example.com"),
+				MachineEditable: true,
+			},
+		},
+
+		{
+			name: "fields_in_freeform_header",
+			in: comment(0,
+				"DuckCo: https://example.com",
+				"PR: #1234",
+				"Submitted by: Duck <duck@example.com>",
+				"Note: Duck is in charge",
+				"Requested: someday",
+			),
+			want: MaintainerInfo{
+				Name:        "DuckCo",
+				URLs:        urls("https://example.com"),
+				Maintainers: emails("Duck", "duck@example.com"),
+				PR:          1234,
+				Notes:       []string{"Duck is in charge"},
+				Other: []string{
+					"Requested: someday",
+				},
+				MachineEditable: true,
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -249,6 +311,178 @@ func TestMetadata(t *testing.T) {
 	}
 }
 
+func TestFreeformHeaderFields(t *testing.T) {
+	// Free-form headers are parsed for the optional fields of
+	// structured headers too, so existing "Note:" and "PR: #" lines
+	// become structured data instead of unrecognized text, wherever
+	// they are in the header.
+	got := extractMaintainerInfo(comment(0,
+		"Oracle Dyn : https://dyn.com/dns/",
+		"Submitted by Gregory Drake <support@dyn.com>",
+		"Note: This is intended to also include customer-oci.com",
+		"PR: #1234",
+	))
+	want := MaintainerInfo{
+		Name:            "Oracle Dyn",
+		URLs:            urls("https://dyn.com/dns/"),
+		Maintainers:     emails("Gregory Drake", "support@dyn.com"),
+		PR:              1234,
+		Notes:           []string{"This is intended to also include customer-oci.com"},
+		MachineEditable: true,
+	}
+	checkDiff(t, "maintainer info", got, want)
+
+	// Fields that don't parse are left as unrecognized text.
+	got = extractMaintainerInfo(comment(0,
+		"DuckCo",
+		"Note:",
+		"PR: #one",
+		"PR: #1234",
+		"PR: #5678",
+	))
+	want = MaintainerInfo{
+		Name:            "DuckCo",
+		PR:              1234,
+		Other:           []string{"Note:", "PR: #one", "PR: #5678"},
+		MachineEditable: false,
+	}
+	checkDiff(t, "maintainer info with bad fields", got, want)
+}
+
+func TestStructuredHeader(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want bool
+	}{
+		{"name_only", []string{"DuckCo"}, true},
+		{"canonical", []string{"DuckCo : https://example.com", "Submitted by Duck <duck@example.com>"}, true},
+		{"no_space_around_colon", []string{"DuckCo:https://example.com"}, false},
+		{"url_in_parens", []string{"DuckCo (https://example.com)"}, false},
+		{"submitted_by_colon", []string{"DuckCo", "Submitted by: Duck <duck@example.com>"}, false},
+		{"two_submitters_one_line", []string{"DuckCo", "Submitted by Duck <duck@example.com> and Goat <goat@example.com>"}, false},
+		{"fields_out_of_order", []string{"DuckCo", "PR: #1234", "Requested: 2024-06-01"}, false},
+		{"bad_date", []string{"DuckCo", "Requested: June 2024"}, false},
+		{"bad_pr", []string{"DuckCo", "PR: #01234"}, false},
+		{"free_text", []string{"DuckCo", "Duck is in charge"}, false},
+		{"missing_name", []string{"Submitted by Duck <duck@example.com>"}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, got := parseStructuredHeader(tc.in)
+			if got != tc.want {
+				t.Errorf("parseStructuredHeader(%q) ok=%v, want %v", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMigrateHeaders(t *testing.T) {
+	in := byteLines(
+		"// ===BEGIN ICANN DOMAINS===",
+		"",
+		"// gh : https://www.iana.org/domains/root/db/gh.html",
+		"// https://www.nic.gh/",
+		"// Although domains directly at second level are not possible at the moment,",
+		"// they have been possible for some time and may come back.",
+		"gh",
+		"",
+		"// ===END ICANN DOMAINS===",
+		"",
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// DuckCo: https://example.com",
+		"// Submitted by: Duck <duck@example.com>",
+		"// Duck is in charge",
+		"example.com",
+		"",
+		"// GoatCo : https://example.net",
+		"// Submitted by Goat <goat@example.net>",
+		"example.net",
+		"",
+		"// LlamaCo",
+		"// See https://example.org/contact for support",
+		"example.org",
+		"",
+		"// OwlCo",
+		"// Submitted by registry <owl@example.info>",
+		"example.info",
+		"",
+		"// SwanCo : https://example.swiss",
+		"// Swan subdomains are handed out to customers,",
+		"// and may be reassigned over time.",
+		"example.swiss",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+		"",
+	)
+	want := byteLines(
+		"// ===BEGIN ICANN DOMAINS===",
+		"",
+		"// gh : https://www.iana.org/domains/root/db/gh.html",
+		"// https://www.nic.gh/",
+		"// Although domains directly at second level are not possible at the moment,",
+		"// they have been possible for some time and may come back.",
+		"gh",
+		"",
+		"// ===END ICANN DOMAINS===",
+		"",
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// DuckCo : https://example.com",
+		"// Submitted by Duck <duck@example.com>",
+		"// Note: Duck is in charge",
+		"example.com",
+		"",
+		"// GoatCo : https://example.net",
+		"// Submitted by Goat <goat@example.net>",
+		"example.net",
+		"",
+		"// LlamaCo",
+		"// See https://example.org/contact for support",
+		"example.org",
+		"",
+		"// OwlCo",
+		"// Submitted by registry <owl@example.info>",
+		"example.info",
+		"",
+		"// SwanCo : https://example.swiss",
+		"// Note: Swan subdomains are handed out to customers, and may be reassigned over time.",
+		"example.swiss",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+		"",
+	)
+
+	l, errs := Parse(in)
+	if len(errs) > 0 {
+		t.Fatalf("Parse failed: %v", errs)
+	}
+	errs = l.MigrateHeaders()
+	var reasons []string
+	for _, err := range errs {
+		reasons = append(reasons, err.(ErrUnstructuredHeader).Reason)
+	}
+	wantReasons := []string{
+		`cannot parse header line "See https://example.org/contact for support"`,
+		"header information cannot be migrated without changing its meaning",
+	}
+	checkDiff(t, "MigrateHeaders errors", reasons, wantReasons)
+
+	got := l.MarshalPSL()
+	checkDiff(t, "MigrateHeaders output", string(got), string(want))
+
+	// Migration is idempotent.
+	l, _ = Parse(got)
+	if errs := l.MigrateHeaders(); len(errs) != len(wantReasons) {
+		t.Errorf("second MigrateHeaders returned %d errors, want %d", len(errs), len(wantReasons))
+	}
+	if again := l.MarshalPSL(); string(again) != string(got) {
+		t.Errorf("second MigrateHeaders changed the output")
+	}
+}
+
 func urls(us ...string) []*url.URL {
 	var ret []*url.URL
 	for _, s := range us {
@@ -0,0 +1,166 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// removedSection is the name of the overlay section that lists
+// entries to remove from a list.
+const removedSection = "REMOVED DOMAINS"
+
+// An Overlay is a set of local changes to a public suffix list, kept
+// in a separate file so that the upstream list can be used unmodified.
+//
+// An overlay file is written in PSL syntax. Suffix blocks in its
+// "ICANN DOMAINS" and "PRIVATE DOMAINS" sections are added to the
+// same section of the list. Entries in its "REMOVED DOMAINS" section
+// are removed from the list: suffixes and wildcards are removed
+// entirely, and a wildcard followed by exceptions removes only those
+// exceptions. For example:
+//
+//	// ===BEGIN PRIVATE DOMAINS===
+//
+//	// Corp : https://corp.example.com
+//	// Submitted by IT <it@corp.example.com>
+//	*.corp.example.com
+//
+//	// ===END PRIVATE DOMAINS===
+//
+//	// ===BEGIN REMOVED DOMAINS===
+//
+//	// We serve our own content on these.
+//	blogspot.com
+//	*.kawasaki.jp
+//	!city.kawasaki.jp
+//
+//	// ===END REMOVED DOMAINS===
+//
+// Comments outside of suffix blocks are ignored.
+//
+// Blocks added by an overlay become part of the list, and must pass
+// the same validation as the rest of the list, except that their
+// contacts may use domains reserved for documentation and private
+// use, such as example.com. Other checks that don't make sense for
+// local additions can be suppressed with psl:allow annotations (see
+// suppress.go).
+type Overlay struct {
+	// Name identifies the overlay, usually the path of its file.
+	Name string
+	// List is the parsed overlay file.
+	List *List
+}
+
+// ParseOverlay parses bs as an overlay file called name.
+//
+// The SourceRanges of the overlay's blocks and parse errors record
+// name as their File, so that once the overlay is applied to a list,
+// errors about its blocks point to the overlay file.
+func ParseOverlay(name string, bs []byte) (*Overlay, []error) {
+	l, errs := parse(bs, name)
+	return &Overlay{Name: name, List: l}, errs
+}
+
+// ApplyOverlay applies the changes in o to l.
+//
+// Removals are applied before additions, so that an overlay can
+// remove an upstream rule and add it back in a suffix block of its
+// own. Added suffix blocks are inserted at their sorted position, by
+// entity name in the PRIVATE DOMAINS section and by TLD in the ICANN
+// DOMAINS section, outside of the gTLD region. The blocks of o become
+// part of l, so o must not be applied to more than one list.
+//
+// ApplyOverlay returns an error and leaves l unchanged if o has
+// suffixes outside of the sections described in Overlay, or removes
+// entries that are not in l. Added rules are not checked against the
+// rest of l, use ValidateOffline on the result to check the combined
+// list.
+func (l *List) ApplyOverlay(o *Overlay) error {
+	type addition struct {
+		section *Section
+		block   *Suffixes
+	}
+	var (
+		removals  []string
+		additions []addition
+		errs      []error
+	)
+	for _, b := range o.List.Blocks {
+		switch v := b.(type) {
+		case *Comment:
+			// Header of the overlay file.
+		case *Section:
+			if v.Name == removedSection {
+				removals = append(removals, overlayRemovals(v)...)
+				continue
+			}
+			if v.Name != "ICANN DOMAINS" && v.Name != "PRIVATE DOMAINS" {
+				errs = append(errs, fmt.Errorf("%s: unknown overlay section %q", v.LocationString(), v.Name))
+				continue
+			}
+			target := findSection(l, v.Name)
+			if target == nil {
+				errs = append(errs, fmt.Errorf("%s: list has no section %q", v.LocationString(), v.Name))
+				continue
+			}
+			for _, block := range BlocksOfType[*Suffixes](v) {
+				additions = append(additions, addition{target, block})
+			}
+		default:
+			errs = append(errs, fmt.Errorf("%s: overlay suffixes must be in a section", b.SrcRange().LocationString()))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	if _, err := l.RemoveSuffixes(removals); err != nil {
+		return fmt.Errorf("applying overlay %s: %w", o.Name, err)
+	}
+	for _, a := range additions {
+		setOverlay(a.block, o.Name)
+		idx := len(a.section.Blocks)
+		switch a.section.Name {
+		case "ICANN DOMAINS":
+			idx = gTLDRegionStart(a.section)
+			if tld, ok := blockTLD(a.block); ok {
+				idx = sortedICANNIndex(a.section, tld)
+			}
+		case "PRIVATE DOMAINS":
+			idx = sortedBlockIndex(a.section, a.block.Info.Name)
+		}
+		a.section.Blocks = slices.Insert(a.section.Blocks, idx, Block(a.block))
+		markChanged(l, a.block)
+	}
+	return nil
+}
+
+// overlayRemovals returns the entries listed in the REMOVED DOMAINS
+// section s of an overlay, in the syntax that RemoveSuffixes expects.
+func overlayRemovals(s *Section) []string {
+	var ret []string
+	for _, b := range BlocksOfType[Block](s) {
+		switch v := b.(type) {
+		case *Suffix:
+			ret = append(ret, v.Domain.String())
+		case *Wildcard:
+			if len(v.Exceptions) == 0 {
+				ret = append(ret, "*."+v.Domain.String())
+			}
+			for _, exc := range v.Exceptions {
+				ret = append(ret, "!"+exc.String()+"."+v.Domain.String())
+			}
+		}
+	}
+	return ret
+}
+
+// setOverlay records that the tree rooted at b was added to a list by
+// the named overlay.
+func setOverlay(b Block, name string) {
+	b.info().overlay = name
+	for _, child := range b.Children() {
+		setOverlay(child, name)
+	}
+}
@@ -0,0 +1,334 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestApplyOverlay(t *testing.T) {
+	upstream := byteLines(
+		"// ===BEGIN ICANN DOMAINS===",
+		"",
+		"// ck",
+		"*.ck",
+		"!www.ck",
+		"",
+		"// com",
+		"com",
+		"",
+		"// ===END ICANN DOMAINS===",
+		"",
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// Bravo : https://bravo.net",
+		"// Submitted by Bravo <psl@bravo.net>",
+		"bravo.net",
+		"*.users.bravo.net",
+		"",
+		"// Delta : https://delta.net",
+		"// Submitted by Delta <psl@delta.net>",
+		"delta.net",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+		"",
+	)
+
+	tests := []struct {
+		name     string
+		overlays []string
+		want     []byte
+		wantErr  bool
+	}{
+		{
+			name: "add_private",
+			overlays: []string{string(byteLines(
+				"// Our internal suffixes.",
+				"",
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Corp : https://corp.example",
+				"// Submitted by IT <it@corp.example>",
+				"*.corp.example",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+			))},
+			want: byteLines(
+				"// ===BEGIN ICANN DOMAINS===",
+				"",
+				"// ck",
+				"*.ck",
+				"!www.ck",
+				"",
+				"// com",
+				"com",
+				"",
+				"// ===END ICANN DOMAINS===",
+				"",
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Bravo : https://bravo.net",
+				"// Submitted by Bravo <psl@bravo.net>",
+				"bravo.net",
+				"*.users.bravo.net",
+				"",
+				"// Corp : https://corp.example",
+				"// Submitted by IT <it@corp.example>",
+				"*.corp.example",
+				"",
+				"// Delta : https://delta.net",
+				"// Submitted by Delta <psl@delta.net>",
+				"delta.net",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+		},
+		{
+			name: "add_icann",
+			overlays: []string{string(byteLines(
+				"// ===BEGIN ICANN DOMAINS===",
+				"",
+				"// co",
+				"co",
+				"",
+				"// ===END ICANN DOMAINS===",
+			))},
+			want: byteLines(
+				"// ===BEGIN ICANN DOMAINS===",
+				"",
+				"// ck",
+				"*.ck",
+				"!www.ck",
+				"",
+				"// co",
+				"co",
+				"",
+				"// com",
+				"com",
+				"",
+				"// ===END ICANN DOMAINS===",
+				"",
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Bravo : https://bravo.net",
+				"// Submitted by Bravo <psl@bravo.net>",
+				"bravo.net",
+				"*.users.bravo.net",
+				"",
+				"// Delta : https://delta.net",
+				"// Submitted by Delta <psl@delta.net>",
+				"delta.net",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+		},
+		{
+			name: "remove_and_add_icann",
+			overlays: []string{string(byteLines(
+				"// ===BEGIN ICANN DOMAINS===",
+				"",
+				"// example",
+				"example",
+				"",
+				"// ===END ICANN DOMAINS===",
+				"",
+				"// ===BEGIN REMOVED DOMAINS===",
+				"",
+				"*.ck",
+				"!www.ck",
+				"",
+				"// Whole block",
+				"delta.net",
+				"",
+				"// ===END REMOVED DOMAINS===",
+			))},
+			want: byteLines(
+				"// ===BEGIN ICANN DOMAINS===",
+				"",
+				"// ck",
+				"*.ck",
+				"",
+				"// com",
+				"com",
+				"",
+				"// example",
+				"example",
+				"",
+				"// ===END ICANN DOMAINS===",
+				"",
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Bravo : https://bravo.net",
+				"// Submitted by Bravo <psl@bravo.net>",
+				"bravo.net",
+				"*.users.bravo.net",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+		},
+		{
+			name: "stacked_overlays",
+			overlays: []string{
+				string(byteLines(
+					"// ===BEGIN PRIVATE DOMAINS===",
+					"",
+					"// Corp : https://corp.example",
+					"// Submitted by IT <it@corp.example>",
+					"*.corp.example",
+					"",
+					"// ===END PRIVATE DOMAINS===",
+				)),
+				string(byteLines(
+					"// ===BEGIN REMOVED DOMAINS===",
+					"",
+					"*.corp.example",
+					"*.users.bravo.net",
+					"",
+					"// ===END REMOVED DOMAINS===",
+				)),
+			},
+			want: byteLines(
+				"// ===BEGIN ICANN DOMAINS===",
+				"",
+				"// ck",
+				"*.ck",
+				"!www.ck",
+				"",
+				"// com",
+				"com",
+				"",
+				"// ===END ICANN DOMAINS===",
+				"",
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Bravo : https://bravo.net",
+				"// Submitted by Bravo <psl@bravo.net>",
+				"bravo.net",
+				"",
+				"// Delta : https://delta.net",
+				"// Submitted by Delta <psl@delta.net>",
+				"delta.net",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+				"",
+			),
+		},
+		{
+			name: "remove_missing",
+			overlays: []string{string(byteLines(
+				"// ===BEGIN REMOVED DOMAINS===",
+				"",
+				"bravo.net",
+				"charlie.net",
+				"",
+				"// ===END REMOVED DOMAINS===",
+			))},
+			wantErr: true,
+		},
+		{
+			name: "unknown_section",
+			overlays: []string{string(byteLines(
+				"// ===BEGIN INTERNAL DOMAINS===",
+				"",
+				"corp.example",
+				"",
+				"// ===END INTERNAL DOMAINS===",
+			))},
+			wantErr: true,
+		},
+		{
+			name: "suffix_outside_section",
+			overlays: []string{string(byteLines(
+				"// Corp",
+				"corp.example",
+			))},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			l, errs := Parse(upstream)
+			if len(errs) > 0 {
+				t.Fatalf("Parse failed: %v", errs)
+			}
+			var err error
+			for _, src := range tc.overlays {
+				o, errs := ParseOverlay("overlay.dat", []byte(src))
+				if len(errs) > 0 {
+					t.Fatalf("ParseOverlay failed: %v", errs)
+				}
+				if err = l.ApplyOverlay(o); err != nil {
+					break
+				}
+			}
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("ApplyOverlay succeeded, want error")
+				}
+				checkDiff(t, "list after failed ApplyOverlay", string(l.MarshalPSL()), string(upstream))
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyOverlay failed: %v", err)
+			}
+			l.Clean()
+			checkDiff(t, "list after ApplyOverlay", string(l.MarshalPSL()), string(tc.want))
+		})
+	}
+}
+
+func TestOverlayOrigin(t *testing.T) {
+	l, errs := Parse(byteLines(
+		"// ===BEGIN ICANN DOMAINS===",
+		"",
+		"// com",
+		"com",
+		"",
+		"// ===END ICANN DOMAINS===",
+		"",
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// Bravo : https://bravo.net",
+		"// Submitted by Bravo <psl@bravo.net>",
+		"bravo.net",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+	))
+	if len(errs) > 0 {
+		t.Fatalf("Parse failed: %v", errs)
+	}
+	o, errs := ParseOverlay("corp.dat", byteLines(
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// Corp : https://corp.example.com",
+		"// Submitted by IT <it@corp.example.com>",
+		"*.corp.example.com",
+		"bravo.net",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+	))
+	if len(errs) > 0 {
+		t.Fatalf("ParseOverlay failed: %v", errs)
+	}
+	if err := l.ApplyOverlay(o); err != nil {
+		t.Fatalf("ApplyOverlay failed: %v", err)
+	}
+
+	origins := func(host string) []string {
+		var ret []string
+		for _, m := range l.MatchingRules(mustParseDomain(host)) {
+			ret = append(ret, m.Text+" "+m.Overlay)
+		}
+		return ret
+	}
+	checkDiff(t, "rules for www.corp.example.com", origins("www.corp.example.com"), []string{"com ", "*.corp.example.com corp.dat"})
+
+	// The combined list is validated as a unit, and errors point to
+	// the file each block came from. Overlay blocks may use
+	// placeholder contacts.
+	checkDiff(t, "ValidateOffline", errorStrings(ValidateOffline(l)), []string{
+		`corp.dat, line 6: duplicate suffix definition for "bravo.net", first definition at line 12`,
+	})
+}
@@ -21,15 +21,66 @@ import (
 // (https://github.com/publicsuffix/list/wiki/Guidelines). A File with
 // errors should not be used to calculate public suffixes for FQDNs.
 func Parse(bs []byte) (*List, []error) {
+	return parse(bs, "")
+}
+
+// parse is Parse, with file recorded in the SourceRange of all blocks
+// and errors.
+func parse(bs []byte, file string) (*List, []error) {
+	p := newParser(bs, file)
+	ret := p.parseTopLevel()
+	return ret, p.errs
+}
+
+// newParser returns a parser for bs, which was read from file.
+func newParser(bs []byte, file string) *parser {
 	lines, errs := normalizeToUTF8Lines(bs)
 	p := &parser{
 		input:     lines,
 		inputLine: 0,
+		file:      file,
+	}
+	for _, err := range errs {
+		p.addError(err)
+	}
+	return p
+}
+
+// ParseLossless is like Parse, but the returned List also remembers
+// the exact source text of bs.
+//
+// The SourceRanges of blocks and errors in the returned List include
+// column information, and MarshalPSL reproduces the original text of
+// all blocks that have not been edited since parsing, including
+// whitespace and blank lines that Parse would discard. An unmodified
+// List marshals to exactly bs.
+//
+// Byte-exact output is only possible for UTF-8 input. For input in
+// other encodings, MarshalPSL behaves as if the list had been parsed
+// with Parse.
+func ParseLossless(bs []byte) (*List, []error) {
+	lines, errs := decodeToUTF8Lines(bs)
+	indents := make([]int, len(lines))
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" {
+			indents[i] = strings.Index(line, trimmed)
+		}
+		lines[i] = trimmed
+	}
+
+	p := &parser{
+		input:     lines,
+		inputLine: 0,
+		indents:   indents,
 	}
 	for _, err := range errs {
 		p.addError(err)
 	}
 	ret := p.parseTopLevel()
+	if isUTF8(bs) {
+		ret.src = newRawSource(ret, bs)
+	}
 	return ret, p.errs
 }
 
@@ -40,6 +91,13 @@ type parser struct {
 	// inputLine is the offset for input[0]. That is, input[0] is line
 	// number inputLine of the source text.
 	inputLine int
+	// indents, if non-nil, is the byte offset of the first
+	// non-whitespace character of each line of the source text. It is
+	// used to attach column information to tokens in lossless mode.
+	indents []int
+	// file is the name of the source file, recorded in the
+	// SourceRange of tokens.
+	file string
 	// peekBuf is a buffer containing zero or one input tokens.
 	peekBuf any
 	// errs are the accumulated parse errors so far.
@@ -112,9 +170,13 @@ func (p *parser) next() (ret any) {
 	// the rest of the function is just to determine what kind of
 	// token to return.
 	src := line{
-		SourceRange: SourceRange{p.inputLine, p.inputLine + 1},
+		SourceRange: SourceRange{FirstLine: p.inputLine, LastLine: p.inputLine + 1, File: p.file},
 		Text:        p.input[0],
 	}
+	if p.indents != nil && src.Text != "" {
+		src.FirstColumn = p.indents[p.inputLine]
+		src.LastColumn = src.FirstColumn + len(src.Text)
+	}
 	p.input = p.input[1:]
 	p.inputLine++
 
@@ -245,6 +307,7 @@ func (p *parser) parseSection() *Section {
 		switch tok := p.peek().(type) {
 		case tokenEOF:
 			p.addError(ErrUnclosedSection{ret})
+			ret.Blocks = p.parseGroups(ret.Blocks)
 			return ret
 		case tokenBlank:
 			p.next()
@@ -264,6 +327,8 @@ func (p *parser) parseSection() *Section {
 				p.addError(ErrMismatchedSection{tok.SourceRange, tok.Name, ret})
 			}
 			ret.SourceRange.LastLine = tok.SourceRange.LastLine
+			ret.SourceRange.LastColumn = tok.SourceRange.LastColumn
+			ret.Blocks = p.parseGroups(ret.Blocks)
 			return ret
 		case tokenSectionUnknown:
 			p.next()
@@ -276,6 +341,87 @@ func (p *parser) parseSection() *Section {
 	}
 }
 
+// parseGroups assembles the managed groups in blocks, which are the
+// contents of a section, into Group blocks.
+//
+// Group markers are comments, and can only be told apart from other
+// comments once the comment has been parsed. So unlike sections,
+// groups are assembled after the fact from a section's blocks.
+func (p *parser) parseGroups(blocks []Block) []Block {
+	var ret []Block
+	for len(blocks) > 0 {
+		m, ok := p.groupMarker(blocks[0])
+		switch {
+		case !ok:
+			ret = append(ret, blocks[0])
+			blocks = blocks[1:]
+		case m.begin:
+			var g *Group
+			g, blocks = p.parseGroup(blocks)
+			ret = append(ret, g)
+		default:
+			p.addError(ErrUnstartedGroup{blocks[0].SrcRange(), m.owner})
+			ret = append(ret, blocks[0])
+			blocks = blocks[1:]
+		}
+	}
+	return ret
+}
+
+// parseGroup parses the group that starts at blocks[0], and returns it
+// along with the remaining blocks that follow the group.
+func (p *parser) parseGroup(blocks []Block) (*Group, []Block) {
+	start, _ := p.groupMarker(blocks[0])
+	ret := &Group{
+		Owner:   start.owner,
+		SortKey: start.sortKey,
+	}
+	emit := blockEmitter(&ret.Blocks, &ret.SourceRange)
+	emit(blocks[0])
+	blocks = blocks[1:]
+
+	for len(blocks) > 0 {
+		m, ok := p.groupMarker(blocks[0])
+		switch {
+		case !ok:
+			emit(blocks[0])
+			blocks = blocks[1:]
+		case m.begin:
+			// As with sections, pretend that the inner group never
+			// existed and grab all its blocks for ourselves.
+			var inner *Group
+			inner, blocks = p.parseGroup(blocks)
+			emit(inner.Blocks...)
+			p.addError(ErrNestedGroup{inner.Blocks[0].SrcRange(), inner.Owner, ret})
+		default:
+			emit(blocks[0])
+			if m.owner != ret.Owner {
+				p.addError(ErrMismatchedGroup{blocks[0].SrcRange(), m.owner, ret})
+			}
+			return ret, blocks[1:]
+		}
+	}
+	p.addError(ErrUnclosedGroup{ret})
+	return ret, nil
+}
+
+// groupMarker returns the group marker that b starts with, if b is a
+// standalone comment that starts with a group marker. Malformed
+// markers are reported as errors, and b is treated as an ordinary
+// comment.
+func (p *parser) groupMarker(b Block) (groupMarker, bool) {
+	c, ok := b.(*Comment)
+	if !ok || len(c.Text) == 0 {
+		return groupMarker{}, false
+	}
+	m, ok, err := parseGroupMarker(c.Text[0])
+	if err != nil {
+		p.addError(ErrInvalidGroupMarker{commentLineRange(c, 0), c.Text[0], err})
+		return groupMarker{}, false
+	}
+	return m, ok
+}
+
 // parseCommentOrSuffixBlock parses a comment, then either returns it
 // as a lone comment or chains into suffix block parsing, depending on
 // what follows the comment.
@@ -329,8 +475,12 @@ func (p *parser) parseSuffixBlock(initialComment *Comment) *Suffixes {
 		case tokenException:
 			// Note we don't emit here, exceptions receive a list of
 			// existing blocks and attach the exception to the
-			// corresponding wildcard entry.
+			// corresponding wildcard entry. The exception's line is
+			// still part of the suffix block though.
 			p.parseException(ret.Blocks)
+			if ret.SourceRange != (SourceRange{}) {
+				ret.SourceRange = ret.SourceRange.merge(tok.SourceRange)
+			}
 		case tokenEOF:
 			return ret
 		default:
@@ -331,7 +331,7 @@ func TestParser(t *testing.T) {
 
 // mkSrc returns a SourceRange with the given start and end.
 func mkSrc(start, end int) SourceRange {
-	return SourceRange{start, end}
+	return SourceRange{FirstLine: start, LastLine: end}
 }
 
 // TestParseRealList checks that the real public suffix list can parse
@@ -374,6 +374,17 @@ func section(start, end int, name string, blocks ...Block) *Section {
 	}
 }
 
+func group(start, end int, owner, sortKey string, blocks ...Block) *Group {
+	return &Group{
+		blockInfo: blockInfo{
+			SourceRange: mkSrc(start, end),
+		},
+		Owner:   owner,
+		SortKey: sortKey,
+		Blocks:  blocks,
+	}
+}
+
 func suffixes(start, end int, info MaintainerInfo, blocks ...Block) *Suffixes {
 	return &Suffixes{
 		blockInfo: blockInfo{
@@ -441,6 +452,8 @@ func zeroSourceRange(b Block) Block {
 		v.SourceRange = SourceRange{}
 	case *Section:
 		v.SourceRange = SourceRange{}
+	case *Group:
+		v.SourceRange = SourceRange{}
 	case *Suffixes:
 		v.SourceRange = SourceRange{}
 	case *Suffix:
@@ -0,0 +1,205 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Suffix blocks can opt out of individual checks with "psl:allow"
+// annotations in their comments, as an alternative to the exemption
+// lists in exceptions.go. For example:
+//
+//	// Example Ltd : https://example.com
+//	// psl:allow missing-email reason="Owner only accepts contact through the website."
+//	example.com
+//
+// An annotation names a check by the name or ID of its error code
+// (see Catalog), and must give a reason as a double-quoted string. It
+// can be part of the block's header, or of a comment before any of
+// the block's suffixes, and applies to the entire block. Only checks
+// marked Suppressible in the catalog can be suppressed, and adding an
+// annotation needs a PSL maintainer's approval (see
+// ValidateNewSuppressions).
+
+// suppressionPrefix is the prefix of annotation comment lines.
+const suppressionPrefix = "psl:allow"
+
+// A Suppression is a psl:allow annotation in a suffix block.
+type Suppression struct {
+	SourceRange
+
+	// Text is the annotation as written, without the leading "//".
+	Text string
+	// Code is the code of the suppressed check, or the zero Code if
+	// the annotation is malformed.
+	Code Code
+	// Reason is the justification given for the suppression.
+	Reason string
+}
+
+// isSuppression reports whether line is a psl:allow annotation,
+// possibly a malformed one.
+func isSuppression(line string) bool {
+	rest, ok := strings.CutPrefix(line, suppressionPrefix)
+	return ok && (rest == "" || rest[0] == ' ')
+}
+
+// parseSuppression parses line as a psl:allow annotation. If line is
+// malformed, parseSuppression returns an error along with a
+// Suppression that has only Text set.
+func parseSuppression(line string) (Suppression, error) {
+	ret := Suppression{Text: line}
+
+	rest, _ := strings.CutPrefix(line, suppressionPrefix)
+	check, rest, _ := strings.Cut(strings.TrimSpace(rest), " ")
+	if check == "" {
+		return ret, errors.New("no check name")
+	}
+	entry, ok := LookupCode(check)
+	if !ok {
+		return ret, fmt.Errorf("unknown check %q", check)
+	}
+	if !entry.Suppressible {
+		return ret, fmt.Errorf("check %s cannot be suppressed", entry.Code)
+	}
+
+	rest = strings.TrimSpace(rest)
+	if rest == "" {
+		return ret, errors.New(`missing reason="..."`)
+	}
+	quoted, ok := strings.CutPrefix(rest, "reason=")
+	if !ok {
+		return ret, fmt.Errorf(`unexpected %q, want reason="..."`, rest)
+	}
+	reason, err := strconv.Unquote(quoted)
+	if err != nil || !strings.HasPrefix(quoted, `"`) {
+		return ret, errors.New("reason must be a double-quoted string")
+	}
+	if strings.TrimSpace(reason) == "" {
+		return ret, errors.New("reason is empty")
+	}
+
+	ret.Code = entry.Code
+	ret.Reason = reason
+	return ret, nil
+}
+
+// commentLineRange returns the SourceRange of the i-th line of c.
+func commentLineRange(c *Comment, i int) SourceRange {
+	if c.NumLines() == 0 {
+		return SourceRange{}
+	}
+	return SourceRange{
+		FirstLine: c.FirstLine + i,
+		LastLine:  c.FirstLine + i + 1,
+		File:      c.File,
+	}
+}
+
+// Suppressions returns the psl:allow annotations of s, from both its
+// header and the comments between its suffixes. The returned slice
+// includes malformed annotations, which have a zero Code.
+func (s *Suffixes) Suppressions() []Suppression {
+	ret := slices.Clone(s.Info.Suppressions)
+	for i, b := range s.Blocks {
+		c, ok := b.(*Comment)
+		if !ok || i == 0 {
+			// The header's annotations are in s.Info.
+			continue
+		}
+		for j, line := range c.Text {
+			if !isSuppression(line) {
+				continue
+			}
+			sup, _ := parseSuppression(line)
+			sup.SourceRange = commentLineRange(c, j)
+			ret = append(ret, sup)
+		}
+	}
+	return ret
+}
+
+// validateSuppressions returns an ErrInvalidSuppression for every
+// malformed psl:allow annotation in block.
+func validateSuppressions(block Block) (errs []error) {
+	for _, s := range BlocksOfType[*Suffixes](block) {
+		for _, sup := range s.Suppressions() {
+			if _, err := parseSuppression(sup.Text); err != nil {
+				errs = append(errs, ErrInvalidSuppression{sup.SourceRange, sup.Text, err})
+			}
+		}
+	}
+	return errs
+}
+
+// ValidateNewSuppressions returns an ErrNewSuppression for every
+// psl:allow annotation in a changed suffix block of l that the same
+// entity's blocks in base don't have. Malformed annotations are
+// reported by ValidateOffline instead.
+func ValidateNewSuppressions(l, base *List) (errs []error) {
+	type key struct{ entity, text string }
+	old := map[key]bool{}
+	for _, s := range BlocksOfType[*Suffixes](base) {
+		for _, sup := range s.Suppressions() {
+			old[key{s.Info.Name, sup.Text}] = true
+		}
+	}
+
+	for _, s := range BlocksOfType[*Suffixes](l) {
+		if !s.Changed() {
+			continue
+		}
+		for _, sup := range s.Suppressions() {
+			if sup.Code != (Code{}) && !old[key{s.Info.Name, sup.Text}] {
+				errs = append(errs, ErrNewSuppression{sup})
+			}
+		}
+	}
+	return errs
+}
+
+// applySuppressions replaces the errors in errs that are suppressed by
+// a psl:allow annotation in l with a SuppressedError.
+//
+// An error is suppressed if it has a Code, and its source location is
+// within a suffix block that has an annotation for that code.
+func applySuppressions(l *List, errs []error) []error {
+	blocks := BlocksOfType[*Suffixes](l)
+	for i, err := range errs {
+		code, ok := ErrorCode(err)
+		if !ok {
+			continue
+		}
+		src, ok := ErrorSource(err)
+		if !ok {
+			continue
+		}
+		for _, b := range blocks {
+			if b.File != src.File || src.FirstLine < b.FirstLine || src.LastLine > b.LastLine {
+				continue
+			}
+			sups := b.Suppressions()
+			if idx := slices.IndexFunc(sups, func(s Suppression) bool { return s.Code == code }); idx >= 0 {
+				errs[i] = SuppressedError{err, sups[idx]}
+				break
+			}
+		}
+	}
+	return errs
+}
+
+// SplitSuppressed separates the SuppressedErrors in errs from the
+// errors that still apply.
+func SplitSuppressed(errs []error) (active []error, suppressed []SuppressedError) {
+	for _, err := range errs {
+		if s, ok := err.(SuppressedError); ok {
+			suppressed = append(suppressed, s)
+		} else {
+			active = append(active, err)
+		}
+	}
+	return active, suppressed
+}
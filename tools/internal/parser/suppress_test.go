@@ -0,0 +1,163 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestSuppressions(t *testing.T) {
+	in := byteLines(
+		"// ===BEGIN ICANN DOMAINS===",
+		"",
+		"// com",
+		"com",
+		"",
+		"// ===END ICANN DOMAINS===",
+		"",
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// Alpha : https://alpha.com",
+		`// psl:allow missing-email reason="Contact through https://alpha.com/contact only."`,
+		"alpha.com",
+		"",
+		"// Bravo : https://bravo.com",
+		"bravo.com",
+		`// psl:allow PSL0010 reason="Set up before contact emails were required."`,
+		"users.bravo.com",
+		"",
+		"// Charlie : https://charlie.com",
+		"// psl:allow missing-email",
+		"// psl:allow duplicate-suffix reason=\"Needed.\"",
+		"// psl:allow no-such-check reason=\"Needed.\"",
+		"// psl:allow unrelated-url reason=\"\"",
+		"// psl:allow missing-txt-record reason=\"Needed.\"",
+		"charlie.com",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+	)
+	l, errs := Parse(in)
+	if len(errs) > 0 {
+		t.Fatalf("Parse failed: %v", errs)
+	}
+
+	got, suppressed := SplitSuppressed(ValidateOffline(l))
+	checkDiff(t, "active errors", errorStrings(got), []string{
+		"lines 19-25: suffix block has no contact email",
+		`line 20: invalid annotation "psl:allow missing-email": missing reason="..."`,
+		`line 21: invalid annotation "psl:allow duplicate-suffix reason=\"Needed.\"": check PSL0024 duplicate-suffix cannot be suppressed`,
+		`line 22: invalid annotation "psl:allow no-such-check reason=\"Needed.\"": unknown check "no-such-check"`,
+		`line 23: invalid annotation "psl:allow unrelated-url reason=\"\"": reason is empty`,
+		`line 24: invalid annotation "psl:allow missing-txt-record reason=\"Needed.\"": check PSL0028 missing-txt-record cannot be suppressed`,
+	})
+	var gotSuppressed []string
+	for _, s := range suppressed {
+		gotSuppressed = append(gotSuppressed, s.Error())
+	}
+	checkDiff(t, "suppressed errors", gotSuppressed, []string{
+		"lines 10-12: suffix block has no contact email (suppressed at line 11: Contact through https://alpha.com/contact only.)",
+		"lines 14-17: suffix block has no contact email (suppressed at line 16: Set up before contact emails were required.)",
+	})
+
+	// Annotations must survive formatting and header rewrites.
+	for _, s := range BlocksOfType[*Suffixes](l) {
+		if s.Info.Name != "Alpha" {
+			continue
+		}
+		info := s.Info
+		info.Notes = append(info.Notes, "Test note.")
+		if err := l.UpdateMaintainerInfo(s, info); err != nil {
+			t.Fatalf("UpdateMaintainerInfo failed: %v", err)
+		}
+	}
+	l.Clean()
+	checkDiff(t, "list after edits", string(l.MarshalPSL()), string(byteLines(
+		"// ===BEGIN ICANN DOMAINS===",
+		"",
+		"// com",
+		"com",
+		"",
+		"// ===END ICANN DOMAINS===",
+		"",
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// Alpha : https://alpha.com",
+		"// Note: Test note.",
+		`// psl:allow missing-email reason="Contact through https://alpha.com/contact only."`,
+		"alpha.com",
+		"",
+		"// Bravo : https://bravo.com",
+		"bravo.com",
+		`// psl:allow PSL0010 reason="Set up before contact emails were required."`,
+		"users.bravo.com",
+		"",
+		"// Charlie : https://charlie.com",
+		"// psl:allow missing-email",
+		"// psl:allow duplicate-suffix reason=\"Needed.\"",
+		"// psl:allow no-such-check reason=\"Needed.\"",
+		"// psl:allow unrelated-url reason=\"\"",
+		"// psl:allow missing-txt-record reason=\"Needed.\"",
+		"charlie.com",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+		"",
+	)))
+
+	l2, errs := Parse(l.MarshalPSL())
+	if len(errs) > 0 {
+		t.Fatalf("reparsing edited list failed: %v", errs)
+	}
+	_, suppressed = SplitSuppressed(ValidateOffline(l2))
+	if len(suppressed) != 2 {
+		t.Errorf("edited list has %d suppressed errors, want 2", len(suppressed))
+	}
+}
+
+func TestValidateNewSuppressions(t *testing.T) {
+	base, errs := Parse(byteLines(
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// Alpha : https://alpha.com",
+		`// psl:allow missing-email reason="Contact through https://alpha.com/contact only."`,
+		"alpha.com",
+		"",
+		"// Bravo : https://bravo.com",
+		"// Submitted by Bravo <psl@bravo.com>",
+		"bravo.com",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+	))
+	if len(errs) > 0 {
+		t.Fatalf("Parse(base) failed: %v", errs)
+	}
+	l, errs := Parse(byteLines(
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// Alpha : https://alpha.com",
+		`// psl:allow missing-email reason="Contact through https://alpha.com/contact only."`,
+		"alpha.com",
+		"www.alpha.com",
+		"",
+		"// Bravo : https://bravo.com",
+		"// Submitted by Bravo <psl@bravo.com>",
+		`// psl:allow unrelated-url reason="Marketing site."`,
+		"bravo.com",
+		"// psl:allow no-such-check",
+		"users.bravo.com",
+		"",
+		"// Charlie : https://charlie.com",
+		`// psl:allow missing-email reason="No email."`,
+		"charlie.com",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+	))
+	if len(errs) > 0 {
+		t.Fatalf("Parse failed: %v", errs)
+	}
+	l.SetBaseVersion(base, false)
+
+	// Alpha's annotation is unchanged, and the malformed annotation
+	// in Bravo is reported by ValidateOffline.
+	checkDiff(t, "ValidateNewSuppressions", errorStrings(ValidateNewSuppressions(l, base)), []string{
+		`line 10: new annotation "psl:allow unrelated-url reason=\"Marketing site.\"" needs approval from a PSL maintainer`,
+		`line 16: new annotation "psl:allow missing-email reason=\"No email.\"" needs approval from a PSL maintainer`,
+	})
+}
@@ -13,9 +13,35 @@ import (
 // SourceRange describes a slice of lines from an unparsed source
 // file. FirstLine and LastLine behave like normal slice offsets,
 // i.e. they represent the half-open range [FirstLine:LastLine).
+//
+// Lists parsed with ParseLossless also record columns. FirstColumn
+// is the byte offset of the start of the range within FirstLine, and
+// LastColumn is the byte offset just past the end of the range within
+// the last line of the range. A LastColumn of zero means the range has
+// no column information, and covers whole lines.
+//
+// File is the name of the source file, for lists that combine blocks
+// from several files (see ParseOverlay). It is empty for blocks of a
+// list parsed from a single file.
 type SourceRange struct {
 	FirstLine int
 	LastLine  int
+
+	FirstColumn int
+	LastColumn  int
+
+	File string
+}
+
+// SrcRange returns s. It lets errors that embed a SourceRange report
+// their location the same way as errors that embed a Block.
+func (s SourceRange) SrcRange() SourceRange {
+	return s
+}
+
+// hasColumns reports whether s has column information.
+func (s SourceRange) hasColumns() bool {
+	return s.LastColumn > 0
 }
 
 // NumLines returns the number of source lines described by
@@ -30,11 +56,23 @@ func (s SourceRange) NumLines() int {
 // LocationString prints a human-readable description of the
 // SourceRange.
 func (s SourceRange) LocationString() string {
+	if s.File != "" {
+		return s.File + ", " + s.lineString()
+	}
+	return s.lineString()
+}
+
+// lineString is LocationString without the file name.
+func (s SourceRange) lineString() string {
 	switch {
 	case s.LastLine <= s.FirstLine:
 		return "<invalid SourceRange>"
+	case s.LastLine == s.FirstLine+1 && s.hasColumns():
+		return fmt.Sprintf("line %d:%d-%d", s.FirstLine+1, s.FirstColumn+1, s.LastColumn)
 	case s.LastLine == s.FirstLine+1:
 		return fmt.Sprintf("line %d", s.FirstLine+1)
+	case s.hasColumns():
+		return fmt.Sprintf("lines %d:%d-%d:%d", s.FirstLine+1, s.FirstColumn+1, s.LastLine, s.LastColumn)
 	default:
 		return fmt.Sprintf("lines %d-%d", s.FirstLine+1, s.LastLine)
 	}
@@ -44,11 +82,36 @@ func (s SourceRange) LocationString() string {
 // and other are not contiguous or overlapping, the returned
 // SourceRange also spans unrelated lines, but always covers both s
 // and other.
+//
+// The returned SourceRange only has column information if both s and
+// other do.
 func (s SourceRange) merge(other SourceRange) SourceRange {
-	return SourceRange{
+	ret := SourceRange{
 		FirstLine: min(s.FirstLine, other.LastLine),
 		LastLine:  max(s.LastLine, other.LastLine),
+		File:      s.File,
 	}
+	if !s.hasColumns() || !other.hasColumns() {
+		return ret
+	}
+
+	switch {
+	case s.FirstLine < other.FirstLine:
+		ret.FirstColumn = s.FirstColumn
+	case other.FirstLine < s.FirstLine:
+		ret.FirstColumn = other.FirstColumn
+	default:
+		ret.FirstColumn = min(s.FirstColumn, other.FirstColumn)
+	}
+	switch {
+	case s.LastLine > other.LastLine:
+		ret.LastColumn = s.LastColumn
+	case other.LastLine > s.LastLine:
+		ret.LastColumn = other.LastColumn
+	default:
+		ret.LastColumn = max(s.LastColumn, other.LastColumn)
+	}
+	return ret
 }
 
 const (
@@ -80,6 +143,16 @@ var (
 // normalizeToUTF8Lines returns the normalized lines of bs, as well as
 // errors that report deviations from the canonical encoding, if any.
 func normalizeToUTF8Lines(bs []byte) ([]string, []error) {
+	lines, errs := decodeToUTF8Lines(bs)
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return lines, errs
+}
+
+// decodeToUTF8Lines is like normalizeToUTF8Lines, but does not trim
+// leading and trailing whitespace from the returned lines.
+func decodeToUTF8Lines(bs []byte) ([]string, []error) {
 	var errs []error
 
 	// Figure out the byte encoding to use. We try to detect and
@@ -128,13 +201,12 @@ func normalizeToUTF8Lines(bs []byte) ([]string, []error) {
 		// replacement character is a distinctive shape that stands
 		// out, it should provide enough hints as to where any invalid
 		// byte sequences are.
-		src := SourceRange{i, i + 1}
+		src := SourceRange{FirstLine: i, LastLine: i + 1}
 		if strings.ContainsRune(line, utf8.RuneError) {
 			// We can't fix invalid Unicode, by definition we don't
 			// know what it's trying to say.
 			errs = append(errs, ErrInvalidUnicode{src})
 		}
-		ret[i] = strings.TrimSpace(line)
 	}
 
 	return ret, errs
@@ -0,0 +1,386 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// A list can also be kept as a source tree, with one file per suffix
+// block instead of a single file. The tree for the PSL looks like:
+//
+//	00-comments.dat             comments outside of sections
+//	01-icann-domains/           one directory per section
+//	   00/                      one directory per run of blocks
+//	      ac.dat                one file per suffix block
+//	      ad.dat
+//	      ...
+//	   01-idn-cctlds/
+//	      _comment.dat          free-floating comment that starts the run
+//	      00-xn--mgbaam7a8h.dat
+//	      ...                   numbered, see below
+//	02-private-domains/
+//	   00-note-these-are-in-alphabetical-order-by-company-name/
+//	      _comment.dat
+//	      amazon.dat            a managed group is kept in one file
+//	      example-ltd.dat
+//	      ...
+//
+// Each file is in PSL syntax. Block files contain exactly one suffix
+// block or managed group, and comment files contain only comments.
+//
+// Section and run directories are assembled in the order of their
+// numeric prefixes, and the files of a run in the order of their
+// names without the ".dat" extension. The result is sorted with
+// Clean like any other list, so apart from blocks that sort equally,
+// IDN ccTLD blocks and the gTLD region, the names of block files don't
+// affect the assembled list. The names that MarshalTree chooses are
+// the block's TLD in the ICANN section, and a simplified entity name
+// elsewhere. IDN ccTLD blocks are sorted by hand, so the files of runs
+// that contain them are also numbered in order.
+
+const (
+	// treeExt is the extension of PSL files in a source tree.
+	treeExt = ".dat"
+	// treeCommentFile is the name of the file that holds the
+	// free-floating comment at the start of a run of blocks.
+	treeCommentFile = "_comment" + treeExt
+	// maxTreeSlugLen is the maximum length in bytes of the
+	// descriptive part of a tree file name.
+	maxTreeSlugLen = 60
+)
+
+// ParseTree parses the source tree in fsys called name, usually the
+// path of the tree's root directory, and returns the assembled list.
+//
+// The SourceRanges of blocks and parse errors record the path of the
+// file they came from as their File, which is the file's path within
+// fsys joined to name. Files without the ".dat" extension are
+// ignored.
+func ParseTree(name string, fsys fs.FS) (*List, []error) {
+	t := treeReader{fsys, name}
+	return t.parse()
+}
+
+// treeReader reads the files of a source tree.
+type treeReader struct {
+	fsys fs.FS
+	name string
+}
+
+// display returns the name of file for SourceRanges and errors.
+func (t treeReader) display(file string) string {
+	return path.Join(t.name, file)
+}
+
+func (t treeReader) parse() (*List, []error) {
+	ret := &List{}
+	var errs []error
+
+	entries, err := fs.ReadDir(t.fsys, ".")
+	if err != nil {
+		return ret, []error{err}
+	}
+	for _, e := range entries {
+		name := e.Name()
+		switch {
+		case e.IsDir():
+			_, slug, ok := cutTreeIndex(name)
+			if !ok || slug == "" {
+				errs = append(errs, fmt.Errorf("%s: section directory name must be <number>-<section name>", t.display(name)))
+				continue
+			}
+			section, sectionErrs := t.parseSection(name, strings.ToUpper(strings.ReplaceAll(slug, "-", " ")))
+			errs = append(errs, sectionErrs...)
+			ret.Blocks = append(ret.Blocks, section)
+		case path.Ext(name) == treeExt:
+			l, fileErrs := t.parseFile(name)
+			errs = append(errs, fileErrs...)
+			for _, b := range l.Blocks {
+				if _, ok := b.(*Comment); !ok {
+					errs = append(errs, fmt.Errorf("%s: suffix blocks must be in a section directory", b.SrcRange().LocationString()))
+					continue
+				}
+				ret.Blocks = append(ret.Blocks, b)
+			}
+		}
+	}
+	return ret, errs
+}
+
+// parseSection parses the section called name from the directory dir
+// of the tree.
+func (t treeReader) parseSection(dir, name string) (*Section, []error) {
+	ret := &Section{Name: name}
+	var errs []error
+
+	runs, err := fs.ReadDir(t.fsys, dir)
+	if err != nil {
+		return ret, []error{err}
+	}
+	for _, run := range runs {
+		runDir := path.Join(dir, run.Name())
+		if !run.IsDir() {
+			if path.Ext(runDir) == treeExt {
+				errs = append(errs, fmt.Errorf("%s: files in a section must be in a numbered directory", t.display(runDir)))
+			}
+			continue
+		}
+		if _, _, ok := cutTreeIndex(run.Name()); !ok {
+			errs = append(errs, fmt.Errorf("%s: directory name must start with a number", t.display(runDir)))
+			continue
+		}
+
+		files, err := fs.ReadDir(t.fsys, runDir)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		files = slices.DeleteFunc(files, func(f fs.DirEntry) bool {
+			return f.IsDir() || path.Ext(f.Name()) != treeExt
+		})
+		slices.SortStableFunc(files, func(a, b fs.DirEntry) int {
+			return strings.Compare(strings.TrimSuffix(a.Name(), treeExt), strings.TrimSuffix(b.Name(), treeExt))
+		})
+		// The run's comment comes first, regardless of how its name
+		// sorts.
+		if i := slices.IndexFunc(files, func(f fs.DirEntry) bool { return f.Name() == treeCommentFile }); i > 0 {
+			comment := files[i]
+			files = slices.Insert(slices.Delete(files, i, i+1), 0, comment)
+		}
+
+		for _, f := range files {
+			file := path.Join(runDir, f.Name())
+			l, fileErrs := t.parseFile(file)
+			errs = append(errs, fileErrs...)
+			if f.Name() == treeCommentFile {
+				if !isOnly[*Comment](l.Blocks) {
+					errs = append(errs, fmt.Errorf("%s: must contain only a single comment", t.display(file)))
+					continue
+				}
+			} else if !isOnly[*Suffixes](l.Blocks) && !isOnly[*Group](l.Blocks) {
+				errs = append(errs, fmt.Errorf("%s: must contain exactly one suffix block or managed group", t.display(file)))
+				continue
+			}
+			ret.Blocks = append(ret.Blocks, l.Blocks[0])
+		}
+	}
+	return ret, errs
+}
+
+// isOnly reports whether blocks consists of a single T.
+func isOnly[T Block](blocks []Block) bool {
+	if len(blocks) != 1 {
+		return false
+	}
+	_, ok := blocks[0].(T)
+	return ok
+}
+
+// parseFile parses one file of the tree.
+func (t treeReader) parseFile(file string) (*List, []error) {
+	bs, err := fs.ReadFile(t.fsys, file)
+	if err != nil {
+		return &List{}, []error{err}
+	}
+	p := newParser(bs, t.display(file))
+	ret := p.parseTopLevel()
+	// Managed groups are kept in files of their own, outside of any
+	// section.
+	ret.Blocks = p.parseGroups(ret.Blocks)
+	return ret, p.errs
+}
+
+// cutTreeIndex splits the name of a numbered tree directory or file
+// into its number and the rest of the name.
+func cutTreeIndex(name string) (idx int, rest string, ok bool) {
+	num, rest, _ := strings.Cut(strings.TrimSuffix(name, treeExt), "-")
+	idx, err := strconv.Atoi(num)
+	if err != nil || idx < 0 {
+		return 0, "", false
+	}
+	return idx, rest, true
+}
+
+// IsTreePath reports whether name, a slash-separated path within a
+// source tree, is laid out like the files that MarshalTree writes:
+// either a numbered file at the top of the tree, or a file in a
+// numbered run directory of a numbered section directory. Tools that
+// rewrite a tree must leave all other files alone.
+func IsTreePath(name string) bool {
+	if path.Ext(name) != treeExt {
+		return false
+	}
+	parts := strings.Split(name, "/")
+	switch len(parts) {
+	case 1:
+		_, _, ok := cutTreeIndex(parts[0])
+		return ok
+	case 3:
+		_, slug, ok := cutTreeIndex(parts[0])
+		if !ok || slug == "" {
+			return false
+		}
+		_, _, ok = cutTreeIndex(parts[1])
+		return ok
+	default:
+		return false
+	}
+}
+
+// MarshalTree returns l as a source tree, as a map of slash-separated
+// file paths to file contents. See ParseTree for the layout of the
+// tree.
+//
+// MarshalTree returns an error if l has suffix blocks outside of
+// sections, which the tree layout cannot represent.
+func (l *List) MarshalTree() (map[string][]byte, error) {
+	// Consecutive comments outside sections share a file.
+	var items [][]Block
+	for i, b := range l.Blocks {
+		switch b.(type) {
+		case *Comment:
+			if i > 0 && len(items) > 0 {
+				if _, ok := l.Blocks[i-1].(*Comment); ok {
+					items[len(items)-1] = append(items[len(items)-1], b)
+					continue
+				}
+			}
+			items = append(items, []Block{b})
+		case *Section:
+			items = append(items, []Block{b})
+		default:
+			return nil, fmt.Errorf("%s: suffix block outside of a section", b.SrcRange().LocationString())
+		}
+	}
+
+	ret := map[string][]byte{}
+	width := treeIndexWidth(len(items))
+	for i, item := range items {
+		s, ok := item[0].(*Section)
+		if !ok {
+			ret[fmt.Sprintf("%0*d-comments%s", width, i, treeExt)] = marshalTreeFile(item...)
+			continue
+		}
+		marshalTreeSection(ret, fmt.Sprintf("%0*d-%s", width, i, treeSlug(s.Name)), s)
+	}
+	return ret, nil
+}
+
+// marshalTreeSection adds the files for section s in directory dir
+// to files.
+func marshalTreeSection(files map[string][]byte, dir string, s *Section) {
+	// Split the section into runs of blocks, each starting with a
+	// free-floating comment except possibly the first.
+	var (
+		runs      [][]Block
+		runStarts []int
+	)
+	for i, b := range s.Blocks {
+		if _, ok := b.(*Comment); ok || len(runs) == 0 {
+			runs = append(runs, nil)
+			runStarts = append(runStarts, i)
+		}
+		runs[len(runs)-1] = append(runs[len(runs)-1], b)
+	}
+	gTLDStart := gTLDRegionStart(s)
+
+	width := treeIndexWidth(len(runs))
+	for i, run := range runs {
+		runDir := fmt.Sprintf("%0*d", width, i)
+		if c, ok := run[0].(*Comment); ok {
+			if slug := treeSlug(c.Text[0]); slug != "" {
+				runDir += "-" + slug
+			}
+			files[path.Join(dir, runDir, treeCommentFile)] = marshalTreeFile(c)
+			run = run[1:]
+		}
+
+		numbered := s.Name == "ICANN DOMAINS" && runStarts[i] < gTLDStart && slices.ContainsFunc(run, isIDNBlock)
+		fileWidth := treeIndexWidth(len(run))
+		used := map[string]bool{}
+		for j, b := range run {
+			stem := treeFileStem(s, b)
+			if stem == "" {
+				stem = "block"
+			}
+			if numbered {
+				stem = fmt.Sprintf("%0*d-%s", fileWidth, j, stem)
+			}
+			name := stem
+			for n := 2; used[name]; n++ {
+				name = fmt.Sprintf("%s-%d", stem, n)
+			}
+			used[name] = true
+			files[path.Join(dir, runDir, name+treeExt)] = marshalTreeFile(b)
+		}
+	}
+}
+
+// treeFileStem returns the preferred file name for block b of
+// section s, without extension.
+func treeFileStem(s *Section, b Block) string {
+	switch v := b.(type) {
+	case *Group:
+		return treeSlug(v.Owner)
+	case *Suffixes:
+		if s.Name == "ICANN DOMAINS" {
+			// The A-label is always a valid file name, and sorts
+			// the same way as the gTLD updater orders the gTLD
+			// region.
+			if tld, ok := blockTLD(v); ok {
+				return tld.ASCIIString()
+			}
+		}
+		return treeSlug(v.Info.Name)
+	default:
+		return ""
+	}
+}
+
+// marshalTreeFile returns the PSL text of a tree file containing
+// blocks.
+func marshalTreeFile(blocks ...Block) []byte {
+	var ret bytes.Buffer
+	for i, b := range blocks {
+		if i > 0 {
+			ret.WriteString("\n")
+		}
+		writeBlockPSL(&ret, b)
+	}
+	return ret.Bytes()
+}
+
+// treeIndexWidth returns the number of digits to use for the indexes
+// of n numbered tree entries.
+func treeIndexWidth(n int) int {
+	return max(2, len(strconv.Itoa(n-1)))
+}
+
+// treeSlug returns a simplified form of s for use in tree file names:
+// lowercase letters and digits, with each run of other characters
+// replaced by a single dash.
+func treeSlug(s string) string {
+	var ret strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			dash = true
+			continue
+		}
+		if dash && ret.Len() > 0 {
+			ret.WriteByte('-')
+		}
+		dash = false
+		if ret.Len()+len(string(r)) > maxTreeSlugLen {
+			break
+		}
+		ret.WriteRune(r)
+	}
+	return strings.TrimSuffix(ret.String(), "-")
+}
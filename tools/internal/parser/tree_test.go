@@ -0,0 +1,177 @@
+package parser
+
+import (
+	"os"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestTreeRoundtripRealPSL(t *testing.T) {
+	bs, err := os.ReadFile("../../../public_suffix_list.dat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	psl, errs := Parse(bs)
+	if len(errs) > 0 {
+		t.Fatalf("Parse failed: %v", errs)
+	}
+
+	files, err := psl.MarshalTree()
+	if err != nil {
+		t.Fatalf("MarshalTree failed: %v", err)
+	}
+	fsys := fstest.MapFS{}
+	for path, bs := range files {
+		fsys[path] = &fstest.MapFile{Data: bs}
+	}
+
+	tree, errs := ParseTree("", fsys)
+	if len(errs) > 0 {
+		t.Fatalf("ParseTree failed: %v", errs)
+	}
+	if errs := tree.Clean(); len(errs) > 0 {
+		t.Fatalf("Clean failed: %v", errs)
+	}
+	checkDiff(t, "assembled list", string(tree.MarshalPSL()), string(bs))
+
+	// Spot check a few files of the layout.
+	for _, path := range []string{
+		"00-comments.dat",
+		"01-icann-domains/00/ac.dat",
+		"01-icann-domains/00/vn-2.dat",
+		"01-icann-domains/01-idn-cctlds/_comment.dat",
+		"01-icann-domains/01-idn-cctlds/00-xn--mgbaam7a8h.dat",
+		"01-icann-domains/02-newgtlds/aaa.dat",
+		"02-private-domains/00-note-these-are-in-alphabetical-order-by-company-name/amazon.dat",
+	} {
+		if _, ok := files[path]; !ok {
+			t.Errorf("tree has no file %q", path)
+		}
+	}
+}
+
+func TestParseTree(t *testing.T) {
+	fsys := fstest.MapFS{
+		"00-comments.dat": {Data: byteLines(
+			"// License header.",
+			"",
+		)},
+		"01-icann-domains/00/com.dat": {Data: byteLines(
+			"// com",
+			"com",
+			"",
+		)},
+		"01-icann-domains/00/ae.dat": {Data: byteLines(
+			"// ae",
+			"ae",
+			"",
+		)},
+		"02-private-domains/00/zork.dat": {Data: byteLines(
+			"// Zork : https://zork.net",
+			"// Submitted by Zork <psl@zork.net>",
+			"zork.net",
+			"",
+		)},
+		"02-private-domains/00/adventure.dat": {Data: byteLines(
+			"// Adventure : https://adventure.net",
+			"adventure.net",
+			"",
+		)},
+		"02-private-domains/00/README.md": {Data: []byte("Not part of the list.")},
+	}
+
+	l, errs := ParseTree("", fsys)
+	if len(errs) > 0 {
+		t.Fatalf("ParseTree failed: %v", errs)
+	}
+	got, _ := SplitSuppressed(append(l.Clean(), ValidateOffline(l)...))
+	checkDiff(t, "errors", errorStrings(got), []string{
+		"02-private-domains/00/adventure.dat, lines 1-2: suffix block has no contact email",
+	})
+	checkDiff(t, "assembled list", string(l.MarshalPSL()), string(byteLines(
+		"// License header.",
+		"",
+		"// ===BEGIN ICANN DOMAINS===",
+		"",
+		"// ae",
+		"ae",
+		"",
+		"// com",
+		"com",
+		"",
+		"// ===END ICANN DOMAINS===",
+		"",
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// Adventure : https://adventure.net",
+		"adventure.net",
+		"",
+		"// Zork : https://zork.net",
+		"// Submitted by Zork <psl@zork.net>",
+		"zork.net",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+		"",
+	)))
+}
+
+func TestParseTreeErrors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"01-icann-domains/00/com.dat": {Data: byteLines(
+			"// com",
+			"com",
+			"",
+			"// net",
+			"net",
+			"",
+		)},
+		"01-icann-domains/00/org.dat": {Data: byteLines(
+			"// org",
+			"org",
+			"bad..org",
+			"",
+		)},
+		"01-icann-domains/stray.dat": {Data: byteLines(
+			"// stray",
+			"stray",
+		)},
+		"private/00/x.dat": {Data: byteLines("x.example")},
+	}
+
+	_, errs := ParseTree("", fsys)
+	got := errorStrings(errs)
+	slices.Sort(got)
+	checkDiff(t, "errors", got, []string{
+		"01-icann-domains/00/com.dat: must contain exactly one suffix block or managed group",
+		"01-icann-domains/00/org.dat, line 3: invalid suffix \"bad..org\": label 2 is empty",
+		"01-icann-domains/stray.dat: files in a section must be in a numbered directory",
+		"private: section directory name must be <number>-<section name>",
+	})
+}
+
+func TestIsTreePath(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"00-comments.dat", true},
+		{"01-icann-domains/00/ac.dat", true},
+		{"01-icann-domains/01-idn-cctlds/_comment.dat", true},
+		{"02-private-domains/00-note/amazon.dat", true},
+
+		{"public_suffix_list.dat", false},
+		{"00-comments.txt", false},
+		{"01-icann-domains/README.md", false},
+		{"01-icann-domains/ac.dat", false},
+		{"01/00/ac.dat", false},
+		{"other/00/keep.dat", false},
+		{"01-icann-domains/other/keep.dat", false},
+		{"01-icann-domains/00/sub/ac.dat", false},
+	}
+	for _, tc := range tests {
+		if got := IsTreePath(tc.name); got != tc.want {
+			t.Errorf("IsTreePath(%q) = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
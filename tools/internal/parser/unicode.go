@@ -69,21 +69,42 @@ func compareCommentText(a string, b string) int {
 	// are more exhaustive tests for sort key computation, so there is
 	// higher confidence that it works correctly.
 	//
-	// Unfortunately individual collators are also not safe for
-	// concurrent use. Wrap them in a global mutex. We could also
-	// construct a new collator for each use, but that ends up being
-	// more expensive and less performant than sharing one collator
-	// with a mutex.
-	commentCollatorMu.Lock()
-	defer commentCollatorMu.Unlock()
-	var buf collate.Buffer
-	ka := commentCollator.KeyFromString(&buf, a)
-	kb := commentCollator.KeyFromString(&buf, b)
-	return bytes.Compare(ka, kb)
+	// Individual collators are also not safe for concurrent use,
+	// and are somewhat expensive to construct, so we borrow one from
+	// a pool. Each pooled collator has its own scratch buffer, which
+	// is too large to allocate on every comparison.
+	cc := commentCollators.Get().(*commentCollator)
+	defer commentCollators.Put(cc)
+	ka := cc.c.KeyFromString(&cc.buf, a)
+	kb := cc.c.KeyFromString(&cc.buf, b)
+	ret := bytes.Compare(ka, kb)
+	cc.buf.Reset()
+	return ret
 }
 
-// commentCollator compares strings in the PSL's chosen collation for
-// non-suffix text. See the comment at the start of this file for more
-// details.
-var commentCollator = collate.New(language.MustParse("en"))
-var commentCollatorMu sync.Mutex
+// commentSortKey returns the collation key of the comment text s.
+//
+// Comment texts can be ordered by comparing their keys with
+// bytes.Compare, in the same order as compareCommentText. This is
+// useful when sorting, to compute each key only once.
+func commentSortKey(s string) []byte {
+	cc := commentCollators.Get().(*commentCollator)
+	defer commentCollators.Put(cc)
+	ret := bytes.Clone(cc.c.KeyFromString(&cc.buf, s))
+	cc.buf.Reset()
+	return ret
+}
+
+// commentCollators is a pool of collators for the PSL's chosen
+// collation for non-suffix text. See the comment at the start of
+// this file for more details.
+var commentCollators = sync.Pool{
+	New: func() any {
+		return &commentCollator{c: collate.New(language.MustParse("en"))}
+	},
+}
+
+type commentCollator struct {
+	c   *collate.Collator
+	buf collate.Buffer
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log"
 	"net"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -16,19 +17,26 @@ import (
 )
 
 // ValidateOffline runs offline validations on a parsed PSL.
+//
+// Errors that are suppressed by a psl:allow annotation are returned as
+// SuppressedErrors.
 func ValidateOffline(l *List) []error {
 	var ret []error
 
 	for _, block := range BlocksOfType[*Section](l) {
 		if block.Name == "PRIVATE DOMAINS" {
 			ret = append(ret, validateEntityMetadata(block)...)
+			ret = append(ret, validateEntityContacts(block)...)
 			break
 		}
 	}
-	validateExpectedSections(l)
-	validateSuffixUniqueness(l)
+	ret = append(ret, validateExpectedSections(l)...)
+	ret = append(ret, validateSuffixUniqueness(l)...)
+	ret = append(ret, validateConfusables(l)...)
+	ret = append(ret, validateSuppressions(l)...)
+	ret = append(ret, validateGroups(l)...)
 
-	return ret
+	return applySuppressions(l, ret)
 }
 
 // validateEntityMetadata verifies that all suffix blocks have some
@@ -54,6 +62,201 @@ func validateEntityMetadata(block Block) []error {
 	return ret
 }
 
+// validateEntityContacts verifies that the URLs and email addresses
+// in suffix block headers are plausible ways to contact the block's
+// owner.
+//
+// Blocks added by an overlay hold local suffixes, which usually live
+// under reserved names like corp.example.com, so their contacts may
+// use placeholder domains.
+func validateEntityContacts(block Block) []error {
+	var ret []error
+	for _, block := range BlocksOfType[*Suffixes](block) {
+		if !block.Changed() || exemptFromContactValidation(block.Info.Name) {
+			continue
+		}
+
+		for _, u := range block.Info.URLs {
+			switch CheckEntityURL(u) {
+			case errURLHasCredentials:
+				ret = append(ret, ErrEntityURLHasCredentials{block, u})
+			case errInsecureURL:
+				ret = append(ret, ErrInsecureEntityURL{block, u})
+			}
+
+			host, err := domain.Parse(u.Hostname())
+			if err != nil {
+				ret = append(ret, ErrInvalidEntityURL{block, u, err})
+			} else if isPlaceholderDomain(host) && block.overlay == "" {
+				ret = append(ret, ErrPlaceholderEntityContact{block, u.String()})
+			}
+		}
+
+		for _, m := range block.Info.Maintainers {
+			_, hostStr, _ := strings.Cut(m.Address, "@")
+			host, err := domain.Parse(hostStr)
+			if err == nil && host.NumLabels() < 2 {
+				err = errors.New("email domain is a top-level domain")
+			}
+			if err != nil {
+				ret = append(ret, ErrInvalidEntityEmail{block, m, err})
+			} else if isPlaceholderDomain(host) && block.overlay == "" {
+				ret = append(ret, ErrPlaceholderEntityContact{block, m.Address})
+			}
+		}
+	}
+	return ret
+}
+
+// ValidateNewEntityURLs returns an ErrUnrelatedEntityURL for every
+// URL that a change adds to a private suffix block, compared to the
+// same entity's blocks in base, and that doesn't look related to the
+// block's suffixes.
+//
+// Whether a URL is related is only a heuristic (see
+// relatedToSuffixes), so it is applied to new URLs where a reviewer
+// can look at the result, and not to the existing list. Invalid and
+// placeholder URLs are reported by ValidateOffline instead.
+func ValidateNewEntityURLs(l, base *List) []error {
+	type key struct{ entity, url string }
+	old := map[key]bool{}
+	for _, s := range BlocksOfType[*Suffixes](base) {
+		for _, u := range s.Info.URLs {
+			old[key{s.Info.Name, u.String()}] = true
+		}
+	}
+
+	var ret []error
+	for _, section := range BlocksOfType[*Section](l) {
+		if section.Name != "PRIVATE DOMAINS" {
+			continue
+		}
+		for _, block := range BlocksOfType[*Suffixes](section) {
+			if !block.Changed() || exemptFromContactValidation(block.Info.Name) {
+				continue
+			}
+			for _, u := range block.Info.URLs {
+				if old[key{block.Info.Name, u.String()}] {
+					continue
+				}
+				host, err := domain.Parse(u.Hostname())
+				if err != nil || isPlaceholderDomain(host) {
+					continue
+				}
+				if !relatedToSuffixes(host, block) {
+					ret = append(ret, ErrUnrelatedEntityURL{block, u})
+				}
+			}
+		}
+	}
+	return applySuppressions(l, ret)
+}
+
+var (
+	errURLHasCredentials = errors.New("URL must not contain credentials")
+	errInsecureURL       = errors.New("URL must use https")
+)
+
+// CheckEntityURL returns an error if u can't be used in a suffix
+// block header because it contains credentials or doesn't use https.
+// ValidateOffline reports the same problems as
+// ErrEntityURLHasCredentials and ErrInsecureEntityURL.
+func CheckEntityURL(u *url.URL) error {
+	switch {
+	case u.User != nil:
+		return errURLHasCredentials
+	case u.Scheme != "https":
+		return errInsecureURL
+	}
+	return nil
+}
+
+// placeholderDomains are domains reserved for documentation and
+// testing by RFC 2606 and RFC 6761. Contact information that uses
+// them was copied from an example and never filled in.
+var placeholderDomains = []string{
+	"example",
+	"example.com",
+	"example.net",
+	"example.org",
+	"invalid",
+	"localhost",
+	"test",
+}
+
+// isPlaceholderDomain reports whether d is, or is a subdomain of, one
+// of placeholderDomains.
+func isPlaceholderDomain(d domain.Name) bool {
+	for _, p := range placeholderDomains {
+		pd, err := domain.Parse(p)
+		if err != nil {
+			panic(err)
+		}
+		if isSameOrChild(d, pd) {
+			return true
+		}
+	}
+	return false
+}
+
+// relatedToSuffixes reports whether host plausibly belongs to the
+// same owner as the suffixes in block.
+//
+// host is related if it is a parent or child of one of the suffixes,
+// or if it shares a distinctive label with one of them. For example,
+// "www.example.com" is related to "users.example.net" and
+// "exampleusercontent.com", but not to "foo.com".
+func relatedToSuffixes(host domain.Name, block *Suffixes) bool {
+	var suffixes []domain.Name
+	for _, s := range BlocksOfType[*Suffix](block) {
+		suffixes = append(suffixes, s.Domain)
+	}
+	for _, w := range BlocksOfType[*Wildcard](block) {
+		suffixes = append(suffixes, w.Domain)
+	}
+
+	hostBrands := brandLabels(host)
+	for _, s := range suffixes {
+		if isSameOrChild(host, s) || isSameOrChild(s, host) {
+			return true
+		}
+		for _, b := range brandLabels(s) {
+			for _, hb := range hostBrands {
+				if strings.Contains(b, hb) || strings.Contains(hb, b) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// isSameOrChild reports whether d is equal to parent, or is a child
+// domain of parent.
+func isSameOrChild(d, parent domain.Name) bool {
+	if d.Equal(parent) {
+		return true
+	}
+	_, ok := d.CutSuffix(parent)
+	return ok
+}
+
+// brandLabels returns the labels of d that are distinctive enough to
+// identify an organization: all labels except the top-level domain,
+// and except short labels like "co" or "www".
+func brandLabels(d domain.Name) []string {
+	var ret []string
+	labels := d.Labels()
+	for _, l := range labels[:len(labels)-1] {
+		s := l.ASCIIString()
+		if len(s) < 4 {
+			continue
+		}
+		ret = append(ret, s)
+	}
+	return ret
+}
+
 // validateExpectedSections verifies that the two top-level sections
 // (ICANN and private domains) exist, are not duplicated, and that no
 // other sections are present.
@@ -121,10 +324,65 @@ func validateSuffixUniqueness(block Block) (errs []error) {
 	return errs
 }
 
+// validateConfusables verifies that suffixes are not visually
+// confusable with other suffixes, and that their labels do not mix
+// scripts in unsafe ways.
+func validateConfusables(block Block) (errs []error) {
+	type entry struct {
+		Block
+		name domain.Name
+	}
+	var entries []entry
+	for _, suffix := range BlocksOfType[*Suffix](block) {
+		entries = append(entries, entry{suffix, suffix.Domain})
+	}
+	for _, wildcard := range BlocksOfType[*Wildcard](block) {
+		entries = append(entries, entry{wildcard, wildcard.Domain})
+	}
+
+	// Note this doesn't report wildcards and suffixes that are
+	// exactly equal, those are reported by validateSuffixUniqueness
+	// if needed.
+	bySkeleton := map[string][]entry{}
+	for _, e := range entries {
+		skel := e.name.Skeleton()
+		bySkeleton[skel] = append(bySkeleton[skel], e)
+	}
+
+	// Each confusable pair is reported once, on whichever of the two
+	// suffixes is checked first.
+	type pair struct{ a, b Block }
+	reported := map[pair]bool{}
+	for _, e := range entries {
+		if !e.Changed() || exemptFromConfusables(e.name) {
+			continue
+		}
+
+		for _, label := range e.name.Labels() {
+			if runes, mixed := label.MixedScriptRunes(); mixed {
+				errs = append(errs, ErrMixedScriptSuffix{e.Block, label, label.Scripts(), runes})
+			}
+		}
+
+		for _, other := range bySkeleton[e.name.Skeleton()] {
+			if other.name.Equal(e.name) || reported[pair{other.Block, e.Block}] {
+				continue
+			}
+			reported[pair{e.Block, other.Block}] = true
+			errs = append(errs, ErrConfusableSuffix{e.Block, other.Block, e.name.ConfusableRunes(other.name)})
+		}
+	}
+
+	return errs
+}
+
 // ValidateOnline runs online validations on a parsed PSL. Online
 // validations are slower than offline validation, especially when
 // checking the entire PSL. All online validations respect
 // cancellation on the given context.
+//
+// As with ValidateOffline, errors that are suppressed by a psl:allow
+// annotation are returned as SuppressedErrors.
 func ValidateOnline(ctx context.Context, l *List, client *github.Repo, prHistory *githistory.History) (errs []error) {
 	for _, section := range BlocksOfType[*Section](l) {
 		if section.Name == "PRIVATE DOMAINS" {
@@ -132,7 +390,7 @@ func ValidateOnline(ctx context.Context, l *List, client *github.Repo, prHistory
 			break
 		}
 	}
-	return errs
+	return applySuppressions(l, errs)
 }
 
 const (
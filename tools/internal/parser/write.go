@@ -6,12 +6,21 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // MarshalPSL returns the list serialized to standard PSL text format.
+//
+// If l was parsed with ParseLossless, the original source text is
+// reused for all blocks that have not been edited, so that only the
+// edited portions of the list differ from the original input.
 func (l *List) MarshalPSL() []byte {
 	var ret bytes.Buffer
-	writeBlockPSL(&ret, l)
+	if l.src != nil {
+		l.src.writeList(&ret, l)
+	} else {
+		writeBlockPSL(&ret, l)
+	}
 	return ret.Bytes()
 }
 
@@ -36,6 +45,13 @@ func writeBlockPSL(w io.Writer, b Block) {
 		}
 		f("")
 		f("// ===END %s===", v.Name)
+	case *Group:
+		for i, child := range v.Blocks {
+			if i > 0 {
+				f("")
+			}
+			writeBlockPSL(w, child)
+		}
 	case *Suffixes:
 		for _, child := range v.Blocks {
 			writeBlockPSL(w, child)
@@ -97,6 +113,12 @@ func writeBlockDebug(w io.Writer, b Block, indent string) {
 			writeBlockDebug(w, child, nextIndent)
 		}
 		f("} // Section(name=%q)", v.Name)
+	case *Group:
+		f("%sGroup(%s, owner=%q, sortkey=%q) {", changemark, loc, v.Owner, v.SortKey)
+		for _, child := range v.Blocks {
+			writeBlockDebug(w, child, nextIndent)
+		}
+		f("} // Group(owner=%q)", v.Owner)
 	case *Suffixes:
 		items := []string{loc, fmt.Sprintf("editable=%v", v.Info.MachineEditable)}
 		if v.Info.Name != "" {
@@ -109,9 +131,21 @@ func writeBlockDebug(w io.Writer, b Block, indent string) {
 			email := strings.TrimSpace(fmt.Sprintf("%s <%s>", e.Name, e.Address))
 			items = append(items, fmt.Sprintf("contact=%q", email))
 		}
+		if !v.Info.Requested.IsZero() {
+			items = append(items, fmt.Sprintf("requested=%s", v.Info.Requested.Format(time.DateOnly)))
+		}
+		if v.Info.PR != 0 {
+			items = append(items, fmt.Sprintf("pr=%d", v.Info.PR))
+		}
+		for _, n := range v.Info.Notes {
+			items = append(items, fmt.Sprintf("note=%q", n))
+		}
 		for _, o := range v.Info.Other {
 			items = append(items, fmt.Sprintf("other=%q", o))
 		}
+		for _, s := range v.Info.Suppressions {
+			items = append(items, fmt.Sprintf("allow=%q", s.Text))
+		}
 
 		const open = "SuffixBlock("
 		pad := strings.Repeat(" ", len(open))
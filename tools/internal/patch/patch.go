@@ -0,0 +1,331 @@
+// Package patch parses unified diffs and applies them to text files.
+package patch
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// File is the set of changes a patch makes to one file.
+type File struct {
+	// OldName and NewName are the file names from the "---" and "+++"
+	// header lines, without timestamps or git's a/ and b/ prefixes.
+	OldName, NewName string
+	Hunks            []*Hunk
+}
+
+// Hunk is one "@@" section of a unified diff.
+type Hunk struct {
+	// OldStart and NewStart are the 1-based line numbers where the
+	// hunk starts in the old and new file, as given in the hunk
+	// header. OldLines and NewLines are the number of old and new
+	// lines in the hunk.
+	OldStart, OldLines int
+	NewStart, NewLines int
+
+	// Lines are the hunk's body lines, each starting with ' ', '-'
+	// or '+'. Line terminators are removed.
+	Lines []string
+	// PatchLine is the 1-based line number of the hunk header in
+	// the patch.
+	PatchLine int
+
+	// oldNoEOL and newNoEOL are whether the last old or new line of
+	// the hunk is at the end of the file and has no trailing newline.
+	oldNoEOL, newNoEOL bool
+}
+
+// Parse parses the unified diffs in bs. Text that isn't part of a
+// diff, such as email headers or a commit message, is ignored.
+func Parse(bs []byte) ([]*File, error) {
+	lines := strings.Split(string(bs), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSuffix(l, "\r")
+	}
+
+	var (
+		ret []*File
+		cur *File
+	)
+	for i := 0; i < len(lines); i++ {
+		l := lines[i]
+		switch {
+		case strings.HasPrefix(l, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ "):
+			cur = &File{
+				OldName: fileName(l[4:]),
+				NewName: fileName(lines[i+1][4:]),
+			}
+			ret = append(ret, cur)
+			i++
+		case strings.HasPrefix(l, "@@ ") && cur != nil:
+			h, err := parseHunkHeader(l)
+			if err != nil {
+				return nil, fmt.Errorf("patch line %d: %w", i+1, err)
+			}
+			h.PatchLine = i + 1
+			i, err = h.readBody(lines, i+1)
+			if err != nil {
+				return nil, err
+			}
+			cur.Hunks = append(cur.Hunks, h)
+		case cur != nil && len(cur.Hunks) > 0:
+			// Anything else ends the current file's diff.
+			cur = nil
+		}
+	}
+
+	if len(ret) == 0 {
+		return nil, fmt.Errorf("no unified diff found in patch")
+	}
+	for _, f := range ret {
+		if len(f.Hunks) == 0 {
+			return nil, fmt.Errorf("diff of %q has no hunks", f.NewName)
+		}
+	}
+	return ret, nil
+}
+
+// fileName extracts the file name from the value of a "---" or "+++"
+// header line.
+func fileName(s string) string {
+	// Diff tools separate an optional timestamp with a tab.
+	s, _, _ = strings.Cut(s, "\t")
+	s = strings.TrimSpace(s)
+	if s == "/dev/null" {
+		return s
+	}
+	if strings.HasPrefix(s, "a/") || strings.HasPrefix(s, "b/") {
+		s = s[2:]
+	}
+	return s
+}
+
+// parseHunkHeader parses a "@@ -a,b +c,d @@" line.
+func parseHunkHeader(l string) (*Hunk, error) {
+	fs := strings.Fields(l)
+	if len(fs) < 4 || fs[3] != "@@" || !strings.HasPrefix(fs[1], "-") || !strings.HasPrefix(fs[2], "+") {
+		return nil, fmt.Errorf("invalid hunk header %q", l)
+	}
+	oldStart, oldLines, err := parseRange(fs[1][1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid hunk header %q: %w", l, err)
+	}
+	newStart, newLines, err := parseRange(fs[2][1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid hunk header %q: %w", l, err)
+	}
+	return &Hunk{
+		OldStart: oldStart,
+		OldLines: oldLines,
+		NewStart: newStart,
+		NewLines: newLines,
+	}, nil
+}
+
+// parseRange parses the "start,count" part of a hunk header. The
+// count defaults to 1 when omitted.
+func parseRange(s string) (start, count int, err error) {
+	startStr, countStr, hasCount := strings.Cut(s, ",")
+	start, err = strconv.Atoi(startStr)
+	if err != nil || start < 0 {
+		return 0, 0, fmt.Errorf("invalid line number %q", startStr)
+	}
+	if !hasCount {
+		return start, 1, nil
+	}
+	count, err = strconv.Atoi(countStr)
+	if err != nil || count < 0 {
+		return 0, 0, fmt.Errorf("invalid line count %q", countStr)
+	}
+	return start, count, nil
+}
+
+// readBody reads the body of h from lines, starting at index start. It
+// returns the index of the last line consumed.
+func (h *Hunk) readBody(lines []string, start int) (int, error) {
+	nOld, nNew := 0, 0
+	i := start
+	for ; nOld < h.OldLines || nNew < h.NewLines; i++ {
+		if i >= len(lines) {
+			return 0, fmt.Errorf("patch line %d: hunk is truncated, want %d old and %d new lines, got %d and %d", h.PatchLine, h.OldLines, h.NewLines, nOld, nNew)
+		}
+		l := lines[i]
+		if l == "" {
+			// Email clients and editors sometimes strip the
+			// leading space of blank context lines.
+			l = " "
+		}
+		switch l[0] {
+		case ' ':
+			nOld++
+			nNew++
+		case '-':
+			nOld++
+		case '+':
+			nNew++
+		case '\\':
+			h.markNoEOL()
+			continue
+		default:
+			return 0, fmt.Errorf("patch line %d: unexpected line %q in hunk", i+1, l)
+		}
+		if nOld > h.OldLines || nNew > h.NewLines {
+			return 0, fmt.Errorf("patch line %d: hunk has more lines than its header says", i+1)
+		}
+		h.Lines = append(h.Lines, l)
+	}
+	if i < len(lines) && strings.HasPrefix(lines[i], "\\") {
+		h.markNoEOL()
+		i++
+	}
+	return i - 1, nil
+}
+
+// markNoEOL records a "\ No newline at end of file" marker, which
+// applies to the hunk line before it.
+func (h *Hunk) markNoEOL() {
+	if len(h.Lines) == 0 {
+		return
+	}
+	switch h.Lines[len(h.Lines)-1][0] {
+	case ' ':
+		h.oldNoEOL, h.newNoEOL = true, true
+	case '-':
+		h.oldNoEOL = true
+	case '+':
+		h.newNoEOL = true
+	}
+}
+
+// oldText and newText return the text of h's lines in the old and new
+// versions of the file, without the diff prefix.
+func (h *Hunk) oldText() []string { return h.text('-') }
+func (h *Hunk) newText() []string { return h.text('+') }
+
+func (h *Hunk) text(side byte) []string {
+	var ret []string
+	for _, l := range h.Lines {
+		if l[0] == ' ' || l[0] == side {
+			ret = append(ret, l[1:])
+		}
+	}
+	return ret
+}
+
+// Matches reports whether f's name matches name, ignoring
+// directories.
+func (f *File) Matches(name string) bool {
+	n := f.NewName
+	if n == "/dev/null" {
+		n = f.OldName
+	}
+	return path.Base(n) == path.Base(name)
+}
+
+// Apply applies the changes in f to base, and returns the result.
+//
+// Like git apply, hunks may apply at a different line than their
+// header says, if base has moved lines around, but the context and
+// removed lines of each hunk must match base exactly. Apply returns
+// an error describing the first hunk that doesn't apply.
+func (f *File) Apply(base []byte) ([]byte, error) {
+	src := string(base)
+	crlf := strings.Contains(src, "\r\n")
+	eol := "\n"
+	if crlf {
+		eol = "\r\n"
+	}
+	noEOL := src != "" && !strings.HasSuffix(src, "\n")
+	src = strings.TrimSuffix(src, "\n")
+	var lines []string
+	if src != "" {
+		lines = strings.Split(src, "\n")
+	}
+	for i, l := range lines {
+		lines[i] = strings.TrimSuffix(l, "\r")
+	}
+
+	var out []string
+	// next is the index of the first line of base that hasn't been
+	// copied to out yet.
+	next := 0
+	for i, h := range f.Hunks {
+		old := h.oldText()
+		want := h.OldStart - 1
+		if h.OldLines == 0 {
+			// Pure additions are positioned after the start line.
+			want = h.OldStart
+		}
+		at, ok := findHunk(lines, old, next, want)
+		if !ok {
+			return nil, hunkError(i+1, h, lines, old, want)
+		}
+		if h.oldNoEOL && (at+len(old) != len(lines) || !noEOL) {
+			return nil, fmt.Errorf("hunk #%d (patch line %d) does not apply: it expects line %d to be the end of a file with no trailing newline", i+1, h.PatchLine, at+len(old))
+		}
+		out = append(out, lines[next:at]...)
+		out = append(out, h.newText()...)
+		next = at + len(old)
+		if next == len(lines) {
+			noEOL = h.newNoEOL
+		}
+	}
+	out = append(out, lines[next:]...)
+
+	if len(out) == 0 {
+		return []byte{}, nil
+	}
+	ret := strings.Join(out, eol)
+	if !noEOL {
+		ret += eol
+	}
+	return []byte(ret), nil
+}
+
+// findHunk returns the index in lines at which old matches, searching
+// outwards from want but not before min.
+func findHunk(lines, old []string, min, want int) (int, bool) {
+	matches := func(at int) bool {
+		if at < min || at+len(old) > len(lines) {
+			return false
+		}
+		for i, l := range old {
+			if lines[at+i] != l {
+				return false
+			}
+		}
+		return true
+	}
+	for off := 0; want-off >= min || want+off <= len(lines); off++ {
+		if matches(want - off) {
+			return want - off, true
+		}
+		if matches(want + off) {
+			return want + off, true
+		}
+	}
+	return 0, false
+}
+
+// hunkError returns an error explaining why h, the nth hunk, doesn't
+// apply at line want of lines.
+func hunkError(n int, h *Hunk, lines, old []string, want int) error {
+	want = max(want, 0)
+	if want > len(lines) {
+		return fmt.Errorf("hunk #%d (patch line %d) does not apply: it starts at line %d, but the file has only %d lines", n, h.PatchLine, want+1, len(lines))
+	}
+	for i, l := range old {
+		at := want + i
+		if at >= len(lines) {
+			return fmt.Errorf("hunk #%d (patch line %d) does not apply: expected %q at line %d, but the file has only %d lines", n, h.PatchLine, l, at+1, len(lines))
+		}
+		if lines[at] != l {
+			return fmt.Errorf("hunk #%d (patch line %d) does not apply: expected %q at line %d, found %q", n, h.PatchLine, l, at+1, lines[at])
+		}
+	}
+	// The text is at the expected place, but an earlier hunk already
+	// consumed it.
+	return fmt.Errorf("hunk #%d (patch line %d) does not apply: it overlaps the previous hunk", n, h.PatchLine)
+}
@@ -0,0 +1,209 @@
+package patch
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func lines(ls ...string) string {
+	return strings.Join(ls, "\n") + "\n"
+}
+
+var testBase = lines(
+	"// ===BEGIN PRIVATE DOMAINS===",
+	"",
+	"// Alpha : https://alpha.net",
+	"alpha.net",
+	"",
+	"// Charlie : https://charlie.net",
+	"charlie.net",
+	"",
+	"// ===END PRIVATE DOMAINS===",
+)
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    string
+		patch   string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "git_format_patch",
+			base: testBase,
+			patch: lines(
+				"From 1234abcd Mon Sep 17 00:00:00 2001",
+				"From: Bravo <psl@bravo.net>",
+				"Subject: [PATCH] Add bravo.net",
+				"",
+				"---",
+				" public_suffix_list.dat | 4 ++++",
+				"",
+				"diff --git a/public_suffix_list.dat b/public_suffix_list.dat",
+				"index 1111111..2222222 100644",
+				"--- a/public_suffix_list.dat",
+				"+++ b/public_suffix_list.dat",
+				"@@ -3,6 +3,9 @@",
+				" // Alpha : https://alpha.net",
+				" alpha.net",
+				" ",
+				"+// Bravo : https://bravo.net",
+				"+bravo.net",
+				"+",
+				" // Charlie : https://charlie.net",
+				" charlie.net",
+				" ",
+				"-- ",
+				"2.40.0",
+			),
+			want: lines(
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Alpha : https://alpha.net",
+				"alpha.net",
+				"",
+				"// Bravo : https://bravo.net",
+				"bravo.net",
+				"",
+				"// Charlie : https://charlie.net",
+				"charlie.net",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+			),
+		},
+
+		{
+			name: "offset_and_stripped_blank_context",
+			base: lines("// header", "// more header") + testBase,
+			patch: lines(
+				"--- public_suffix_list.dat.orig\t2024-01-01 00:00:00",
+				"+++ public_suffix_list.dat\t2024-01-02 00:00:00",
+				"@@ -6,3 +6,3 @@",
+				"",
+				"-// Charlie : https://charlie.net",
+				"+// Charlie Corp : https://charlie.net",
+				" charlie.net",
+			),
+			want: lines("// header", "// more header") + strings.Replace(testBase, "// Charlie :", "// Charlie Corp :", 1),
+		},
+
+		{
+			name: "multiple_hunks",
+			base: testBase,
+			patch: lines(
+				"--- a/public_suffix_list.dat",
+				"+++ b/public_suffix_list.dat",
+				"@@ -4 +4,2 @@",
+				" alpha.net",
+				"+*.users.alpha.net",
+				"@@ -7,0 +8 @@",
+				"+*.users.charlie.net",
+			),
+			want: lines(
+				"// ===BEGIN PRIVATE DOMAINS===",
+				"",
+				"// Alpha : https://alpha.net",
+				"alpha.net",
+				"*.users.alpha.net",
+				"",
+				"// Charlie : https://charlie.net",
+				"charlie.net",
+				"*.users.charlie.net",
+				"",
+				"// ===END PRIVATE DOMAINS===",
+			),
+		},
+
+		{
+			name: "no_newline_at_eof",
+			base: testBase,
+			patch: lines(
+				"--- a/public_suffix_list.dat",
+				"+++ b/public_suffix_list.dat",
+				"@@ -9 +9 @@",
+				"-// ===END PRIVATE DOMAINS===",
+				"+// ===END PRIVATE DOMAINS===",
+				`\ No newline at end of file`,
+			),
+			want: strings.TrimSuffix(testBase, "\n"),
+		},
+
+		{
+			name: "context_mismatch",
+			base: testBase,
+			patch: lines(
+				"--- a/public_suffix_list.dat",
+				"+++ b/public_suffix_list.dat",
+				"@@ -3,2 +3,2 @@",
+				" // Alpha : https://alpha.example",
+				"-alpha.net",
+				"+alpha.org",
+			),
+			wantErr: `hunk #1 (patch line 3) does not apply: expected "// Alpha : https://alpha.example" at line 3, found "// Alpha : https://alpha.net"`,
+		},
+
+		{
+			name: "past_end",
+			base: testBase,
+			patch: lines(
+				"--- a/public_suffix_list.dat",
+				"+++ b/public_suffix_list.dat",
+				"@@ -9,2 +9,2 @@",
+				" // ===END PRIVATE DOMAINS===",
+				"-// trailer",
+				"+// new trailer",
+			),
+			wantErr: `hunk #1 (patch line 3) does not apply: expected "// trailer" at line 10, but the file has only 9 lines`,
+		},
+
+		{
+			name: "truncated",
+			base: testBase,
+			patch: lines(
+				"--- a/public_suffix_list.dat",
+				"+++ b/public_suffix_list.dat",
+				"@@ -3,3 +3,3 @@",
+				" // Alpha : https://alpha.net",
+			),
+			wantErr: "patch line 3: hunk is truncated, want 3 old and 3 new lines, got 2 and 2",
+		},
+
+		{
+			name:    "not_a_diff",
+			base:    testBase,
+			patch:   lines("Hello, please add my domain!"),
+			wantErr: "no unified diff found in patch",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			files, err := Parse([]byte(tc.patch))
+			var got []byte
+			if err == nil {
+				if len(files) != 1 {
+					t.Fatalf("Parse returned %d files, want 1", len(files))
+				}
+				if !files[0].Matches("public_suffix_list.dat") {
+					t.Errorf("patch for %q doesn't match public_suffix_list.dat", files[0].NewName)
+				}
+				got, err = files[0].Apply([]byte(tc.base))
+			}
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("got error %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(string(got), tc.want); diff != "" {
+				t.Errorf("wrong patched output (-got+want):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package serve
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// endpoints are the names of the lookup endpoints, as used in metric
+// labels.
+var endpoints = []string{"public-suffix", "registered-domain", "rules", "batch"}
+
+// metrics are the server's counters, exported in the Prometheus text
+// format.
+type metrics struct {
+	// lookups and lookupErrors count hostnames looked up and
+	// hostnames that failed to parse, by endpoint.
+	lookups      counterVec
+	lookupErrors counterVec
+	// reloads and reloadFailures count successful and failed loads
+	// of a new version of the PSL file.
+	reloads        atomic.Int64
+	reloadFailures atomic.Int64
+}
+
+func newMetrics() metrics {
+	return metrics{
+		lookups:      newCounterVec(endpoints),
+		lookupErrors: newCounterVec(endpoints),
+	}
+}
+
+// counterVec is a set of counters with a fixed set of label values.
+type counterVec map[string]*atomic.Int64
+
+func newCounterVec(labels []string) counterVec {
+	ret := counterVec{}
+	for _, l := range labels {
+		ret[l] = new(atomic.Int64)
+	}
+	return ret
+}
+
+func (c counterVec) add(label string, n int64) {
+	c[label].Add(n)
+}
+
+// write writes m to w in the Prometheus text format, along with
+// information about the served list.
+func (m *metrics) write(w io.Writer, info ListInfo) {
+	metric := func(name, typ, help string) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	}
+	vec := func(name, label string, c counterVec) {
+		keys := make([]string, 0, len(c))
+		for k := range c {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			fmt.Fprintf(w, "%s{%s=%s} %d\n", name, label, quoteLabel(k), c[k].Load())
+		}
+	}
+
+	metric("psl_lookups_total", "counter", "Number of hostnames looked up, by endpoint.")
+	vec("psl_lookups_total", "endpoint", m.lookups)
+	metric("psl_lookup_errors_total", "counter", "Number of invalid hostnames in lookups, by endpoint.")
+	vec("psl_lookup_errors_total", "endpoint", m.lookupErrors)
+
+	metric("psl_reloads_total", "counter", "Number of times a new version of the PSL file was loaded.")
+	fmt.Fprintf(w, "psl_reloads_total %d\n", m.reloads.Load())
+	metric("psl_reload_failures_total", "counter", "Number of times loading a new version of the PSL file failed.")
+	fmt.Fprintf(w, "psl_reload_failures_total %d\n", m.reloadFailures.Load())
+
+	metric("psl_list_rules", "gauge", "Number of rules in the served PSL file.")
+	fmt.Fprintf(w, "psl_list_rules %d\n", info.Rules)
+	metric("psl_list_loaded_timestamp_seconds", "gauge", "Unix time at which the served PSL file was loaded.")
+	fmt.Fprintf(w, "psl_list_loaded_timestamp_seconds %d\n", info.LoadedAt.Unix())
+	metric("psl_list_info", "gauge", "Version of the served PSL file.")
+	fmt.Fprintf(w, "psl_list_info{sha256=%s,version=%s,commit=%s} 1\n", quoteLabel(info.SHA256), quoteLabel(info.Version), quoteLabel(info.Commit))
+}
+
+// labelEscaper escapes label values in the Prometheus text format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func quoteLabel(s string) string {
+	return `"` + labelEscaper.Replace(s) + `"`
+}
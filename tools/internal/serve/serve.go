@@ -0,0 +1,487 @@
+// Package serve implements an HTTP API for public suffix lookups in a
+// PSL file.
+//
+// The server answers with parser.List semantics, and reloads the PSL
+// file and its overlays when they change. Each request is answered
+// entirely from one version of the file, a reload never affects
+// requests in flight.
+package serve
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/publicsuffix/list/tools/internal/domain"
+	"github.com/publicsuffix/list/tools/internal/parser"
+)
+
+// maxBatchHosts is the maximum number of hostnames in one batch
+// request.
+const maxBatchHosts = 1000
+
+// maxBodyBytes is the maximum size of a batch request body.
+const maxBodyBytes = 1 << 20
+
+// Server is an HTTP handler that answers public suffix queries for a
+// PSL file.
+type Server struct {
+	path     string
+	overlays []string
+	mux      *http.ServeMux
+
+	// cur is the currently served version of the PSL file.
+	cur atomic.Pointer[snapshot]
+
+	// reloadMu serializes reloads.
+	reloadMu sync.Mutex
+	// lastStat is the modification time and size of the PSL file
+	// and overlays when they were last read, to skip reading
+	// unchanged files.
+	lastStat []fileStat
+	// lastErr is the error of the most recent failed reload, cleared
+	// by the next successful one.
+	lastErr atomic.Pointer[string]
+
+	metrics metrics
+}
+
+// snapshot is one loaded version of the PSL file.
+type snapshot struct {
+	list *parser.List
+	info ListInfo
+}
+
+// ListInfo describes the version of the PSL file that the server is
+// using.
+type ListInfo struct {
+	// Path is the path of the PSL file.
+	Path string `json:"path"`
+	// Version and Commit are the values of the "// VERSION:" and
+	// "// COMMIT:" lines that publicsuffix.org adds to the header of
+	// published copies of the list, if present.
+	Version string `json:"version,omitempty"`
+	Commit  string `json:"commit,omitempty"`
+	// SHA256 is the hex SHA-256 hash of the file.
+	SHA256 string `json:"sha256"`
+	// Rules is the number of rules in the file. A wildcard with
+	// exceptions counts as one rule.
+	Rules int `json:"rules"`
+	// LoadedAt is when the file was loaded.
+	LoadedAt time.Time `json:"loaded_at"`
+	// Overlays are the overlay files applied to the PSL file, in
+	// the order they are applied.
+	Overlays []OverlayInfo `json:"overlays,omitempty"`
+}
+
+// OverlayInfo describes an overlay file applied to the served list.
+type OverlayInfo struct {
+	// Path is the path of the overlay file.
+	Path string `json:"path"`
+	// SHA256 is the hex SHA-256 hash of the file.
+	SHA256 string `json:"sha256"`
+}
+
+// hashes returns the hashes of the PSL file and overlays described
+// by i.
+func (i ListInfo) hashes() []string {
+	ret := []string{i.SHA256}
+	for _, o := range i.Overlays {
+		ret = append(ret, o.SHA256)
+	}
+	return ret
+}
+
+type fileStat struct {
+	modTime time.Time
+	size    int64
+}
+
+// New returns a Server for the PSL file at path, with the overlay
+// files at overlays applied to it in order (see parser.Overlay). The
+// files are loaded immediately, and must parse and apply without
+// errors.
+func New(path string, overlays ...string) (*Server, error) {
+	s := &Server{
+		path:     path,
+		overlays: overlays,
+		mux:      http.NewServeMux(),
+		metrics:  newMetrics(),
+	}
+	if _, err := s.Reload(); err != nil {
+		return nil, err
+	}
+
+	s.handleLookup("GET /v1/public-suffix", "public-suffix", publicSuffixResult)
+	s.handleLookup("GET /v1/registered-domain", "registered-domain", registeredDomainResult)
+	s.handleLookup("GET /v1/rules", "rules", rulesResult)
+	s.mux.HandleFunc("POST /v1/batch", s.serveBatch)
+	s.mux.HandleFunc("GET /v1/list", s.serveList)
+	s.mux.HandleFunc("GET /healthz", s.serveHealth)
+	s.mux.HandleFunc("GET /metrics", s.serveMetrics)
+
+	return s, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Info returns information about the currently served version of
+// the PSL file.
+func (s *Server) Info() ListInfo {
+	return s.cur.Load().info
+}
+
+// Reload rereads the PSL file and overlays if any of them changed
+// since they were last read, and reports whether a new version was
+// loaded.
+//
+// If a file can't be read or has parse errors, or an overlay doesn't
+// apply, Reload returns an error and the server keeps serving the
+// previous version.
+func (s *Server) Reload() (bool, error) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	changed, err := s.reload()
+	switch {
+	case err != nil:
+		msg := err.Error()
+		s.lastErr.Store(&msg)
+		s.metrics.reloadFailures.Add(1)
+	case changed:
+		s.lastErr.Store(nil)
+		s.metrics.reloads.Add(1)
+	}
+	return changed, err
+}
+
+func (s *Server) reload() (bool, error) {
+	paths := append([]string{s.path}, s.overlays...)
+	stats := make([]fileStat, len(paths))
+	for i, path := range paths {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return false, err
+		}
+		stats[i] = fileStat{fi.ModTime(), fi.Size()}
+	}
+	if slices.Equal(stats, s.lastStat) {
+		return false, nil
+	}
+
+	files := make([][]byte, len(paths))
+	hashes := make([]string, len(paths))
+	for i, path := range paths {
+		bs, err := os.ReadFile(path)
+		if err != nil {
+			return false, err
+		}
+		sum := sha256.Sum256(bs)
+		files[i], hashes[i] = bs, hex.EncodeToString(sum[:])
+	}
+	s.lastStat = stats
+	if cur := s.cur.Load(); cur != nil && slices.Equal(cur.info.hashes(), hashes) {
+		// Touched, but not changed.
+		return false, nil
+	}
+
+	psl, errs := parser.Parse(files[0])
+	if len(errs) > 0 {
+		return false, fmt.Errorf("loading %s: %w", s.path, errors.Join(errs...))
+	}
+	for i, path := range s.overlays {
+		o, errs := parser.ParseOverlay(path, files[i+1])
+		if len(errs) > 0 {
+			return false, fmt.Errorf("loading %s: %w", path, errors.Join(errs...))
+		}
+		if err := psl.ApplyOverlay(o); err != nil {
+			return false, err
+		}
+	}
+
+	info := ListInfo{
+		Path:     s.path,
+		SHA256:   hashes[0],
+		Rules:    len(parser.BlocksOfType[*parser.Suffix](psl)) + len(parser.BlocksOfType[*parser.Wildcard](psl)),
+		LoadedAt: time.Now().UTC(),
+	}
+	info.Version, info.Commit = headerVersion(files[0])
+	for i, path := range s.overlays {
+		info.Overlays = append(info.Overlays, OverlayInfo{path, hashes[i+1]})
+	}
+	s.cur.Store(&snapshot{psl, info})
+	return true, nil
+}
+
+// Watch checks the PSL file and overlays for changes every interval,
+// and reloads them when they change. Watch returns when ctx is canceled.
+func (s *Server) Watch(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			changed, err := s.Reload()
+			if err != nil {
+				log.Printf("Reloading PSL failed, still serving sha256 %s: %v", s.Info().SHA256, err)
+			} else if changed {
+				log.Printf("Reloaded PSL, now serving sha256 %s", s.Info().SHA256)
+			}
+		}
+	}
+}
+
+// headerVersion returns the values of the "// VERSION:" and
+// "// COMMIT:" lines in the header of a published PSL file.
+func headerVersion(bs []byte) (version, commit string) {
+	for _, line := range bytes.Split(bs, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if !bytes.HasPrefix(line, []byte("//")) {
+			if len(line) > 0 {
+				// End of the header.
+				break
+			}
+			continue
+		}
+		text := strings.TrimSpace(strings.TrimPrefix(string(line), "//"))
+		if v, ok := strings.CutPrefix(text, "VERSION:"); ok {
+			version = strings.TrimSpace(v)
+		} else if v, ok := strings.CutPrefix(text, "COMMIT:"); ok {
+			commit = strings.TrimSpace(v)
+		}
+		if strings.HasPrefix(text, "===BEGIN ") {
+			break
+		}
+	}
+	return version, commit
+}
+
+// Lookup is the answer to a lookup for one hostname. Which fields are
+// set depends on the endpoint.
+type Lookup struct {
+	// Host is the queried hostname in canonical form.
+	Host string `json:"host"`
+	// Error is the reason the hostname couldn't be looked up, in
+	// batch results.
+	Error string `json:"error,omitempty"`
+
+	PublicSuffix      string `json:"public_suffix,omitempty"`
+	PublicSuffixASCII string `json:"public_suffix_ascii,omitempty"`
+	// RegisteredDomain is empty when Host is itself a public
+	// suffix.
+	RegisteredDomain      string `json:"registered_domain,omitempty"`
+	RegisteredDomainASCII string `json:"registered_domain_ascii,omitempty"`
+	IsPublicSuffix        *bool  `json:"is_public_suffix,omitempty"`
+
+	// Rules are the rules that match Host, in file order.
+	Rules []Rule `json:"rules,omitempty"`
+	// ImplicitRule is whether no rule matched, and the public suffix
+	// comes from the PSL algorithm's implicit "*" rule.
+	ImplicitRule bool `json:"implicit_rule,omitempty"`
+}
+
+// Rule is a rule that matches a hostname.
+type Rule struct {
+	// Rule is the rule in PSL syntax.
+	Rule string `json:"rule"`
+	// PublicSuffix is the public suffix according to this rule alone.
+	PublicSuffix string `json:"public_suffix"`
+	// Prevailing is whether this rule determines the public suffix
+	// of the hostname.
+	Prevailing bool `json:"prevailing"`
+	// Section is the name of the section containing the rule.
+	Section string `json:"section,omitempty"`
+	// Entity is the name of the suffix block containing the rule.
+	Entity string `json:"entity,omitempty"`
+	// Overlay is the path of the overlay file that added the rule,
+	// or empty if the rule is from the PSL file itself.
+	Overlay string `json:"overlay,omitempty"`
+	// Line is the 1-based line number of the rule in the PSL file,
+	// or in the overlay file for rules added by an overlay. For
+	// wildcard exceptions, it is the line of the wildcard.
+	Line int `json:"line"`
+}
+
+// lookupFunc computes the answer for host according to psl.
+type lookupFunc func(psl *parser.List, host domain.Name) Lookup
+
+func publicSuffixResult(psl *parser.List, host domain.Name) Lookup {
+	ret := Lookup{Host: host.String()}
+	ret.setPublicSuffix(psl.PublicSuffix(host))
+	return ret
+}
+
+func registeredDomainResult(psl *parser.List, host domain.Name) Lookup {
+	ret := Lookup{Host: host.String()}
+	ret.setRegisteredDomain(psl.RegisteredDomain(host))
+	return ret
+}
+
+// fullResult returns the public suffix and registered domain of host,
+// and optionally the rules that match it.
+func fullResult(psl *parser.List, host domain.Name, withRules bool) Lookup {
+	ret := Lookup{Host: host.String()}
+	suffix := psl.PublicSuffix(host)
+	ret.setPublicSuffix(suffix)
+	ret.setRegisteredDomain(psl.RegisteredDomain(host))
+	if !withRules {
+		return ret
+	}
+
+	matches := psl.MatchingRules(host)
+	hasException := slices.ContainsFunc(matches, func(m parser.RuleMatch) bool { return m.IsException })
+	for _, m := range matches {
+		r := Rule{
+			Rule:         m.Text,
+			PublicSuffix: m.PublicSuffix.String(),
+			Prevailing:   m.PublicSuffix.Equal(suffix) && m.IsException == hasException,
+			Overlay:      m.Overlay,
+			Line:         m.Rule.SrcRange().FirstLine + 1,
+		}
+		if m.Section != nil {
+			r.Section = m.Section.Name
+		}
+		if m.Suffixes != nil {
+			r.Entity = m.Suffixes.Info.Name
+		}
+		ret.Rules = append(ret.Rules, r)
+	}
+	ret.ImplicitRule = len(matches) == 0
+	return ret
+}
+
+func rulesResult(psl *parser.List, host domain.Name) Lookup {
+	return fullResult(psl, host, true)
+}
+
+func (l *Lookup) setPublicSuffix(suffix domain.Name) {
+	l.PublicSuffix = suffix.String()
+	l.PublicSuffixASCII = suffix.ASCIIString()
+}
+
+func (l *Lookup) setRegisteredDomain(reg domain.Name, ok bool) {
+	isPublicSuffix := !ok
+	l.IsPublicSuffix = &isPublicSuffix
+	if ok {
+		l.RegisteredDomain = reg.String()
+		l.RegisteredDomainASCII = reg.ASCIIString()
+	}
+}
+
+// handleLookup registers a handler for pattern that looks up the
+// hostname in the "host" query parameter.
+func (s *Server) handleLookup(pattern, endpoint string, fn lookupFunc) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		snap := s.cur.Load()
+		s.metrics.lookups.add(endpoint, 1)
+
+		host, err := domain.Parse(r.URL.Query().Get("host"))
+		if err != nil {
+			s.metrics.lookupErrors.add(endpoint, 1)
+			s.writeError(w, snap, http.StatusBadRequest, fmt.Errorf("invalid host: %w", err))
+			return
+		}
+		s.writeJSON(w, snap, http.StatusOK, fn(snap.list, host))
+	})
+}
+
+// batchRequest is the request body of the batch endpoint.
+type batchRequest struct {
+	Hosts []string `json:"hosts"`
+	// Rules is whether to include matching rules in the results.
+	Rules bool `json:"rules"`
+}
+
+func (s *Server) serveBatch(w http.ResponseWriter, r *http.Request) {
+	snap := s.cur.Load()
+
+	var req batchRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		s.writeError(w, snap, http.StatusBadRequest, fmt.Errorf("invalid batch request: %w", err))
+		return
+	}
+	if len(req.Hosts) > maxBatchHosts {
+		s.writeError(w, snap, http.StatusBadRequest, fmt.Errorf("too many hosts in batch request, got %d, maximum is %d", len(req.Hosts), maxBatchHosts))
+		return
+	}
+
+	s.metrics.lookups.add("batch", int64(len(req.Hosts)))
+	results := make([]Lookup, 0, len(req.Hosts))
+	for _, h := range req.Hosts {
+		host, err := domain.Parse(h)
+		if err != nil {
+			s.metrics.lookupErrors.add("batch", 1)
+			results = append(results, Lookup{Host: h, Error: err.Error()})
+			continue
+		}
+		res := fullResult(snap.list, host, req.Rules)
+		results = append(results, res)
+	}
+	s.writeJSON(w, snap, http.StatusOK, struct {
+		Results []Lookup `json:"results"`
+	}{results})
+}
+
+func (s *Server) serveList(w http.ResponseWriter, r *http.Request) {
+	snap := s.cur.Load()
+	s.writeJSON(w, snap, http.StatusOK, snap.info)
+}
+
+func (s *Server) serveHealth(w http.ResponseWriter, r *http.Request) {
+	snap := s.cur.Load()
+	resp := struct {
+		Status          string   `json:"status"`
+		List            ListInfo `json:"list"`
+		LastReloadError string   `json:"last_reload_error,omitempty"`
+	}{
+		Status: "ok",
+		List:   snap.info,
+	}
+	if err := s.lastErr.Load(); err != nil {
+		// Still healthy, the previous version of the list is
+		// served.
+		resp.LastReloadError = *err
+	}
+	s.writeJSON(w, snap, http.StatusOK, resp)
+}
+
+func (s *Server) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	s.metrics.write(w, s.cur.Load().info)
+}
+
+// writeJSON writes v as the JSON response to a request answered from
+// snap.
+func (s *Server) writeJSON(w http.ResponseWriter, snap *snapshot, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("PSL-SHA256", snap.info.SHA256)
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+func (s *Server) writeError(w http.ResponseWriter, snap *snapshot, status int, err error) {
+	s.writeJSON(w, snap, status, struct {
+		Error string `json:"error"`
+	}{err.Error()})
+}
@@ -0,0 +1,391 @@
+package serve
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func lines(ls ...string) string {
+	return strings.Join(ls, "\n") + "\n"
+}
+
+var testList = lines(
+	"// VERSION: 2024-06-01_00-00-00_UTC",
+	"// COMMIT: 0123456789abcdef",
+	"",
+	"// ===BEGIN ICANN DOMAINS===",
+	"",
+	"// com : https://www.iana.org/domains/root/db/com.html",
+	"com",
+	"",
+	"// ck : https://www.iana.org/domains/root/db/ck.html",
+	"*.ck",
+	"!www.ck",
+	"",
+	"// ===END ICANN DOMAINS===",
+	"// ===BEGIN PRIVATE DOMAINS===",
+	"",
+	"// Bravo : https://bravo.com",
+	"bravo.com",
+	"",
+	"// ===END PRIVATE DOMAINS===",
+)
+
+// newTestServer returns a server for a PSL file with the given
+// contents, and the path of that file.
+func newTestServer(t *testing.T, psl string) (*httptest.Server, *Server, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "public_suffix_list.dat")
+	if err := os.WriteFile(path, []byte(psl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	srv, err := New(path)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	hs := httptest.NewServer(srv)
+	t.Cleanup(hs.Close)
+	return hs, srv, path
+}
+
+// rewrite replaces the PSL file at path, making sure that its
+// modification time changes.
+func rewrite(t *testing.T, path, psl string) {
+	t.Helper()
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(psl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := fi.ModTime().Add(time.Second)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func get(t *testing.T, hs *httptest.Server, path string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(hs.URL + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(bs)
+}
+
+func getJSON(t *testing.T, hs *httptest.Server, path string, wantStatus int) map[string]any {
+	t.Helper()
+	status, body := get(t, hs, path)
+	if status != wantStatus {
+		t.Fatalf("GET %s: got status %d, want %d. Body:\n%s", path, status, wantStatus, body)
+	}
+	var ret map[string]any
+	if err := json.Unmarshal([]byte(body), &ret); err != nil {
+		t.Fatalf("GET %s: invalid JSON: %v\n%s", path, err, body)
+	}
+	return ret
+}
+
+func TestLookups(t *testing.T) {
+	hs, _, _ := newTestServer(t, testList)
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		want       map[string]any
+	}{
+		{
+			path:       "/v1/public-suffix?host=www.Bravo.com",
+			wantStatus: http.StatusOK,
+			want: map[string]any{
+				"host":                "www.bravo.com",
+				"public_suffix":       "bravo.com",
+				"public_suffix_ascii": "bravo.com",
+			},
+		},
+		{
+			path:       "/v1/public-suffix?host=b%C3%BCcher.foo.ck",
+			wantStatus: http.StatusOK,
+			want: map[string]any{
+				"host":                "bücher.foo.ck",
+				"public_suffix":       "foo.ck",
+				"public_suffix_ascii": "foo.ck",
+			},
+		},
+		{
+			path:       "/v1/registered-domain?host=a.b.example.com",
+			wantStatus: http.StatusOK,
+			want: map[string]any{
+				"host":                    "a.b.example.com",
+				"registered_domain":       "example.com",
+				"registered_domain_ascii": "example.com",
+				"is_public_suffix":        false,
+			},
+		},
+		{
+			path:       "/v1/registered-domain?host=bravo.com",
+			wantStatus: http.StatusOK,
+			want: map[string]any{
+				"host":             "bravo.com",
+				"is_public_suffix": true,
+			},
+		},
+		{
+			path:       "/v1/rules?host=a.www.ck",
+			wantStatus: http.StatusOK,
+			want: map[string]any{
+				"host":                    "a.www.ck",
+				"public_suffix":           "ck",
+				"public_suffix_ascii":     "ck",
+				"registered_domain":       "www.ck",
+				"registered_domain_ascii": "www.ck",
+				"is_public_suffix":        false,
+				"rules": []any{
+					map[string]any{
+						"rule":          "!www.ck",
+						"public_suffix": "ck",
+						"prevailing":    true,
+						"section":       "ICANN DOMAINS",
+						"entity":        "ck",
+						"line":          10.0,
+					},
+				},
+			},
+		},
+		{
+			path:       "/v1/rules?host=foo.example",
+			wantStatus: http.StatusOK,
+			want: map[string]any{
+				"host":                    "foo.example",
+				"public_suffix":           "example",
+				"public_suffix_ascii":     "example",
+				"registered_domain":       "foo.example",
+				"registered_domain_ascii": "foo.example",
+				"is_public_suffix":        false,
+				"implicit_rule":           true,
+			},
+		},
+		{
+			path:       "/v1/public-suffix?host=a..com",
+			wantStatus: http.StatusBadRequest,
+			want: map[string]any{
+				"error": "invalid host: label 2 is empty",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		got := getJSON(t, hs, tc.path, tc.wantStatus)
+		if diff := cmp.Diff(got, tc.want); diff != "" {
+			t.Errorf("GET %s: wrong response (-got+want):\n%s", tc.path, diff)
+		}
+	}
+}
+
+func TestBatch(t *testing.T) {
+	hs, _, _ := newTestServer(t, testList)
+
+	body := `{"hosts": ["www.bravo.com", "com", "not..valid"]}`
+	resp, err := http.Post(hs.URL+"/v1/batch", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var got map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"results": []any{
+			map[string]any{
+				"host":                    "www.bravo.com",
+				"public_suffix":           "bravo.com",
+				"public_suffix_ascii":     "bravo.com",
+				"registered_domain":       "www.bravo.com",
+				"registered_domain_ascii": "www.bravo.com",
+				"is_public_suffix":        false,
+			},
+			map[string]any{
+				"host":                "com",
+				"public_suffix":       "com",
+				"public_suffix_ascii": "com",
+				"is_public_suffix":    true,
+			},
+			map[string]any{
+				"host":  "not..valid",
+				"error": "label 2 is empty",
+			},
+		},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("wrong batch response (-got+want):\n%s", diff)
+	}
+
+	tooMany := `{"hosts": [` + strings.Repeat(`"a.com",`, maxBatchHosts) + `"a.com"]}`
+	resp, err = http.Post(hs.URL+"/v1/batch", "application/json", strings.NewReader(tooMany))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("oversized batch: got status %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
+
+func TestReload(t *testing.T) {
+	hs, srv, path := newTestServer(t, testList)
+
+	info := srv.Info()
+	if info.Version != "2024-06-01_00-00-00_UTC" || info.Commit != "0123456789abcdef" || info.Rules != 3 {
+		t.Errorf("wrong list info: %+v", info)
+	}
+
+	// Unchanged file.
+	if changed, err := srv.Reload(); changed || err != nil {
+		t.Errorf("Reload of unchanged file = %v, %v, want false, nil", changed, err)
+	}
+
+	// Add a suffix.
+	rewrite(t, path, strings.Replace(testList, "bravo.com\n", "bravo.com\n*.users.bravo.com\n", 1))
+	if changed, err := srv.Reload(); !changed || err != nil {
+		t.Fatalf("Reload of changed file = %v, %v, want true, nil", changed, err)
+	}
+	got := getJSON(t, hs, "/v1/public-suffix?host=a.b.users.bravo.com", http.StatusOK)
+	if got["public_suffix"] != "b.users.bravo.com" {
+		t.Errorf("after reload, got public suffix %q, want %q", got["public_suffix"], "b.users.bravo.com")
+	}
+	if srv.Info().SHA256 == info.SHA256 {
+		t.Errorf("list hash didn't change after reload")
+	}
+
+	// A broken file keeps the previous version in service.
+	info = srv.Info()
+	rewrite(t, path, strings.Replace(testList, "// ===END PRIVATE DOMAINS===", "", 1))
+	if changed, err := srv.Reload(); changed || err == nil {
+		t.Fatalf("Reload of broken file = %v, %v, want false, error", changed, err)
+	}
+	if diff := cmp.Diff(srv.Info(), info); diff != "" {
+		t.Errorf("broken file replaced the served list (-got+want):\n%s", diff)
+	}
+	health := getJSON(t, hs, "/healthz", http.StatusOK)
+	if health["last_reload_error"] == nil {
+		t.Errorf("health check doesn't report the failed reload: %v", health)
+	}
+
+	_, metrics := get(t, hs, "/metrics")
+	for _, want := range []string{
+		"psl_reloads_total 2\n",
+		"psl_reload_failures_total 1\n",
+		"psl_list_rules 4\n",
+		`psl_lookups_total{endpoint="public-suffix"} 1` + "\n",
+		`psl_list_info{sha256="` + info.SHA256 + `",version="2024-06-01_00-00-00_UTC",commit="0123456789abcdef"} 1` + "\n",
+	} {
+		if !strings.Contains(metrics, want) {
+			t.Errorf("metrics missing %q, got:\n%s", want, metrics)
+		}
+	}
+}
+
+func TestOverlays(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "public_suffix_list.dat")
+	overlay := filepath.Join(dir, "corp.dat")
+	overlayText := lines(
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"",
+		"// Corp : https://corp.example.com",
+		"*.corp.example.com",
+		"",
+		"// ===END PRIVATE DOMAINS===",
+		"",
+		"// ===BEGIN REMOVED DOMAINS===",
+		"",
+		"bravo.com",
+		"",
+		"// ===END REMOVED DOMAINS===",
+	)
+	for p, text := range map[string]string{path: testList, overlay: overlayText} {
+		if err := os.WriteFile(p, []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv, err := New(path, overlay)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	hs := httptest.NewServer(srv)
+	t.Cleanup(hs.Close)
+
+	got := getJSON(t, hs, "/v1/rules?host=a.b.corp.example.com", http.StatusOK)
+	want := map[string]any{
+		"host":                    "a.b.corp.example.com",
+		"public_suffix":           "b.corp.example.com",
+		"public_suffix_ascii":     "b.corp.example.com",
+		"registered_domain":       "a.b.corp.example.com",
+		"registered_domain_ascii": "a.b.corp.example.com",
+		"is_public_suffix":        false,
+		"rules": []any{
+			map[string]any{
+				"rule":          "com",
+				"public_suffix": "com",
+				"prevailing":    false,
+				"section":       "ICANN DOMAINS",
+				"entity":        "com",
+				"line":          7.0,
+			},
+			map[string]any{
+				"rule":          "*.corp.example.com",
+				"public_suffix": "b.corp.example.com",
+				"prevailing":    true,
+				"section":       "PRIVATE DOMAINS",
+				"entity":        "Corp",
+				"overlay":       overlay,
+				"line":          4.0,
+			},
+		},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("wrong rules with overlay (-got+want):\n%s", diff)
+	}
+	got = getJSON(t, hs, "/v1/public-suffix?host=www.bravo.com", http.StatusOK)
+	if got["public_suffix"] != "com" {
+		t.Errorf("rule removed by overlay still applies, got public suffix %q", got["public_suffix"])
+	}
+
+	// Overlay changes are picked up like changes to the PSL file.
+	info := srv.Info()
+	rewrite(t, overlay, strings.Replace(overlayText, "bravo.com\n", "", 1))
+	if changed, err := srv.Reload(); !changed || err != nil {
+		t.Fatalf("Reload of changed overlay = %v, %v, want true, nil", changed, err)
+	}
+	got = getJSON(t, hs, "/v1/public-suffix?host=www.bravo.com", http.StatusOK)
+	if got["public_suffix"] != "bravo.com" {
+		t.Errorf("after overlay reload, got public suffix %q, want %q", got["public_suffix"], "bravo.com")
+	}
+	newInfo := srv.Info()
+	if newInfo.SHA256 != info.SHA256 || newInfo.Overlays[0].SHA256 == info.Overlays[0].SHA256 {
+		t.Errorf("wrong hashes after overlay reload, got %+v, was %+v", newInfo, info)
+	}
+
+	// An overlay that doesn't apply keeps the previous version in
+	// service.
+	rewrite(t, overlay, strings.Replace(overlayText, "bravo.com", "charlie.com", 1))
+	if changed, err := srv.Reload(); changed || err == nil {
+		t.Fatalf("Reload of broken overlay = %v, %v, want false, error", changed, err)
+	}
+}
@@ -0,0 +1,216 @@
+package xnetdiff
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+)
+
+// xnetModule is the module that provides the reference
+// implementation.
+const xnetModule = "golang.org/x/net"
+
+// XNet is a build of golang.org/x/net/publicsuffix that uses a table
+// generated from a specific PSL file, instead of the table that
+// x/net embeds.
+type XNet struct {
+	dir string // temporary Go module containing the build
+	bin string // compiled lookup program
+}
+
+// BuildXNet generates x/net/publicsuffix's table from the PSL file
+// contents psl, and compiles a program that looks up public suffixes
+// in it.
+//
+// The build uses x/net's own table generator and the version of x/net
+// that this program was built with, and requires a Go toolchain in
+// $PATH. The x/net module is fetched from the module cache if
+// possible.
+func BuildXNet(ctx context.Context, psl []byte) (ret *XNet, err error) {
+	version, err := xnetVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "psl-xnetdiff-")
+	if err != nil {
+		return nil, err
+	}
+	ret = &XNet{
+		dir: dir,
+		bin: filepath.Join(dir, "lookup"),
+	}
+	defer func() {
+		if err != nil {
+			ret.Close()
+			ret = nil
+		}
+	}()
+
+	goMod := fmt.Sprintf("module xnetdiff\n\ngo 1.23\n\nrequire %s %s\n", xnetModule, version)
+	if err := writeFiles(dir, map[string]string{
+		"go.mod":  goMod,
+		"main.go": lookupMain,
+	}); err != nil {
+		return nil, err
+	}
+
+	out, err := ret.goCmd(ctx, dir, nil, "mod", "download", "-json", xnetModule+"@"+version)
+	if err != nil {
+		return nil, err
+	}
+	var mod struct{ Dir string }
+	if err := json.Unmarshal(out, &mod); err != nil {
+		return nil, fmt.Errorf("parsing go mod download output: %w", err)
+	}
+
+	// Copy the lookup code and table generator of x/net into the
+	// temporary module, so that the generated table replaces the
+	// embedded one.
+	pkgDir := filepath.Join(dir, "publicsuffix")
+	if err := os.MkdirAll(filepath.Join(pkgDir, "data"), 0o755); err != nil {
+		return nil, err
+	}
+	for _, f := range []string{"list.go", "gen.go"} {
+		bs, err := os.ReadFile(filepath.Join(mod.Dir, "publicsuffix", f))
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(filepath.Join(pkgDir, f), bs, 0o644); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := ret.goCmd(ctx, pkgDir, psl, "run", "gen.go", "-url", "", "-version", "psltool xnetdiff"); err != nil {
+		return nil, fmt.Errorf("generating x/net table: %w", err)
+	}
+	if _, err := ret.goCmd(ctx, dir, nil, "build", "-o", ret.bin, "."); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// Close deletes the temporary files of x.
+func (x *XNet) Close() error {
+	return os.RemoveAll(x.dir)
+}
+
+// PublicSuffixes returns x/net's answer for each of hosts. hosts must
+// be in canonical ASCII form.
+func (x *XNet) PublicSuffixes(ctx context.Context, hosts []string) ([]Result, error) {
+	c := exec.CommandContext(ctx, x.bin)
+	c.Stdin = strings.NewReader(strings.Join(hosts, "\n") + "\n")
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	out, err := c.Output()
+	if err != nil {
+		return nil, fmt.Errorf("running x/net lookup: %w. stderr:\n%s", err, stderr.String())
+	}
+
+	ret := make([]Result, 0, len(hosts))
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		suffix, icann, ok := strings.Cut(sc.Text(), " ")
+		if !ok {
+			return nil, fmt.Errorf("unexpected x/net lookup output %q", sc.Text())
+		}
+		ret = append(ret, Result{
+			PublicSuffix: suffix,
+			ICANN:        icann == "true",
+		})
+	}
+	if len(ret) != len(hosts) {
+		return nil, fmt.Errorf("x/net lookup returned %d results for %d hosts", len(ret), len(hosts))
+	}
+	return ret, nil
+}
+
+// goCmd runs the go tool in dir with the given stdin, and returns its
+// stdout.
+func (x *XNet) goCmd(ctx context.Context, dir string, stdin []byte, args ...string) ([]byte, error) {
+	c := exec.CommandContext(ctx, "go", args...)
+	c.Dir = dir
+	c.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod")
+	if stdin != nil {
+		c.Stdin = bytes.NewReader(stdin)
+	}
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	out, err := c.Output()
+	if err != nil {
+		// Make the error show the go commandline and captured
+		// stderr, not just the plain "exited with code 1" error.
+		cmdline := append([]string{"go"}, args...)
+		var stderrStr string
+		if stderr.Len() != 0 {
+			stderrStr = "stderr:\n" + stderr.String()
+		}
+		return nil, fmt.Errorf("running %q: %w. %s", strings.Join(cmdline, " "), err, stderrStr)
+	}
+	return out, nil
+}
+
+// xnetVersion returns the version of x/net that this program was
+// built with.
+func xnetVersion() (string, error) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "", errors.New("no build information in binary, can't find x/net version")
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != xnetModule {
+			continue
+		}
+		if dep.Replace != nil {
+			return dep.Replace.Version, nil
+		}
+		return dep.Version, nil
+	}
+	return "", fmt.Errorf("%s is not a dependency of this binary", xnetModule)
+}
+
+func writeFiles(dir string, files map[string]string) error {
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookupMain is a program that reads hostnames from stdin, one per
+// line, and prints the public suffix and ICANN flag that x/net
+// returns for each one.
+const lookupMain = `package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"xnetdiff/publicsuffix"
+)
+
+func main() {
+	sc := bufio.NewScanner(os.Stdin)
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	for sc.Scan() {
+		suffix, icann := publicsuffix.PublicSuffix(sc.Text())
+		fmt.Fprintln(w, suffix, icann)
+	}
+	if err := sc.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`
@@ -0,0 +1,323 @@
+// Package xnetdiff compares the public suffix algorithm of package
+// parser against golang.org/x/net/publicsuffix, a widely used
+// independent implementation.
+//
+// Both implementations are loaded from the same PSL file and asked
+// for the public suffix of a large set of hostnames, and every
+// disagreement is reported along with the rules that match the
+// hostname.
+package xnetdiff
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/publicsuffix/list/tools/internal/domain"
+	"github.com/publicsuffix/list/tools/internal/parser"
+)
+
+// Result is the answer of a public suffix implementation for one
+// hostname.
+type Result struct {
+	// PublicSuffix is the public suffix of the hostname, in ASCII
+	// form.
+	PublicSuffix string
+	// ICANN is whether the public suffix comes from a rule in the
+	// ICANN section of the PSL.
+	ICANN bool
+}
+
+func (r Result) String() string {
+	section := "private"
+	if r.ICANN {
+		section = "ICANN"
+	}
+	return fmt.Sprintf("%q (%s)", r.PublicSuffix, section)
+}
+
+// Rule is a PSL rule that matches a hostname.
+type Rule struct {
+	parser.SourceRange
+	// Text is the rule in PSL format, for example "*.foo.com" or
+	// "!bar.foo.com".
+	Text string
+	// ICANN is whether the rule is in the ICANN section.
+	ICANN bool
+}
+
+func (r Rule) String() string {
+	return fmt.Sprintf("%q (%s)", r.Text, r.LocationString())
+}
+
+// Disagreement is a hostname for which parser and x/net return
+// different public suffixes.
+type Disagreement struct {
+	// Host is the hostname, in ASCII form.
+	Host string
+	// Parser and XNet are the answers of the two implementations.
+	Parser, XNet Result
+	// Rules are the rules that match Host.
+	Rules []Rule
+}
+
+func (d Disagreement) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: parser says %s, x/net says %s", d.Host, d.Parser, d.XNet)
+	if len(d.Rules) == 0 {
+		b.WriteString("\n  no matching rules, implicit \"*\" rule applies")
+	}
+	for _, r := range d.Rules {
+		fmt.Fprintf(&b, "\n  matching rule %s", r)
+	}
+	return b.String()
+}
+
+// Compare returns the hostnames on which l and x disagree. l and x
+// must be loaded from the same PSL file.
+func Compare(ctx context.Context, l *parser.List, x *XNet, hosts []domain.Name) ([]Disagreement, error) {
+	ascii := make([]string, 0, len(hosts))
+	for _, h := range hosts {
+		ascii = append(ascii, h.ASCIIString())
+	}
+	theirs, err := x.PublicSuffixes(ctx, ascii)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := indexRules(l)
+	var ret []Disagreement
+	for i, h := range hosts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		matches := rules.match(h)
+		suffix := l.PublicSuffix(h)
+		ours := Result{
+			PublicSuffix: suffix.ASCIIString(),
+			ICANN:        isICANN(suffix, matches),
+		}
+		if ours != theirs[i] {
+			d := Disagreement{
+				Host:   ascii[i],
+				Parser: ours,
+				XNet:   theirs[i],
+			}
+			for _, m := range matches {
+				d.Rules = append(d.Rules, m.Rule)
+			}
+			ret = append(ret, d)
+		}
+	}
+	return ret, nil
+}
+
+// isICANN reports whether the public suffix answer comes from ICANN
+// rules, given the rules that match the hostname.
+//
+// Package parser doesn't track which rule produced an answer, so this
+// works it out from the matching rules: an answer is ICANN if all the
+// rules that produce it are in the ICANN section, and private if any
+// of them is a private rule or if only the implicit "*" rule applies.
+func isICANN(suffix domain.Name, matches []match) bool {
+	found := false
+	for _, m := range matches {
+		if !m.suffix.Equal(suffix) {
+			continue
+		}
+		if !m.ICANN {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// match is a rule that matches a hostname, and the public suffix the
+// rule yields taken in isolation.
+type match struct {
+	Rule
+	suffix domain.Name
+}
+
+// ruleIndex is the rules of a List, indexed by TLD.
+type ruleIndex map[string][]indexedRule
+
+type indexedRule struct {
+	block parser.Block // *parser.Suffix or *parser.Wildcard
+	icann bool
+}
+
+func indexRules(l *parser.List) ruleIndex {
+	ret := ruleIndex{}
+	add := func(d domain.Name, r indexedRule) {
+		labels := d.Labels()
+		tld := labels[len(labels)-1].String()
+		ret[tld] = append(ret[tld], r)
+	}
+	for _, block := range l.Blocks {
+		icann := false
+		if s, ok := block.(*parser.Section); ok {
+			icann = s.Name == "ICANN DOMAINS"
+		}
+		for _, s := range parser.BlocksOfType[*parser.Suffix](block) {
+			add(s.Domain, indexedRule{s, icann})
+		}
+		for _, w := range parser.BlocksOfType[*parser.Wildcard](block) {
+			add(w.Domain, indexedRule{w, icann})
+		}
+	}
+	return ret
+}
+
+// match returns the rules that match h, in file order.
+func (idx ruleIndex) match(h domain.Name) []match {
+	labels := h.Labels()
+	tld := labels[len(labels)-1].String()
+
+	var ret []match
+	for _, r := range idx[tld] {
+		switch v := r.block.(type) {
+		case *parser.Suffix:
+			if suffix, ok := v.PublicSuffix(h); ok {
+				ret = append(ret, match{
+					Rule:   Rule{v.SourceRange, v.Domain.ASCIIString(), r.icann},
+					suffix: suffix,
+				})
+			}
+		case *parser.Wildcard:
+			suffix, isException, ok := v.PublicSuffix(h)
+			if !ok {
+				continue
+			}
+			text := "*." + v.Domain.ASCIIString()
+			if isException {
+				rest, _ := h.CutSuffix(v.Domain)
+				text = "!" + rest[len(rest)-1].ASCIIString() + "." + v.Domain.ASCIIString()
+			}
+			ret = append(ret, match{
+				Rule:   Rule{v.SourceRange, text, r.icann},
+				suffix: suffix,
+			})
+		}
+	}
+	slices.SortStableFunc(ret, func(a, b match) int {
+		return a.FirstLine - b.FirstLine
+	})
+	return ret
+}
+
+// idnLabels are internationalized labels used to build test
+// hostnames.
+var idnLabels = []string{
+	"bücher",
+	"münchen",
+	"пример",
+	"例え",
+	"δοκιμή",
+	"مثال",
+	"日本語",
+	"ドメイン",
+}
+
+// Hostnames returns hostnames that exercise the rules of l: every
+// suffix and wildcard with zero, one and two labels prepended, every
+// wildcard exception, and names under unknown TLDs. The prepended
+// labels are picked pseudorandomly using seed, from a mix of ASCII
+// labels, internationalized labels, and labels that appear in the
+// list's rules.
+func Hostnames(l *parser.List, seed uint64) []domain.Name {
+	rnd := rand.New(rand.NewPCG(seed, seed))
+
+	var ruleLabels []domain.Label
+	seenLabel := map[string]bool{}
+	addRuleLabels := func(d domain.Name) {
+		for _, label := range d.Labels() {
+			if !seenLabel[label.String()] {
+				seenLabel[label.String()] = true
+				ruleLabels = append(ruleLabels, label)
+			}
+		}
+	}
+	for _, s := range parser.BlocksOfType[*parser.Suffix](l) {
+		addRuleLabels(s.Domain)
+	}
+	for _, w := range parser.BlocksOfType[*parser.Wildcard](l) {
+		addRuleLabels(w.Domain)
+	}
+
+	randomLabel := func() domain.Label {
+		switch n := rnd.IntN(10); {
+		case n < 2 && len(ruleLabels) > 0:
+			return ruleLabels[rnd.IntN(len(ruleLabels))]
+		case n < 4:
+			return mustLabel(idnLabels[rnd.IntN(len(idnLabels))])
+		default:
+			const chars = "abcdefghijklmnopqrstuvwxyz0123456789"
+			bs := make([]byte, 1+rnd.IntN(12))
+			for i := range bs {
+				bs[i] = chars[rnd.IntN(len(chars))]
+			}
+			return mustLabel(string(bs))
+		}
+	}
+
+	var (
+		ret  []domain.Name
+		seen = map[string]bool{}
+	)
+	add := func(d domain.Name, prefix ...domain.Label) {
+		n, err := d.AddPrefix(prefix...)
+		if err != nil {
+			// Too long, or the random labels formed an invalid
+			// name.
+			return
+		}
+		if !seen[n.String()] {
+			seen[n.String()] = true
+			ret = append(ret, n)
+		}
+	}
+
+	for _, s := range parser.BlocksOfType[*parser.Suffix](l) {
+		add(s.Domain)
+		add(s.Domain, randomLabel())
+		add(s.Domain, randomLabel(), randomLabel())
+	}
+	for _, w := range parser.BlocksOfType[*parser.Wildcard](l) {
+		add(w.Domain)
+		add(w.Domain, randomLabel())
+		add(w.Domain, randomLabel(), randomLabel())
+		for _, exc := range w.Exceptions {
+			add(w.Domain, exc)
+			add(w.Domain, randomLabel(), exc)
+		}
+	}
+
+	// Unknown TLDs, and deep names under random rules.
+	for range 100 {
+		tld := randomLabel().AsTLD()
+		add(tld)
+		add(tld, randomLabel())
+		if len(ret) > 0 {
+			d := ret[rnd.IntN(len(ret))]
+			prefix := make([]domain.Label, 2+rnd.IntN(6))
+			for i := range prefix {
+				prefix[i] = randomLabel()
+			}
+			add(d, prefix...)
+		}
+	}
+
+	return ret
+}
+
+func mustLabel(s string) domain.Label {
+	ret, err := domain.ParseLabel(s)
+	if err != nil {
+		panic(fmt.Sprintf("invalid test label %q: %v", s, err))
+	}
+	return ret
+}
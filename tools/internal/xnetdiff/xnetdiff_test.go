@@ -0,0 +1,119 @@
+package xnetdiff
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/publicsuffix/list/tools/internal/parser"
+)
+
+func lines(ls ...string) string {
+	return strings.Join(ls, "\n") + "\n"
+}
+
+var testList = lines(
+	"// ===BEGIN ICANN DOMAINS===",
+	"",
+	"// com : https://www.iana.org/domains/root/db/com.html",
+	"com",
+	"",
+	"// ck : https://www.iana.org/domains/root/db/ck.html",
+	"*.ck",
+	"!www.ck",
+	"",
+	"// xn--p1ai (\"rf\", Russian-Cyrillic) : https://www.iana.org/domains/root/db/xn--p1ai.html",
+	"рф",
+	"",
+	"// ===END ICANN DOMAINS===",
+	"// ===BEGIN PRIVATE DOMAINS===",
+	"",
+	"// Bravo : https://bravo.com",
+	"bravo.com",
+	"*.users.bravo.com",
+	"!www.users.bravo.com",
+	"",
+	"// Charlie : https://charlie.рф",
+	"charlie.рф",
+	"",
+	"// ===END PRIVATE DOMAINS===",
+)
+
+func buildXNet(t *testing.T, psl string) *XNet {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping x/net build in short mode")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go tool not available")
+	}
+	x, err := BuildXNet(context.Background(), []byte(psl))
+	if err != nil {
+		t.Fatalf("building x/net: %v", err)
+	}
+	t.Cleanup(func() { x.Close() })
+	return x
+}
+
+func parse(t *testing.T, psl string) *parser.List {
+	t.Helper()
+	l, errs := parser.Parse([]byte(psl))
+	for _, err := range errs {
+		t.Fatalf("parse error: %v", err)
+	}
+	return l
+}
+
+func TestCompare(t *testing.T) {
+	x := buildXNet(t, testList)
+
+	l := parse(t, testList)
+	hosts := Hostnames(l, 1)
+	diffs, err := Compare(context.Background(), l, x, hosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range diffs {
+		t.Errorf("unexpected disagreement: %s", d)
+	}
+
+	// Compare against a list with some rules changed, to check that
+	// disagreements are found and reported correctly.
+	changed := strings.NewReplacer(
+		"!www.ck", "!api.ck",
+		"charlie.рф", "charlie.com",
+	).Replace(testList)
+	l = parse(t, changed)
+	hosts = append(hosts, Hostnames(l, 1)...)
+	diffs, err = Compare(context.Background(), l, x, hosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, d := range diffs {
+		got[d.Host] = d.String()
+	}
+	want := map[string]string{
+		"www.ck": `www.ck: parser says "www.ck" (ICANN), x/net says "ck" (ICANN)` +
+			"\n  matching rule \"*.ck\" (line 7)",
+		"api.ck": `api.ck: parser says "ck" (ICANN), x/net says "api.ck" (ICANN)` +
+			"\n  matching rule \"!api.ck\" (line 7)",
+		"charlie.xn--p1ai": `charlie.xn--p1ai: parser says "xn--p1ai" (ICANN), x/net says "charlie.xn--p1ai" (private)` +
+			"\n  matching rule \"xn--p1ai\" (line 11)",
+		"charlie.com": `charlie.com: parser says "charlie.com" (private), x/net says "com" (ICANN)` +
+			"\n  matching rule \"com\" (line 4)" +
+			"\n  matching rule \"charlie.com\" (line 22)",
+	}
+	// Other hostnames under the changed rules disagree as well, only
+	// check a representative set.
+	for host := range got {
+		if _, ok := want[host]; !ok {
+			delete(got, host)
+		}
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("wrong disagreements (-got+want):\n%s", diff)
+	}
+}
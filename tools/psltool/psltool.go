@@ -8,9 +8,14 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net/http"
+	"net/mail"
+	"net/url"
 	"os"
+	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"syscall"
@@ -21,9 +26,14 @@ import (
 	"github.com/creachadair/flax"
 	"github.com/creachadair/mds/mdiff"
 	"github.com/natefinch/atomic"
+	"github.com/publicsuffix/list/tools/internal/domain"
 	"github.com/publicsuffix/list/tools/internal/githistory"
 	"github.com/publicsuffix/list/tools/internal/github"
+	"github.com/publicsuffix/list/tools/internal/lsp"
 	"github.com/publicsuffix/list/tools/internal/parser"
+	"github.com/publicsuffix/list/tools/internal/patch"
+	"github.com/publicsuffix/list/tools/internal/serve"
+	"github.com/publicsuffix/list/tools/internal/xnetdiff"
 )
 
 func main() {
@@ -37,117 +47,1267 @@ func main() {
 			{
 				Name:  "fmt",
 				Usage: "<path>",
-				Help: `Format a PSL file.
+				Help: `Format a PSL file or source tree.
 
-By default, the given file is updated in place.`,
+By default, the given file is updated in place.
+
+With -m, only the parts of the file that need reordering or rewriting
+are changed, and the whitespace and line endings of everything else
+are left as they are.
+
+If path is a source tree directory (see 'psltool help split'), each
+file of the tree is rewritten into its canonical form and location.
+-m is not supported for source trees.`,
 				SetFlags: command.Flags(flax.MustBind, &fmtArgs),
 				Run:      command.Adapt(runFmt),
 			},
+			{
+				Name:  "add",
+				Usage: "--entity <name> [--url <url>] [--contact <name and email>] <suffix>...",
+				Help: `Add suffixes to the private domains section of a PSL file.
+
+The suffixes are added to the suffix block owned by the given entity,
+which is created if it doesn't exist yet. Suffixes can be domain names
+or wildcards like "*.example.com".
+
+The --url and --contact flags can be repeated. If the entity's block
+already exists, the given URLs and contacts are added to its header.
+URLs must use https, as required by 'psltool validate'.
+
+The PSL file is not changed if any of the suffixes are already in
+the list.`,
+				SetFlags: command.Flags(flax.MustBind, &addArgs),
+				Run:      command.Adapt(runAdd),
+			},
+			{
+				Name:  "remove",
+				Usage: "[--entity <name>] [<suffix>...]",
+				Help: `Remove suffixes from a PSL file.
+
+Suffixes can be domain names, wildcards like "*.example.com", or
+wildcard exceptions like "!www.example.com". Suffix blocks that no
+longer contain any suffixes are removed entirely. With --entity, the
+whole suffix block owned by that entity is removed.
+
+A preview of the removed entries is printed before the file is
+changed. The PSL file is not changed if any of the suffixes are not
+in the list.`,
+				SetFlags: command.Flags(flax.MustBind, &removeArgs),
+				Run:      command.Adapt(runRemove),
+			},
+			{
+				Name:  "migrate-headers",
+				Usage: "<path>",
+				Help: `Rewrite suffix block headers into the structured header format.
+
+Only the private domains section is migrated. Headers that can be
+converted without losing information are rewritten in place, with
+any free text kept as a single note. Blocks whose headers need human
+attention are listed, and left unchanged.`,
+				SetFlags: command.Flags(flax.MustBind, &migrateHeadersArgs),
+				Run:      command.Adapt(runMigrateHeaders),
+			},
+			{
+				Name:  "merge",
+				Usage: "<base> <ours> <theirs>",
+				Help: `Merge changes to a PSL file from two branches.
+
+Performs a three-way merge of the changes from base to theirs into
+ours, and writes the result to ours. The merge works on the parsed
+structure of the files: suffix blocks are matched by entity name and
+suffixes by domain, so that independent additions to the same part of
+the file merge cleanly. The result is formatted as with psltool fmt.
+
+Conflict markers are only written for true semantic conflicts, such as
+maintainer information edited on both sides, or a suffix moved to
+different suffix blocks on each side. If there are conflicts, merge
+exits with an error.
+
+If any of the files fail to parse, merge falls back to git's textual
+merge.
+
+To use merge as a git merge driver, configure it in .git/config:
+
+  [merge "psl"]
+    name = PSL merge driver
+    driver = psltool merge %O %A %B
+
+and select it in .gitattributes:
+
+  public_suffix_list.dat merge=psl`,
+				Run: command.Adapt(runMerge),
+			},
+			{
+				Name:  "explain",
+				Usage: "[--psl <path>] [--overlay <path>] <hostname>",
+				Help: `Explain how the PSL algorithm finds the public suffix of a hostname.
+
+Every rule that matches the hostname is listed with its location and
+suffix block, along with whether it determines the public suffix. A
+rule loses if a wildcard exception matches, because exceptions take
+priority over all other rules, or if another rule produces a longer
+public suffix. If no rule matches, the implicit "*" rule makes the
+hostname's TLD its public suffix.
+
+With --overlay, the given overlay files are applied to the PSL file
+first, as with psltool flatten.`,
+				SetFlags: command.Flags(flax.MustBind, &explainArgs),
+				Run:      command.Adapt(runExplain),
+			},
+			{
+				Name:  "export",
+				Usage: "[--section <name>] [--tld <tld>] [--entity <name>] [--exclude-entity <name>] [--wildcards] <path>",
+				Help: `Print a subset of a PSL file.
+
+The output is a valid, formatted PSL file containing only the selected
+parts of the input. All selector flags except --wildcards can be
+repeated, and each selector narrows the output further:
+
+  --section         keep only the named sections, "icann" or "private"
+  --tld             keep only rules under the given top-level domains
+  --entity          keep only the suffix blocks of the given entities
+  --exclude-entity  drop the suffix blocks of the given entities
+  --wildcards       keep only wildcard rules
+
+Section markers and the header comments of the retained suffix blocks
+are kept. Entity names are matched case-insensitively. The owner of a
+managed group, such as "Amazon", selects or drops all of the group's
+suffix blocks.`,
+				SetFlags: command.Flags(flax.MustBind, &exportArgs),
+				Run:      command.Adapt(runExport),
+			},
+			{
+				Name:  "flatten",
+				Usage: "<path> [<overlay>...]",
+				Help: `Apply overlay files to a PSL file, and print the combined list.
+
+An overlay is a file in PSL syntax with local changes to the list.
+Suffix blocks in its ICANN DOMAINS and PRIVATE DOMAINS sections are
+added to the list, and entries in its REMOVED DOMAINS section are
+removed from the list:
+
+  // ===BEGIN PRIVATE DOMAINS===
+
+  // Corp : https://corp.example.com
+  // Submitted by IT <it@corp.example.com>
+  *.corp.example.com
+
+  // ===END PRIVATE DOMAINS===
+
+  // ===BEGIN REMOVED DOMAINS===
+
+  blogspot.com
+
+  // ===END REMOVED DOMAINS===
+
+Overlays are applied in order. The combined list is formatted and
+validated as a whole, and is only written if it has no errors.
+Blocks added by overlays must pass the same checks as the rest of the
+list, except that their contacts may use reserved domains like
+example.com. Other checks that don't apply to a local addition can be
+suppressed with a psl:allow annotation, see 'psltool help validate'.`,
+				SetFlags: command.Flags(flax.MustBind, &flattenArgs),
+				Run:      command.Adapt(runFlatten),
+			},
+			{
+				Name:  "split",
+				Usage: "<path> <dir>",
+				Help: `Split a PSL file into a source tree in dir.
+
+A source tree holds the same list as a PSL file, with one file per
+suffix block instead of a single file:
+
+  00-comments.dat             comments outside of sections
+  01-icann-domains/           one directory per section
+     00/                      one directory per run of blocks
+        ac.dat                one file per suffix block, named after
+        ...                   its TLD or entity
+     01-idn-cctlds/
+        _comment.dat          comment that starts the run of blocks
+        00-xn--mgbaam7a8h.dat
+        ...                   IDN ccTLDs are sorted by hand, so the
+                              files of their run are numbered
+  02-private-domains/
+     00-note-these-are-in-alphabetical-order-by-company-name/
+        _comment.dat
+        amazon.dat            a managed group is kept in one file
+        ...
+
+Each file is in PSL syntax. Files are assembled in order of the
+numbers in their directory names and then by file name, and the
+result is sorted like any other PSL file, so new suffix blocks can be
+added as new files in the right run directory under any name. The
+exception is the numbered run of IDN ccTLDs, where a new file's name
+decides its position.
+
+The file must be formatted with psltool fmt first. dir must be empty,
+missing, or hold a source tree already. In an existing tree, files
+laid out like tree files that are no longer part of the tree are
+removed, and other files are left alone. The tree is checked to
+assemble back into exactly the original file.
+
+Run 'psltool build' to assemble a source tree into a PSL file.
+'psltool validate' and 'psltool fmt' accept either form.`,
+				Run: command.Adapt(runSplit),
+			},
+			{
+				Name:  "build",
+				Usage: "<dir>",
+				Help: `Assemble a source tree into a PSL file, and print it.
+
+See 'psltool help split' for the layout of source trees. The
+assembled list is formatted and validated as a whole, and is only
+written if it has no errors.`,
+				SetFlags: command.Flags(flax.MustBind, &buildArgs),
+				Run:      command.Adapt(runBuild),
+			},
+			{
+				Name: "lsp",
+				Help: `Run a Language Server Protocol server on stdin and stdout.
+
+The server reports parse, formatting and validation problems as
+diagnostics, formats files, and provides an outline of sections and
+suffix blocks, go-to-definition from suffixes to their block header,
+and hover information with the parsed maintainer information.`,
+				Run: command.Adapt(runLSP),
+			},
+			{
+				Name:  "serve",
+				Usage: "<path>",
+				Help: `Serve public suffix lookups for a PSL file over HTTP.
+
+Lookups use the same semantics as the rest of psltool. The file is
+checked for changes periodically, and reloaded atomically when it
+changes. If the new version fails to parse, the previous version
+keeps being served.
+
+With --overlay, the given overlay files are applied to the list, as
+with psltool flatten, and reloaded along with it. Matching rules
+report which overlay added them.
+
+Endpoints:
+
+  GET  /v1/public-suffix?host=H      public suffix of H
+  GET  /v1/registered-domain?host=H  registrable domain of H
+  GET  /v1/rules?host=H              both of the above, plus the
+                                     rules that match H
+  POST /v1/batch                     lookups for {"hosts": [...]},
+                                     with rules if "rules": true
+  GET  /v1/list                      version of the served file
+  GET  /healthz                      health check
+  GET  /metrics                      Prometheus metrics`,
+				SetFlags: command.Flags(flax.MustBind, &serveArgs),
+				Run:      command.Adapt(runServe),
+			},
 			{
 				Name:  "validate",
 				Usage: "<path or git commit hash>",
 				Help: `Check that a file is a valid PSL file.
 
-Validation includes basic issues like parse errors, as well as
-conformance with the PSL project's style rules and policies.
+Validation includes basic issues like parse errors, as well as
+conformance with the PSL project's style rules and policies.
+
+The argument can be either a local file, a source tree directory (see
+'psltool help split'), or a git commit hash to fetch from
+https://github.com/publicsuffix/list. Errors in a source tree are
+reported with the path of the file they are in. --fix is not
+supported for source trees.
+
+With --fix, problems that have a single safe fix are corrected in the
+local file, and each fix is listed. This covers files that are not
+UTF-8 or start with a byte order mark, misspelled section markers,
+suffixes that are not written in canonical form (e.g. uppercase or
+punycode), and suffixes repeated within one suffix block. Only the
+listed fixes are written: blocks are not reordered or reformatted,
+and formatting problems are still reported, as are all other
+problems.
+
+A suffix block can opt out of an individual check with an annotation
+in its header or before its suffixes, such as:
+
+  // psl:allow missing-email reason="Contact through the website only."
+
+Suppressed errors are not reported, unless --show-suppressed is given.
+Run 'psltool errors' for the names of checks that can be suppressed.
+Annotations need the approval of a PSL maintainer, so check-pr and
+check-local report every annotation that a change adds.`,
+				SetFlags: command.Flags(flax.MustBind, &validateArgs),
+				Run:      command.Adapt(runValidate),
+			},
+			{
+				Name:  "check-pr",
+				Usage: "<number>",
+				Help: `Validate an open PR on GitHub.
+
+Validation includes basic issues like parse errors, as well as
+conformance with the PSL project's style rules and policies.`,
+				SetFlags: command.Flags(flax.MustBind, &checkPRArgs),
+				Run:      command.Adapt(runCheckPR),
+			},
+			{
+				Name:  "check-local",
+				Usage: "[--base <git ref>]",
+				Help: `Validate local changes to a PSL file, before sending a PR.
+
+The PSL file is compared against the version at a git ref, by default
+the merge-base of HEAD and the main branch. Validations then run the
+same way as for check-pr, focusing on the suffix blocks that changed.`,
+				SetFlags: command.Flags(flax.MustBind, &checkLocalArgs),
+				Run:      command.Adapt(runCheckLocal),
+			},
+			{
+				Name:  "check-patch",
+				Usage: "<base path or git commit hash> <patch file>",
+				Help: `Validate a change to the PSL sent as a patch file.
+
+The patch, a unified diff like the output of "git diff" or "git
+format-patch", is applied to the base PSL file. The base can be either
+a local file, or a git commit hash to read from --gh-local-clone or
+fetch from https://github.com/publicsuffix/list.
+
+The patched file is not written anywhere. If the patch applies
+cleanly, validations run the same way as for check-pr, focusing on the
+suffix blocks that the patch changes.`,
+				SetFlags: command.Flags(flax.MustBind, &checkPatchArgs),
+				Run:      command.Adapt(runCheckPatch),
+			},
+			{
+				Name:  "errors",
+				Usage: "[<code>...]",
+				Help: `Explain the errors reported by validation.
+
+Every kind of validation error has a stable code, like "PSL0028
+missing-txt-record", which is shown next to the error. Without
+arguments, all codes are listed with a short summary. With arguments,
+which can be either code IDs or names, a long-form explanation of each
+error is printed, along with the reason for the PSL policy and how to
+fix the problem.`,
+				Run: command.Adapt(runErrors),
+			},
+			{
+				Name: "debug",
+				Commands: []*command.C{
+					{
+						Name:     "dump",
+						Usage:    "<path>",
+						Help:     "Print a debug dump of a PSL file.",
+						SetFlags: command.Flags(flax.MustBind, &debugDumpArgs),
+						Run:      command.Adapt(runDebugDump),
+					},
+					{
+						Name:  "xnet-diff",
+						Usage: "<path>",
+						Help: `Compare public suffix lookups against golang.org/x/net/publicsuffix.
+
+Builds x/net's public suffix implementation with a table generated
+from the PSL file, and asks both implementations for the public
+suffix of hostnames generated from every rule in the file, plus
+random prefixes, internationalized labels and unknown TLDs. Every
+disagreement is reported along with the rules that match the
+hostname.
+
+Requires a Go toolchain. The x/net module is taken from the local
+module cache if available.`,
+						SetFlags: command.Flags(flax.MustBind, &debugXNetDiffArgs),
+						Run:      command.Adapt(runDebugXNetDiff),
+					},
+				},
+			},
+
+			command.HelpCommand(nil),
+			command.VersionCommand(),
+		},
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	env := root.NewEnv(nil).SetContext(ctx).MergeFlags(true)
+	command.RunOrFail(env, os.Args[1:])
+}
+
+var fmtArgs struct {
+	Diff    bool `flag:"d,Output a diff of changes instead of rewriting the file"`
+	Minimal bool `flag:"m,Make minimal edits, preserving the layout of unchanged blocks"`
+}
+
+func runFmt(env *command.Env, path string) error {
+	if isDir(path) {
+		return fmtTree(env, path)
+	}
+
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Failed to read PSL file: %w", err)
+	}
+
+	parse := parser.Parse
+	if fmtArgs.Minimal {
+		parse = parser.ParseLossless
+	}
+	psl, parseErrs := parse(bs)
+	fmtErrs, _ := parser.SplitSuppressed(psl.Clean())
+
+	for _, err := range parseErrs {
+		fmt.Fprintln(env, err)
+	}
+	for _, err := range fmtErrs {
+		fmt.Fprintln(env, err)
+	}
+
+	clean := psl.MarshalPSL()
+	changed := !bytes.Equal(bs, clean)
+
+	if changed {
+		if fmtArgs.Diff {
+			lhs, rhs := strings.Split(string(bs), "\n"), strings.Split(string(clean), "\n")
+			diff := mdiff.New(lhs, rhs).AddContext(3)
+			mdiff.FormatUnified(os.Stdout, diff, &mdiff.FileInfo{
+				Left:  "a/" + path,
+				Right: "b/" + path,
+			})
+			return errors.New("File needs reformatting, rerun without -d to fix")
+		}
+		if len(parseErrs) > 0 {
+			return errors.New("Cannot reformat file due to parse errors")
+		}
+		if err := atomic.WriteFile(path, bytes.NewReader(clean)); err != nil {
+			return fmt.Errorf("Failed to reformat: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func fmtTree(env *command.Env, dir string) error {
+	if fmtArgs.Minimal {
+		return errors.New("-m is not supported for source trees")
+	}
+
+	psl, parseErrs := parser.ParseTree(dir, os.DirFS(dir))
+	fmtErrs, _ := parser.SplitSuppressed(psl.Clean())
+
+	for _, err := range parseErrs {
+		fmt.Fprintln(env, err)
+	}
+	for _, err := range fmtErrs {
+		fmt.Fprintln(env, err)
+	}
+
+	files, err := psl.MarshalTree()
+	if err != nil {
+		return err
+	}
+	changes, err := diffTree(dir, files)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	if fmtArgs.Diff {
+		for _, c := range changes {
+			diff := mdiff.New(treeLines(c.Old), treeLines(c.New)).AddContext(3)
+			mdiff.FormatUnified(os.Stdout, diff, &mdiff.FileInfo{
+				Left:  "a/" + c.Path,
+				Right: "b/" + c.Path,
+			})
+		}
+		return errors.New("Source tree needs reformatting, rerun without -d to fix")
+	}
+	if len(parseErrs) > 0 {
+		return errors.New("Cannot reformat source tree due to parse errors")
+	}
+	if err := applyTreeChanges(dir, changes); err != nil {
+		return fmt.Errorf("Failed to reformat: %w", err)
+	}
+	return nil
+}
+
+// stringList is a flag.Value that accumulates repeated flags.
+type stringList []string
+
+func (s *stringList) String() string     { return strings.Join(*s, ", ") }
+func (s *stringList) Set(v string) error { *s = append(*s, v); return nil }
+
+var addArgs struct {
+	PSL      string     `flag:"psl,default=public_suffix_list.dat,Path to the PSL file to edit"`
+	Entity   string     `flag:"entity,Name of the entity that owns the suffixes"`
+	URLs     stringList `flag:"url,URL of the entity's website (repeatable)"`
+	Contacts stringList `flag:"contact,Contact name and email, as \"Name <email>\" (repeatable)"`
+}
+
+func runAdd(env *command.Env, suffix string, more ...string) error {
+	if addArgs.Entity == "" {
+		return errors.New("--entity is required")
+	}
+	info := parser.MaintainerInfo{
+		Name: addArgs.Entity,
+	}
+	for _, s := range addArgs.URLs {
+		u, err := url.Parse(s)
+		if err != nil {
+			return fmt.Errorf("invalid URL %q", s)
+		} else if err := parser.CheckEntityURL(u); err != nil {
+			return fmt.Errorf("invalid URL %q: %w", s, err)
+		}
+		info.URLs = append(info.URLs, u)
+	}
+	for _, s := range addArgs.Contacts {
+		addr, err := mail.ParseAddress(s)
+		if err != nil {
+			return fmt.Errorf("invalid contact %q: %w", s, err)
+		}
+		info.Maintainers = append(info.Maintainers, addr)
+	}
+
+	bs, err := os.ReadFile(addArgs.PSL)
+	if err != nil {
+		return fmt.Errorf("Failed to read PSL file: %w", err)
+	}
+
+	psl, parseErrs := parser.ParseLossless(bs)
+	if len(parseErrs) > 0 {
+		for _, err := range parseErrs {
+			fmt.Fprintln(env, err)
+		}
+		return errors.New("Cannot add suffixes due to parse errors")
+	}
+
+	suffixes := append([]string{suffix}, more...)
+	block, err := psl.AddSuffixes(info, suffixes)
+	if err != nil {
+		return fmt.Errorf("Failed to add suffixes:\n%w", err)
+	}
+	cleanErrs, _ := parser.SplitSuppressed(psl.Clean())
+	for _, err := range cleanErrs {
+		fmt.Fprintln(env, err)
+	}
+
+	if err := atomic.WriteFile(addArgs.PSL, bytes.NewReader(psl.MarshalPSL())); err != nil {
+		return fmt.Errorf("Failed to write PSL file: %w", err)
+	}
+	if len(suffixes) == 1 {
+		fmt.Fprintf(env, "Added 1 suffix to the block for %q\n", block.Info.Name)
+	} else {
+		fmt.Fprintf(env, "Added %d suffixes to the block for %q\n", len(suffixes), block.Info.Name)
+	}
+	return nil
+}
+
+var removeArgs struct {
+	PSL    string `flag:"psl,default=public_suffix_list.dat,Path to the PSL file to edit"`
+	Entity string `flag:"entity,Name of an entity whose entire suffix block should be removed"`
+	DryRun bool   `flag:"n,Only print what would be removed, don't change the file"`
+}
+
+func runRemove(env *command.Env, suffixes ...string) error {
+	if removeArgs.Entity == "" && len(suffixes) == 0 {
+		return errors.New("nothing to remove, specify --entity or suffixes")
+	}
+
+	bs, err := os.ReadFile(removeArgs.PSL)
+	if err != nil {
+		return fmt.Errorf("Failed to read PSL file: %w", err)
+	}
+
+	psl, parseErrs := parser.ParseLossless(bs)
+	if len(parseErrs) > 0 {
+		for _, err := range parseErrs {
+			fmt.Fprintln(env, err)
+		}
+		return errors.New("Cannot remove suffixes due to parse errors")
+	}
+
+	if len(suffixes) > 0 {
+		removed, err := psl.RemoveSuffixes(suffixes)
+		if err != nil {
+			return fmt.Errorf("Failed to remove suffixes:\n%w", err)
+		}
+		for i, r := range removed {
+			if i == 0 || removed[i-1].Block != r.Block {
+				fmt.Fprintf(env, "From %q (%s):\n", r.Block.Info.Name, r.Block.LocationString())
+			}
+			fmt.Fprintf(env, "  - %s\n", r.Entry)
+			if r.BlockRemoved && (i == len(removed)-1 || removed[i+1].Block != r.Block) {
+				fmt.Fprintln(env, "  (block is now empty and is removed)")
+			}
+		}
+	}
+	if removeArgs.Entity != "" {
+		block, err := psl.RemoveEntity(removeArgs.Entity)
+		if err != nil {
+			return fmt.Errorf("Failed to remove entity: %w", err)
+		}
+		fmt.Fprintf(env, "Entire block for %q (%s):\n", block.Info.Name, block.LocationString())
+		for _, child := range block.Blocks {
+			switch v := child.(type) {
+			case *parser.Suffix:
+				fmt.Fprintf(env, "  - %s\n", v.Domain)
+			case *parser.Wildcard:
+				fmt.Fprintf(env, "  - *.%s\n", v.Domain)
+				for _, exc := range v.Exceptions {
+					fmt.Fprintf(env, "  - !%s.%s\n", exc, v.Domain)
+				}
+			}
+		}
+	}
+
+	if removeArgs.DryRun {
+		return nil
+	}
+	if err := atomic.WriteFile(removeArgs.PSL, bytes.NewReader(psl.MarshalPSL())); err != nil {
+		return fmt.Errorf("Failed to write PSL file: %w", err)
+	}
+	return nil
+}
+
+var migrateHeadersArgs struct {
+	Diff bool `flag:"d,Output a diff of changes instead of rewriting the file"`
+}
+
+func runMigrateHeaders(env *command.Env, path string) error {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Failed to read PSL file: %w", err)
+	}
+
+	psl, parseErrs := parser.ParseLossless(bs)
+	if len(parseErrs) > 0 {
+		for _, err := range parseErrs {
+			fmt.Fprintln(env, err)
+		}
+		return errors.New("Cannot migrate headers due to parse errors")
+	}
+
+	migrateErrs := psl.MigrateHeaders()
+	psl.Clean()
+	for _, err := range migrateErrs {
+		fmt.Fprintln(env, err)
+	}
+
+	migrated := psl.MarshalPSL()
+	if !bytes.Equal(bs, migrated) {
+		if migrateHeadersArgs.Diff {
+			lhs, rhs := strings.Split(string(bs), "\n"), strings.Split(string(migrated), "\n")
+			diff := mdiff.New(lhs, rhs).AddContext(3)
+			mdiff.FormatUnified(os.Stdout, diff, &mdiff.FileInfo{
+				Left:  "a/" + path,
+				Right: "b/" + path,
+			})
+		} else if err := atomic.WriteFile(path, bytes.NewReader(migrated)); err != nil {
+			return fmt.Errorf("Failed to migrate headers: %w", err)
+		}
+	}
+
+	if l := len(migrateErrs); l == 1 {
+		fmt.Fprintln(env, "1 suffix block needs manual attention")
+	} else if l > 1 {
+		fmt.Fprintf(env, "%d suffix blocks need manual attention\n", l)
+	}
+	return nil
+}
+
+var validateArgs struct {
+	Owner  string `flag:"gh-owner,default=publicsuffix,Owner of the github repository to check"`
+	Repo   string `flag:"gh-repo,default=list,Github repository to check"`
+	Clone  string `flag:"gh-local-clone,Path to a local clone of the repository specified by gh-owner/gh-repo"`
+	Online bool   `flag:"online-checks,Run validations that require querying third-party servers"`
+	Fix    bool   `flag:"fix,Apply safe fixes for mechanical problems, and rewrite the file"`
+
+	ShowSuppressed bool `flag:"show-suppressed,Also list errors suppressed by psl:allow annotations"`
+}
+
+func isHex(s string) bool {
+	for _, r := range s {
+		if !unicode.In(r, unicode.ASCII_Hex_Digit) {
+			return false
+		}
+	}
+	return true
+}
+
+func runMerge(env *command.Env, basePath, oursPath, theirsPath string) error {
+	var lists [3]*parser.List
+	for i, path := range []string{basePath, oursPath, theirsPath} {
+		bs, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read PSL file: %w", err)
+		}
+		psl, errs := parser.Parse(bs)
+		if len(errs) > 0 {
+			fmt.Fprintf(env, "%s has parse errors, falling back to textual merge:\n", path)
+			for _, err := range errs {
+				fmt.Fprintln(env, withCode(err))
+			}
+			return textMerge(basePath, oursPath, theirsPath)
+		}
+		lists[i] = psl
+	}
+
+	res := parser.Merge(lists[0], lists[1], lists[2])
+	cleanErrs, _ := parser.SplitSuppressed(res.List.Clean())
+	for _, err := range cleanErrs {
+		fmt.Fprintln(env, err)
+	}
+	if err := atomic.WriteFile(oursPath, bytes.NewReader(res.MarshalPSL())); err != nil {
+		return fmt.Errorf("failed to write merged file: %w", err)
+	}
+
+	for _, c := range res.Conflicts {
+		fmt.Fprintf(env, "Conflict: %s\n", c.Reason)
+	}
+	if l := len(res.Conflicts); l == 1 {
+		return errors.New("merge has 1 conflict")
+	} else if l > 1 {
+		return fmt.Errorf("merge has %d conflicts", l)
+	}
+	return nil
+}
+
+// textMerge merges the files with git merge-file, writing the result
+// to oursPath.
+func textMerge(basePath, oursPath, theirsPath string) error {
+	c := exec.Command("git", "merge-file", "-L", "ours", "-L", "base", "-L", "theirs", oursPath, basePath, theirsPath)
+	c.Stderr = os.Stderr
+	err := c.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return fmt.Errorf("textual merge has %d conflicts", exitErr.ExitCode())
+	}
+	return err
+}
+
+var explainArgs struct {
+	PSL      string     `flag:"psl,default=public_suffix_list.dat,Path to the PSL file"`
+	Overlays stringList `flag:"overlay,Overlay file to apply to the PSL file (repeatable)"`
+}
+
+func runExplain(env *command.Env, hostname string) error {
+	host, err := domain.Parse(hostname)
+	if err != nil {
+		return fmt.Errorf("invalid hostname %q: %w", hostname, err)
+	}
+	bs, err := os.ReadFile(explainArgs.PSL)
+	if err != nil {
+		return fmt.Errorf("Failed to read PSL file: %w", err)
+	}
+	psl, errs := parser.Parse(bs)
+	if len(errs) > 0 {
+		return fmt.Errorf("Failed to parse PSL file: %w", errors.Join(errs...))
+	}
+	for _, path := range explainArgs.Overlays {
+		bs, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("Failed to read overlay file: %w", err)
+		}
+		o, errs := parser.ParseOverlay(path, bs)
+		if len(errs) > 0 {
+			return fmt.Errorf("Failed to parse overlay file: %w", errors.Join(errs...))
+		}
+		if err := psl.ApplyOverlay(o); err != nil {
+			return err
+		}
+	}
+
+	// withASCII formats d, followed by its ASCII form if different.
+	withASCII := func(d domain.Name) string {
+		if a := d.ASCIIString(); a != d.String() {
+			return fmt.Sprintf("%s (%s)", d, a)
+		}
+		return d.String()
+	}
+
+	e := psl.Explain(host)
+	fmt.Fprintf(env, "Hostname: %s\n\n", withASCII(host))
+	if e.ImplicitRule {
+		fmt.Fprintf(env, "No rules match. The implicit \"*\" rule applies, so the public suffix\nis the TLD.\n")
+	} else {
+		fmt.Fprintf(env, "Matching rules:\n")
+	}
+	for _, r := range e.Rules {
+		fmt.Fprintf(env, "  %s\n", r.Text)
+		if r.IsException {
+			// Exceptions have no location of their own.
+			fmt.Fprintf(env, "    exception to the wildcard at %s", r.Rule.SrcRange().LocationString())
+		} else {
+			fmt.Fprintf(env, "    at %s", r.Rule.SrcRange().LocationString())
+		}
+		if r.Suffixes != nil {
+			fmt.Fprintf(env, ", in suffix block %q (%s)", r.Suffixes.Info.Name, r.Suffixes.SrcRange().LocationString())
+		}
+		if r.Section != nil {
+			fmt.Fprintf(env, ", %s", r.Section.Name)
+		}
+		fmt.Fprintf(env, "\n")
+		if r.Overlay != "" {
+			fmt.Fprintf(env, "    added by overlay %s\n", r.Overlay)
+		}
+		fmt.Fprintf(env, "    public suffix: %s\n", r.PublicSuffix)
+		if r.Prevailing {
+			fmt.Fprintf(env, "    prevails: %s\n", r.Reason)
+		} else {
+			fmt.Fprintf(env, "    lost: %s\n", r.Reason)
+		}
+	}
+	fmt.Fprintf(env, "\nPublic suffix: %s\n", withASCII(e.PublicSuffix))
+	if e.IsPublicSuffix() {
+		fmt.Fprintf(env, "Registrable domain: none, %s is a public suffix\n", host)
+	} else {
+		fmt.Fprintf(env, "Registrable domain: %s\n", withASCII(e.RegisteredDomain))
+	}
+	return nil
+}
+
+func runErrors(env *command.Env, codes ...string) error {
+	if len(codes) == 0 {
+		for _, e := range parser.Catalog() {
+			fmt.Fprintf(env, "%s  %-30s %s\n", e.ID, e.Name, e.Summary)
+		}
+		return nil
+	}
+
+	for i, c := range codes {
+		e, ok := parser.LookupCode(c)
+		if !ok {
+			return fmt.Errorf("unknown error code %q, run 'psltool errors' for a list of codes", c)
+		}
+		if i > 0 {
+			fmt.Fprintf(env, "\n")
+		}
+		fmt.Fprintf(env, "%s %s: %s\n\n", e.ID, e.Name, e.Summary)
+		fmt.Fprintf(env, "%s\n\n", wrapText(e.Explanation, "", 72))
+		fmt.Fprintf(env, "Why:\n%s\n\n", wrapText(e.Rationale, "  ", 72))
+		fmt.Fprintf(env, "How to fix:\n%s\n", wrapText(e.Fix, "  ", 72))
+	}
+	return nil
+}
+
+// withCode returns the message of err, followed by its error code if
+// it has one.
+func withCode(err error) string {
+	if code, ok := parser.ErrorCode(err); ok {
+		return fmt.Sprintf("%v [%s]", err, code)
+	}
+	return err.Error()
+}
+
+// printSuppressed prints errors suppressed by psl:allow annotations,
+// with the reason given for each suppression.
+func printSuppressed(env *command.Env, suppressed []parser.SuppressedError) {
+	if len(suppressed) == 0 {
+		return
+	}
+	fmt.Fprintf(env, "%d suppressed errors:\n", len(suppressed))
+	for _, s := range suppressed {
+		fmt.Fprintf(env, "  %s\n", withCode(s.Err))
+		fmt.Fprintf(env, "    suppressed at %s: %s\n", s.Suppression.LocationString(), s.Suppression.Reason)
+	}
+}
+
+// wrapText word-wraps s to lines of at most width columns, each
+// starting with indent.
+func wrapText(s, indent string, width int) string {
+	var (
+		ret  strings.Builder
+		line = indent
+	)
+	for _, w := range strings.Fields(s) {
+		if line != indent && len(line)+1+len(w) > width {
+			ret.WriteString(line)
+			ret.WriteString("\n")
+			line = indent
+		}
+		if line != indent {
+			line += " "
+		}
+		line += w
+	}
+	ret.WriteString(line)
+	return ret.String()
+}
+
+var exportArgs struct {
+	Sections        stringList `flag:"section,Section to keep (repeatable)"`
+	TLDs            stringList `flag:"tld,Top-level domain whose rules to keep (repeatable)"`
+	Entities        stringList `flag:"entity,Entity whose suffix block to keep (repeatable)"`
+	ExcludeEntities stringList `flag:"exclude-entity,Entity whose suffix block to drop (repeatable)"`
+	Wildcards       bool       `flag:"wildcards,Keep only wildcard rules"`
+	Output          string     `flag:"o,Write the subset to this file instead of stdout"`
+}
+
+func runExport(env *command.Env, path string) error {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Failed to read PSL file: %w", err)
+	}
+	psl, errs := parser.Parse(bs)
+	if len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Fprintln(env, withCode(err))
+		}
+		return errors.New("Cannot export file due to parse errors")
+	}
+
+	f := parser.Filter{
+		Entities:        exportArgs.Entities,
+		ExcludeEntities: exportArgs.ExcludeEntities,
+		WildcardsOnly:   exportArgs.Wildcards,
+	}
+	for _, name := range exportArgs.Sections {
+		name = strings.ToUpper(name)
+		if !strings.HasSuffix(name, " DOMAINS") {
+			name += " DOMAINS"
+		}
+		if !slices.ContainsFunc(parser.BlocksOfType[*parser.Section](psl), func(s *parser.Section) bool { return s.Name == name }) {
+			return fmt.Errorf("PSL file has no section %q", name)
+		}
+		f.Sections = append(f.Sections, name)
+	}
+	for _, tld := range exportArgs.TLDs {
+		label, err := domain.ParseLabel(strings.TrimPrefix(tld, "."))
+		if err != nil {
+			return fmt.Errorf("invalid TLD %q: %w", tld, err)
+		}
+		f.TLDs = append(f.TLDs, label)
+	}
+
+	subset := psl.Filter(f)
+	cleanErrs, _ := parser.SplitSuppressed(subset.Clean())
+	for _, err := range cleanErrs {
+		fmt.Fprintln(env, err)
+	}
+	out := subset.MarshalPSL()
+	if exportArgs.Output == "" {
+		_, err := os.Stdout.Write(out)
+		return err
+	}
+	if err := atomic.WriteFile(exportArgs.Output, bytes.NewReader(out)); err != nil {
+		return fmt.Errorf("Failed to write subset: %w", err)
+	}
+	return nil
+}
+
+var flattenArgs struct {
+	Output string `flag:"o,Write the combined list to this file instead of stdout"`
+}
+
+func runFlatten(env *command.Env, path string, overlays ...string) error {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Failed to read PSL file: %w", err)
+	}
+	psl, errs := parser.Parse(bs)
+	for _, path := range overlays {
+		bs, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("Failed to read overlay file: %w", err)
+		}
+		o, parseErrs := parser.ParseOverlay(path, bs)
+		if len(parseErrs) > 0 {
+			errs = append(errs, parseErrs...)
+			continue
+		}
+		if err := psl.ApplyOverlay(o); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	errs = append(errs, psl.Clean()...)
+	errs = append(errs, parser.ValidateOffline(psl)...)
+	errs, _ = parser.SplitSuppressed(errs)
+
+	for _, err := range errs {
+		fmt.Fprintln(env, withCode(err))
+	}
+	if l := len(errs); l == 1 {
+		return errors.New("combined list has 1 error")
+	} else if l > 1 {
+		return fmt.Errorf("combined list has %d errors", l)
+	}
+
+	out := psl.MarshalPSL()
+	if flattenArgs.Output == "" {
+		_, err := os.Stdout.Write(out)
+		return err
+	}
+	if err := atomic.WriteFile(flattenArgs.Output, bytes.NewReader(out)); err != nil {
+		return fmt.Errorf("Failed to write combined list: %w", err)
+	}
+	return nil
+}
+
+func runSplit(env *command.Env, path, dir string) error {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Failed to read PSL file: %w", err)
+	}
+	psl, errs := parser.Parse(bs)
+	if len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Fprintln(env, err)
+		}
+		return errors.New("Cannot split file due to parse errors")
+	}
+	psl.Clean()
+	if !bytes.Equal(psl.MarshalPSL(), bs) {
+		return errors.New("File needs reformatting, run 'psltool fmt' first")
+	}
+
+	if err := checkTreeDir(dir); err != nil {
+		return err
+	}
+	files, err := psl.MarshalTree()
+	if err != nil {
+		return err
+	}
+	changes, err := diffTree(dir, files)
+	if err != nil {
+		return err
+	}
+	if err := applyTreeChanges(dir, changes); err != nil {
+		return fmt.Errorf("Failed to write source tree: %w", err)
+	}
+
+	// Check that nothing was lost in the split.
+	tree, errs := parser.ParseTree(dir, os.DirFS(dir))
+	errs = append(errs, tree.Clean()...)
+	if len(errs) > 0 {
+		return fmt.Errorf("Source tree does not parse cleanly: %w", errors.Join(errs...))
+	}
+	if !bytes.Equal(tree.MarshalPSL(), bs) {
+		return fmt.Errorf("Source tree in %s does not assemble into %s", dir, path)
+	}
+	return nil
+}
+
+var buildArgs struct {
+	Output string `flag:"o,Write the assembled list to this file instead of stdout"`
+}
 
-The argument can be either a local file, or a git commit hash to fetch
-from https://github.com/publicsuffix/list.`,
-				SetFlags: command.Flags(flax.MustBind, &validateArgs),
-				Run:      command.Adapt(runValidate),
-			},
-			{
-				Name:  "check-pr",
-				Usage: "<number>",
-				Help: `Validate an open PR on GitHub.
+func runBuild(env *command.Env, dir string) error {
+	psl, errs := parser.ParseTree(dir, os.DirFS(dir))
+	errs = append(errs, psl.Clean()...)
+	errs = append(errs, parser.ValidateOffline(psl)...)
+	errs, _ = parser.SplitSuppressed(errs)
 
-Validation includes basic issues like parse errors, as well as
-conformance with the PSL project's style rules and policies.`,
-				SetFlags: command.Flags(flax.MustBind, &checkPRArgs),
-				Run:      command.Adapt(runCheckPR),
-			},
-			{
-				Name: "debug",
-				Commands: []*command.C{
-					{
-						Name:     "dump",
-						Usage:    "<path>",
-						Help:     "Print a debug dump of a PSL file.",
-						SetFlags: command.Flags(flax.MustBind, &debugDumpArgs),
-						Run:      command.Adapt(runDebugDump),
-					},
-				},
-			},
+	for _, err := range errs {
+		fmt.Fprintln(env, withCode(err))
+	}
+	if l := len(errs); l == 1 {
+		return errors.New("assembled list has 1 error")
+	} else if l > 1 {
+		return fmt.Errorf("assembled list has %d errors", l)
+	}
 
-			command.HelpCommand(nil),
-			command.VersionCommand(),
-		},
+	out := psl.MarshalPSL()
+	if buildArgs.Output == "" {
+		_, err := os.Stdout.Write(out)
+		return err
+	}
+	if err := atomic.WriteFile(buildArgs.Output, bytes.NewReader(out)); err != nil {
+		return fmt.Errorf("Failed to write assembled list: %w", err)
 	}
+	return nil
+}
 
-	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-	defer cancel()
-	env := root.NewEnv(nil).SetContext(ctx).MergeFlags(true)
-	command.RunOrFail(env, os.Args[1:])
+// checkTreeDir returns an error if dir exists and has files in it,
+// but does not hold a source tree. Writing a tree there would mix it
+// with unrelated files.
+func checkTreeDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	notTree := fmt.Errorf("Refusing to write a source tree to %s: directory is not empty and is not a source tree", dir)
+	// Any .dat file at the top of a tree is part of the list, so
+	// unrelated ones can't stay there.
+	for _, e := range entries {
+		if !e.IsDir() && filepath.Ext(e.Name()) == ".dat" && !parser.IsTreePath(e.Name()) {
+			return notTree
+		}
+	}
+	found := false
+	err = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			// Tree files are at most two directories deep.
+			if strings.Count(rel, "/") >= 2 {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if parser.IsTreePath(rel) {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !found {
+		return notTree
+	}
+	return nil
 }
 
-var fmtArgs struct {
-	Diff bool `flag:"d,Output a diff of changes instead of rewriting the file"`
+// isDir reports whether path is a directory.
+func isDir(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.IsDir()
 }
 
-func runFmt(env *command.Env, path string) error {
-	bs, err := os.ReadFile(path)
-	if err != nil {
-		return fmt.Errorf("Failed to read PSL file: %w", err)
+// treeLines returns the lines of a tree file for diffing. Missing
+// files have no lines.
+func treeLines(bs []byte) []string {
+	if len(bs) == 0 {
+		return nil
 	}
+	return strings.Split(string(bs), "\n")
+}
 
-	psl, parseErrs := parser.Parse(bs)
-	fmtErrs := psl.Clean()
+// A treeChange is a file of a source tree that needs rewriting.
+type treeChange struct {
+	Path string // path of the file, including the tree's directory
+	Old  []byte // current contents, or nil if the file is missing
+	New  []byte // new contents, or nil if the file should be removed
+}
 
-	for _, err := range parseErrs {
-		fmt.Fprintln(env, err)
+// diffTree returns the changes needed to make the source tree in dir
+// contain exactly files, as returned by List.MarshalTree. Files that
+// are not laid out like tree files, as reported by parser.IsTreePath,
+// are left alone.
+func diffTree(dir string, files map[string][]byte) ([]treeChange, error) {
+	var ret []treeChange
+	for name, bs := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		old, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		if err == nil && bytes.Equal(old, bs) {
+			continue
+		}
+		ret = append(ret, treeChange{path, old, bs})
 	}
-	for _, err := range fmtErrs {
-		fmt.Fprintln(env, err)
+
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if errors.Is(err, os.ErrNotExist) && path == dir {
+			return filepath.SkipDir
+		} else if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !parser.IsTreePath(rel) {
+			return nil
+		}
+		if _, ok := files[rel]; ok {
+			return nil
+		}
+		old, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		ret = append(ret, treeChange{path, old, nil})
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	clean := psl.MarshalPSL()
-	changed := !bytes.Equal(bs, clean)
+	slices.SortFunc(ret, func(a, b treeChange) int { return strings.Compare(a.Path, b.Path) })
+	return ret, nil
+}
 
-	if changed {
-		if fmtArgs.Diff {
-			lhs, rhs := strings.Split(string(bs), "\n"), strings.Split(string(clean), "\n")
-			diff := mdiff.New(lhs, rhs).AddContext(3)
-			mdiff.FormatUnified(os.Stdout, diff, &mdiff.FileInfo{
-				Left:  "a/" + path,
-				Right: "b/" + path,
-			})
-			return errors.New("File needs reformatting, rerun without -d to fix")
+// applyTreeChanges writes changes to the source tree in root, and
+// removes directories below root left empty by removed files.
+func applyTreeChanges(root string, changes []treeChange) error {
+	for _, c := range changes {
+		if c.New != nil {
+			continue
 		}
-		if len(parseErrs) > 0 {
-			return errors.New("Cannot reformat file due to parse errors")
+		if err := os.Remove(c.Path); err != nil {
+			return err
 		}
-		if err := atomic.WriteFile(path, bytes.NewReader(clean)); err != nil {
-			return fmt.Errorf("Failed to reformat: %w", err)
+		// Remove directories that are now empty, failing silently
+		// at the first one that isn't.
+		for dir := filepath.Dir(c.Path); isBelow(root, dir) && os.Remove(dir) == nil; dir = filepath.Dir(dir) {
+		}
+	}
+	for _, c := range changes {
+		if c.New == nil {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(c.Path), 0o755); err != nil {
+			return err
+		}
+		if err := atomic.WriteFile(c.Path, bytes.NewReader(c.New)); err != nil {
+			return err
 		}
 	}
-
 	return nil
 }
 
-var validateArgs struct {
-	Owner  string `flag:"gh-owner,default=publicsuffix,Owner of the github repository to check"`
-	Repo   string `flag:"gh-repo,default=list,Github repository to check"`
-	Clone  string `flag:"gh-local-clone,Path to a local clone of the repository specified by gh-owner/gh-repo"`
-	Online bool   `flag:"online-checks,Run validations that require querying third-party servers"`
+// isBelow reports whether path is inside the directory root, and not
+// root itself.
+func isBelow(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
 }
 
-func isHex(s string) bool {
-	for _, r := range s {
-		if !unicode.In(r, unicode.ASCII_Hex_Digit) {
-			return false
-		}
+func runLSP(env *command.Env) error {
+	return lsp.Serve(env.Context(), os.Stdin, os.Stdout)
+}
+
+var serveArgs struct {
+	Addr           string        `flag:"addr,default=localhost:8080,Address to listen on"`
+	ReloadInterval time.Duration `flag:"reload-interval,default=10s,How often to check the PSL file for changes"`
+	Overlays       stringList    `flag:"overlay,Overlay file to apply to the PSL file (repeatable)"`
+}
+
+func runServe(env *command.Env, path string) error {
+	srv, err := serve.New(path, serveArgs.Overlays...)
+	if err != nil {
+		return err
 	}
-	return true
+	go srv.Watch(env.Context(), serveArgs.ReloadInterval)
+
+	hs := &http.Server{
+		Addr:    serveArgs.Addr,
+		Handler: srv,
+	}
+	go func() {
+		<-env.Context().Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		hs.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("Serving %s (sha256 %s) on %s", path, srv.Info().SHA256, serveArgs.Addr)
+	if err := hs.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
 }
 
 func runValidate(env *command.Env, pathOrHash string) error {
@@ -159,8 +1319,11 @@ func runValidate(env *command.Env, pathOrHash string) error {
 		Repo:  checkPRArgs.Repo,
 	}
 
-	isPath := false
-	if _, err = os.Stat(pathOrHash); err == nil {
+	isPath, isTree := false, false
+	if fi, statErr := os.Stat(pathOrHash); statErr == nil && fi.IsDir() {
+		// input is a source tree
+		isPath, isTree = true, true
+	} else if statErr == nil {
 		// input is a local file
 		isPath = true
 		bs, err = os.ReadFile(pathOrHash)
@@ -174,7 +1337,33 @@ func runValidate(env *command.Env, pathOrHash string) error {
 		return fmt.Errorf("Failed to read PSL file %q: %w", pathOrHash, err)
 	}
 
-	psl, errs := parser.Parse(bs)
+	var (
+		psl      *parser.List
+		errs     []error
+		fixes    []parser.Fix
+		fixedSrc []byte
+	)
+	if validateArgs.Fix {
+		if !isPath {
+			return errors.New("--fix can only be used on a local file")
+		}
+		if isTree {
+			return errors.New("--fix is not supported for source trees")
+		}
+		// Fixes are applied to a lossless parse, so that the parts of
+		// the file that don't need fixing are written back unchanged.
+		fixed, srcFixes := parser.FixSource(bs)
+		psl, errs = parser.ParseLossless(fixed)
+		fixes = append(srcFixes, psl.ApplyFixes()...)
+		// Only the fixes get written. Clean below reorders blocks,
+		// which is left to psltool fmt.
+		fixedSrc = psl.MarshalPSL()
+	} else if isTree {
+		psl, errs = parser.ParseTree(pathOrHash, os.DirFS(pathOrHash))
+	} else {
+		psl, errs = parser.Parse(bs)
+	}
+	parseErrs := len(errs)
 	errs = append(errs, psl.Clean()...)
 	errs = append(errs, parser.ValidateOffline(psl)...)
 	if validateArgs.Online {
@@ -196,13 +1385,42 @@ func runValidate(env *command.Env, pathOrHash string) error {
 		errs = append(errs, parser.ValidateOnline(ctx, psl, &client, prHistory)...)
 	}
 
-	clean := psl.MarshalPSL()
-	if !bytes.Equal(bs, clean) {
-		errs = append(errs, errors.New("file needs reformatting, run 'psltool fmt' to fix"))
+	if isTree {
+		errs = append(errs, checkTreeFormat(pathOrHash, psl)...)
+	} else {
+		clean := psl.MarshalPSL()
+		if validateArgs.Fix {
+			if len(fixes) > 0 {
+				if parseErrs > 0 {
+					errs = append(errs, fmt.Errorf("cannot apply %d fixes due to parse errors", len(fixes)))
+				} else {
+					if err := atomic.WriteFile(pathOrHash, bytes.NewReader(fixedSrc)); err != nil {
+						return fmt.Errorf("Failed to write fixes: %w", err)
+					}
+					for _, fix := range fixes {
+						fmt.Fprintf(env, "Fixed: %s\n", fix)
+					}
+					bs = fixedSrc
+				}
+			}
+
+			// The file on disk keeps the original formatting of
+			// unfixed blocks, check it the same way as without --fix.
+			formatted, _ := parser.Parse(bs)
+			formatted.Clean()
+			clean = formatted.MarshalPSL()
+		}
+		if !bytes.Equal(bs, clean) {
+			errs = append(errs, errors.New("file needs reformatting, run 'psltool fmt' to fix"))
+		}
 	}
 
+	errs, suppressed := parser.SplitSuppressed(errs)
 	for _, err := range errs {
-		fmt.Fprintln(env, err)
+		fmt.Fprintln(env, withCode(err))
+	}
+	if validateArgs.ShowSuppressed {
+		printSuppressed(env, suppressed)
 	}
 
 	if l := len(errs); l == 0 {
@@ -215,11 +1433,31 @@ func runValidate(env *command.Env, pathOrHash string) error {
 	}
 }
 
+// checkTreeFormat reports the files of the source tree in dir that
+// differ from the canonical form of psl.
+func checkTreeFormat(dir string, psl *parser.List) []error {
+	files, err := psl.MarshalTree()
+	if err != nil {
+		return []error{err}
+	}
+	changes, err := diffTree(dir, files)
+	if err != nil {
+		return []error{err}
+	}
+	var ret []error
+	for _, c := range changes {
+		ret = append(ret, fmt.Errorf("%s: file needs reformatting, run 'psltool fmt' to fix", c.Path))
+	}
+	return ret
+}
+
 var checkPRArgs struct {
 	Owner  string `flag:"gh-owner,default=publicsuffix,Owner of the github repository to check"`
 	Repo   string `flag:"gh-repo,default=list,Github repository to check"`
 	Clone  string `flag:"gh-local-clone,Path to a local clone of the repository specified by gh-owner/gh-repo"`
 	Online bool   `flag:"online-checks,Run validations that require querying third-party servers"`
+
+	ShowSuppressed bool `flag:"show-suppressed,Also list errors suppressed by psl:allow annotations"`
 }
 
 func runCheckPR(env *command.Env, prStr string) error {
@@ -237,34 +1475,155 @@ func runCheckPR(env *command.Env, prStr string) error {
 		return err
 	}
 
-	before, _ := parser.Parse(withoutPR)
-	after, errs := parser.Parse(withPR)
-	after.SetBaseVersion(before, true)
-	errs = append(errs, after.Clean()...)
-	errs = append(errs, parser.ValidateOffline(after)...)
-	if checkPRArgs.Online {
-		var prHistory *githistory.History
-		if validateArgs.Clone != "" {
-			prHistory, err = githistory.GetPRInfo(validateArgs.Clone)
-			if err != nil {
-				return fmt.Errorf("failed to get local PR history: %w", err)
-			}
+	var prHistory *githistory.History
+	if checkPRArgs.Online && validateArgs.Clone != "" {
+		prHistory, err = githistory.GetPRInfo(validateArgs.Clone)
+		if err != nil {
+			return fmt.Errorf("failed to get local PR history: %w", err)
+		}
+	}
+
+	return checkChange(env, withoutPR, withPR, checkPRArgs.Online, checkPRArgs.ShowSuppressed, &client, prHistory)
+}
+
+var checkLocalArgs struct {
+	PSL        string `flag:"psl,default=public_suffix_list.dat,Path to the PSL file to check"`
+	Base       string `flag:"base,Git ref to compare against (default: merge-base of HEAD and --main-branch)"`
+	MainBranch string `flag:"main-branch,default=master,Name of the main branch of the repository"`
+	Owner      string `flag:"gh-owner,default=publicsuffix,Owner of the github repository, for online checks"`
+	Repo       string `flag:"gh-repo,default=list,Github repository, for online checks"`
+	Online     bool   `flag:"online-checks,Run validations that require querying third-party servers"`
+
+	ShowSuppressed bool `flag:"show-suppressed,Also list errors suppressed by psl:allow annotations"`
+}
+
+func runCheckLocal(env *command.Env) error {
+	withChange, err := os.ReadFile(checkLocalArgs.PSL)
+	if err != nil {
+		return fmt.Errorf("Failed to read PSL file: %w", err)
+	}
+
+	gitPath := filepath.Dir(checkLocalArgs.PSL)
+	base := checkLocalArgs.Base
+	if base == "" {
+		base, err = githistory.MergeBase(gitPath, "HEAD", checkLocalArgs.MainBranch)
+		if err != nil {
+			return fmt.Errorf("failed to find merge-base with %q, use --base to pick a ref to compare against: %w", checkLocalArgs.MainBranch, err)
+		}
+	}
+	withoutChange, err := githistory.GetPSL(gitPath, base)
+	if err != nil {
+		return fmt.Errorf("failed to read PSL file at %q: %w", base, err)
+	}
+	fmt.Fprintf(env, "Comparing %s against %s\n\n", checkLocalArgs.PSL, base)
+
+	client := github.Repo{
+		Owner: checkLocalArgs.Owner,
+		Repo:  checkLocalArgs.Repo,
+	}
+	var prHistory *githistory.History
+	if checkLocalArgs.Online {
+		prHistory, err = githistory.GetPRInfo(gitPath)
+		if err != nil {
+			return fmt.Errorf("failed to get local PR history: %w", err)
+		}
+	}
+
+	return checkChange(env, withoutChange, withChange, checkLocalArgs.Online, checkLocalArgs.ShowSuppressed, &client, prHistory)
+}
+
+var checkPatchArgs struct {
+	Owner  string `flag:"gh-owner,default=publicsuffix,Owner of the github repository to check"`
+	Repo   string `flag:"gh-repo,default=list,Github repository to check"`
+	Clone  string `flag:"gh-local-clone,Path to a local clone of the repository specified by gh-owner/gh-repo"`
+	Online bool   `flag:"online-checks,Run validations that require querying third-party servers"`
+
+	ShowSuppressed bool `flag:"show-suppressed,Also list errors suppressed by psl:allow annotations"`
+}
+
+func runCheckPatch(env *command.Env, pathOrHash, patchPath string) error {
+	client := github.Repo{
+		Owner: checkPatchArgs.Owner,
+		Repo:  checkPatchArgs.Repo,
+	}
+
+	var withoutPatch []byte
+	var err error
+	if _, err = os.Stat(pathOrHash); err == nil {
+		withoutPatch, err = os.ReadFile(pathOrHash)
+	} else if isHex(pathOrHash) && checkPatchArgs.Clone != "" {
+		withoutPatch, err = githistory.GetPSL(checkPatchArgs.Clone, pathOrHash)
+	} else if isHex(pathOrHash) {
+		withoutPatch, err = client.PSLForHash(env.Context(), pathOrHash)
+	} else {
+		return fmt.Errorf("Failed to read PSL file %q, not a local file or a git commit hash", pathOrHash)
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to read PSL file %q: %w", pathOrHash, err)
+	}
+
+	patchText, err := os.ReadFile(patchPath)
+	if err != nil {
+		return fmt.Errorf("Failed to read patch: %w", err)
+	}
+	files, err := patch.Parse(patchText)
+	if err != nil {
+		return fmt.Errorf("Failed to parse patch %q: %w", patchPath, err)
+	}
+	var pslPatch *patch.File
+	for _, f := range files {
+		if len(files) == 1 || f.Matches("public_suffix_list.dat") {
+			pslPatch = f
+			break
+		}
+	}
+	if pslPatch == nil {
+		return fmt.Errorf("patch %q does not change public_suffix_list.dat", patchPath)
+	}
+	withPatch, err := pslPatch.Apply(withoutPatch)
+	if err != nil {
+		return fmt.Errorf("patch %q does not apply to %s: %w", patchPath, pathOrHash, err)
+	}
+	fmt.Fprintf(env, "Applied %d hunks from %s to %s\n\n", len(pslPatch.Hunks), patchPath, pathOrHash)
+
+	var prHistory *githistory.History
+	if checkPatchArgs.Online && checkPatchArgs.Clone != "" {
+		prHistory, err = githistory.GetPRInfo(checkPatchArgs.Clone)
+		if err != nil {
+			return fmt.Errorf("failed to get local PR history: %w", err)
 		}
+	}
+
+	return checkChange(env, withoutPatch, withPatch, checkPatchArgs.Online, checkPatchArgs.ShowSuppressed, &client, prHistory)
+}
 
+// checkChange runs change-aware validations on the PSL file after,
+// using before as the base version, and prints a report of what was
+// checked and what problems were found. Errors suppressed by psl:allow
+// annotations are only listed if showSuppressed is true.
+func checkChange(env *command.Env, before, after []byte, online, showSuppressed bool, client *github.Repo, prHistory *githistory.History) error {
+	base, _ := parser.Parse(before)
+	psl, errs := parser.Parse(after)
+	psl.SetBaseVersion(base, true)
+	errs = append(errs, psl.Clean()...)
+	errs = append(errs, parser.ValidateOffline(psl)...)
+	errs = append(errs, parser.ValidateNewSuppressions(psl, base)...)
+	errs = append(errs, parser.ValidateNewEntityURLs(psl, base)...)
+	if online {
 		ctx, cancel := context.WithTimeout(env.Context(), 300*time.Second)
 		defer cancel()
-		errs = append(errs, parser.ValidateOnline(ctx, after, &client, prHistory)...)
+		errs = append(errs, parser.ValidateOnline(ctx, psl, client, prHistory)...)
 	}
 
-	clean := after.MarshalPSL()
-	if !bytes.Equal(withPR, clean) {
+	clean := psl.MarshalPSL()
+	if !bytes.Equal(after, clean) {
 		errs = append(errs, errors.New("file needs reformatting, run 'psltool fmt' to fix"))
 	}
 
 	// Print the blocks marked changed, so a human can check that
 	// something was actually checked by validations.
 	var changed []*parser.Suffixes
-	for _, block := range parser.BlocksOfType[*parser.Suffixes](after) {
+	for _, block := range parser.BlocksOfType[*parser.Suffixes](psl) {
 		if block.Changed() {
 			changed = append(changed, block)
 		}
@@ -279,12 +1638,17 @@ func runCheckPR(env *command.Env, prStr string) error {
 	}
 	io.WriteString(env, "\n")
 
+	errs, suppressed := parser.SplitSuppressed(errs)
 	if len(errs) > 0 {
 		for _, err := range errs {
-			fmt.Fprintln(env, err)
+			fmt.Fprintln(env, withCode(err))
 		}
 		io.WriteString(env, "\n")
 	}
+	if showSuppressed && len(suppressed) > 0 {
+		printSuppressed(env, suppressed)
+		io.WriteString(env, "\n")
+	}
 
 	if l := len(errs); l == 0 {
 		fmt.Fprintln(env, "PSL change is valid")
@@ -324,10 +1688,50 @@ func runDebugDump(env *command.Env, path string) error {
 	}
 
 	for _, err := range errs {
-		fmt.Fprintln(env, err)
+		fmt.Fprintln(env, withCode(err))
 	}
 
 	bs = dumpFn(psl)
 	os.Stdout.Write(bs)
 	return nil
 }
+
+var debugXNetDiffArgs struct {
+	Seed uint64 `flag:"seed,default=1,Seed for generating random hostnames"`
+}
+
+func runDebugXNetDiff(env *command.Env, path string) error {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read PSL file: %w", err)
+	}
+
+	psl, errs := parser.Parse(bs)
+	if len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Fprintln(env, withCode(err))
+		}
+		return errors.New("cannot compare a PSL file with parse errors")
+	}
+
+	xnet, err := xnetdiff.BuildXNet(env.Context(), bs)
+	if err != nil {
+		return err
+	}
+	defer xnet.Close()
+
+	hosts := xnetdiff.Hostnames(psl, debugXNetDiffArgs.Seed)
+	diffs, err := xnetdiff.Compare(env.Context(), psl, xnet, hosts)
+	if err != nil {
+		return err
+	}
+	for _, d := range diffs {
+		fmt.Println(d)
+	}
+
+	fmt.Printf("Compared %d hostnames, found %d disagreements\n", len(hosts), len(diffs))
+	if len(diffs) > 0 {
+		return errors.New("implementations disagree")
+	}
+	return nil
+}